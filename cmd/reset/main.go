@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -11,14 +12,25 @@ import (
 	"time"
 
 	"code88reset/internal/account"
+	"code88reset/internal/api"
 	"code88reset/internal/app"
+	"code88reset/internal/auth"
+	"code88reset/internal/backup"
+	"code88reset/internal/cli"
 	"code88reset/internal/config"
 	appconfig "code88reset/internal/config"
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
+	"code88reset/internal/notify"
+	"code88reset/internal/report"
+	"code88reset/internal/reset"
 	"code88reset/internal/storage"
 	"code88reset/internal/token"
 	"code88reset/internal/web"
+	"code88reset/internal/webapi"
 	"code88reset/pkg/logger"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -26,10 +38,10 @@ const (
 )
 
 var (
-	mode               = flag.String("mode", "web", "运行模式: web(Web管理模式), test(测试), run(自动调度器), list(列出历史账号)")
+	mode               = flag.String("mode", "web", "运行模式: web(Web管理模式), test(测试), run(自动调度器), list(列出历史账号), backup(备份数据), restore(恢复数据)")
 	apiKey             = flag.String("apikey", "", "API Key，支持单个或多个（逗号分隔），仅在run/test模式使用")
 	apiKeys            = flag.String("apikeys", "", "多个 API Keys（逗号分隔），与 -apikey 等效")
-	baseURL            = flag.String("baseurl", appconfig.DefaultBaseURL, "API Base URL")
+	baseURL            = flag.String("baseurl", "", "API Base URL，多个地址（镜像）用逗号分隔，按顺序故障切换；也可通过环境变量 API_BASE_URLS 设置，默认 "+appconfig.DefaultBaseURL)
 	dataDir            = flag.String("datadir", appconfig.DefaultDataDir, "数据目录")
 	logDir             = flag.String("logdir", appconfig.DefaultLogDir, "日志目录")
 	planNames          = flag.String("plans", "", "要重置的订阅计划名称（匹配 subscriptionName），多个用逗号分隔；留空表示所有 MONTHLY 套餐")
@@ -38,11 +50,43 @@ var (
 	creditThresholdMin = flag.Float64("threshold-min", 0, "额度下限百分比(0-100)，当额度<下限时才执行18点重置，0表示不使用下限")
 	enableFirstReset   = flag.Bool("first-reset", false, "是否启用18:55重置，默认关闭（仅run模式）")
 	webPort            = flag.Int("webport", 8966, "Web 服务器端口（仅web模式）")
+	webBind            = flag.String("webbind", "", "Web 服务器监听地址（如 127.0.0.1 只允许本机访问），也可通过环境变量 WEB_BIND 设置，默认监听全部网卡")
+	corsOrigins        = flag.String("cors-origins", "", "允许跨域访问管理 API 的来源（逗号分隔，* 表示任意来源），也可通过环境变量 WEB_CORS_ORIGINS 设置，默认只允许同源")
+	tlsCert            = flag.String("tls-cert", "", "Web 服务器 HTTPS 证书文件（PEM），也可通过环境变量 WEB_TLS_CERT 设置，文件更新后自动重新加载")
+	tlsKey             = flag.String("tls-key", "", "Web 服务器 HTTPS 私钥文件（PEM），也可通过环境变量 WEB_TLS_KEY 设置")
+	httpRedirectPort   = flag.Int("http-redirect-port", 0, "启用 HTTPS 时额外监听的 HTTP 端口，请求重定向到 HTTPS，也可通过环境变量 WEB_HTTP_REDIRECT_PORT 设置")
+	basePath           = flag.String("base-path", "", "Web 服务器 URL 前缀（如 /reset，用于反向代理子路径部署），也可通过环境变量 WEB_BASE_PATH 设置")
+	trustedProxies     = flag.String("trusted-proxies", "", "可信反向代理地址（IP 或 CIDR，逗号分隔），仅信任其转发的 X-Forwarded-For/X-Forwarded-Proto，也可通过环境变量 WEB_TRUSTED_PROXIES 设置")
+	backupFile         = flag.String("backup-file", "", "备份文件路径（backup/restore模式），backup 模式留空时自动生成文件名")
+	passphrase         = flag.String("passphrase", "", "备份加密口令（backup/restore模式），也可通过环境变量 BACKUP_PASSPHRASE 设置")
+	proxyURL           = flag.String("proxy", "", "访问 API 使用的代理（http://、https://、socks5://），也可通过环境变量 API_PROXY_URL 设置")
+	caFile             = flag.String("ca-file", "", "额外信任的 CA 证书文件（PEM），也可通过环境变量 API_CA_FILE 设置")
+	clientCert         = flag.String("client-cert", "", "客户端证书文件（PEM），也可通过环境变量 API_CLIENT_CERT 设置")
+	clientKey          = flag.String("client-key", "", "客户端私钥文件（PEM），也可通过环境变量 API_CLIENT_KEY 设置")
+	httpTimeout        = flag.Int("http-timeout", 0, "API 请求超时（秒），0 表示使用环境变量 API_TIMEOUT 或配置值")
+	userAgent          = flag.String("user-agent", "", "自定义 User-Agent，也可通过环境变量 API_USER_AGENT 设置")
+	extraHeaders       = flag.String("headers", "", "附加请求头，格式 Name=Value;Name2=Value2，也可通过环境变量 API_HEADERS 设置")
+	locale             = flag.String("locale", "", "默认语言（zh-CN 或 en），用于系统日志、命令行输出和未指定语言的 API 请求，也可通过环境变量 LOCALE 设置")
+	serverURL          = flag.String("server", "", "子命令连接的 Web 模式实例地址（如 http://127.0.0.1:8966），使用 WEB_ADMIN_TOKEN 认证；也可通过环境变量 WEB_SERVER_URL 设置，留空时直接操作本地 data 目录")
 )
 
+// endpoints 上游地址列表（主地址 + 镜像），所有 API 客户端共享故障切换状态
+var endpoints *api.EndpointPool
+
 func main() {
 	flag.Parse()
 
+	defaultLocale, localeErr := appconfig.GetLocale(*locale)
+	i18n.SetDefault(defaultLocale)
+	if localeErr != nil {
+		fmt.Fprintf(os.Stderr, "警告: %v，使用 %s\n", localeErr, defaultLocale)
+	}
+
+	// 带子命令时（如 reset token list）执行命令行管理，不进入运行模式
+	if flag.NArg() > 0 {
+		os.Exit(runCLI(flag.Args()))
+	}
+
 	if err := logger.Init(*logDir); err != nil {
 		fmt.Printf("初始化日志系统失败: %v\n", err)
 		os.Exit(1)
@@ -53,6 +97,11 @@ func main() {
 	logger.Info("========================================")
 	logger.Info("运行模式: %s", *mode)
 
+	baseURLs := initEndpoints()
+	if len(baseURLs) > 1 {
+		logger.Info("上游地址: %s（网络错误时按顺序切换）", strings.Join(baseURLs, ", "))
+	}
+
 	// 初始化存储
 	store, err := storage.NewStorage(*dataDir)
 	if err != nil {
@@ -65,13 +114,113 @@ func main() {
 		runWebMode(store)
 	case "test", "run", "list":
 		runLegacyMode(store)
+	case "backup", "restore":
+		runBackupMode(store)
 	default:
 		logger.Error("未知的运行模式: %s", *mode)
-		logger.Error("支持的模式: web, test, run, list")
+		logger.Error("支持的模式: web, test, run, list, backup, restore")
 		os.Exit(1)
 	}
 }
 
+// initEndpoints 解析上游地址列表（主地址 + 镜像）并创建共享的故障切换状态
+func initEndpoints() []string {
+	baseURLs := appconfig.GetBaseURLs(*baseURL)
+	*baseURL = baseURLs[0]
+	endpoints = api.NewEndpointPool(baseURLs)
+	return baseURLs
+}
+
+// runCLI 执行命令行管理子命令，返回进程退出码
+//
+// 指定了 Web 模式实例地址时通过其管理 API 操作，否则直接操作本地 data 目录。
+// 日志只写入文件，标准输出只包含命令结果，便于脚本解析。
+func runCLI(args []string) int {
+	var backend cli.Backend
+	if server, adminToken := appconfig.GetRemoteServer(*serverURL); server != "" {
+		if adminToken == "" {
+			fmt.Fprintln(os.Stderr, "错误: 连接 Web 模式实例需要设置环境变量 WEB_ADMIN_TOKEN")
+			return 1
+		}
+		backend = cli.NewRemoteBackend(webapi.NewClient(server, adminToken))
+	} else {
+		if err := logger.InitWithConsole(*logDir, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "初始化日志系统失败: %v\n", err)
+			return 1
+		}
+		initEndpoints()
+
+		local, err := newLocalBackend()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			return 1
+		}
+		backend = local
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := cli.New(backend, os.Stdout).Run(ctx, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, cli.ErrUsage):
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	default:
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return 1
+	}
+}
+
+// newLocalBackend 按 Web 模式相同的方式初始化存储和管理器，供子命令直接操作 data 目录
+func newLocalBackend() (*cli.LocalBackend, error) {
+	store, err := storage.NewStorage(*dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化存储失败: %w", err)
+	}
+
+	configMgr, err := config.NewDynamicConfigManager(*dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化配置管理器失败: %w", err)
+	}
+
+	tokenStorage, err := token.NewStorage(*dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化 Token 存储失败: %w", err)
+	}
+
+	tokenMgr := token.NewManager(tokenStorage, *baseURL, store)
+
+	usageStore, err := token.NewUsageStore(*dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化用量存储失败: %w", err)
+	}
+	tokenMgr.SetUsageStore(usageStore)
+
+	reportSvc, err := report.NewService(*dataDir)
+	if err != nil {
+		return nil, fmt.Errorf("初始化报告服务失败: %w", err)
+	}
+	tokenMgr.SetResetJournal(reportSvc.Journal())
+
+	cfg := configMgr.GetConfig()
+	opts, err := newClientOptions(cfg.HTTPClient)
+	if err != nil {
+		return nil, fmt.Errorf("API 网络配置无效: %w", err)
+	}
+	tokenMgr.SetClientOptions(opts)
+	store.SetSystemLogRetention(cfg.SystemLogRetention)
+	tokenMgr.SetAutoDisableAuthFailures(cfg.AutoDisableAuthFailures)
+	tokenMgr.SetResetVerification(cfg.ResetVerification)
+	if loc, err := time.LoadLocation(cfg.Timezone); err == nil {
+		tokenMgr.SetLocation(loc)
+	}
+
+	return cli.NewLocalBackend(tokenMgr, configMgr, store), nil
+}
+
 // runWebMode 运行 Web 管理模式
 func runWebMode(store *storage.Storage) {
 	logger.Info("启动 Web 管理模式...")
@@ -100,13 +249,65 @@ func runWebMode(store *storage.Storage) {
 
 	tokenMgr := token.NewManager(tokenStorage, *baseURL, store)
 
+	usageStore, err := token.NewUsageStore(*dataDir)
+	if err != nil {
+		logger.Error("初始化用量存储失败: %v", err)
+		os.Exit(1)
+	}
+	tokenMgr.SetUsageStore(usageStore)
+
+	reportSvc, err := report.NewService(*dataDir)
+	if err != nil {
+		logger.Error("初始化报告服务失败: %v", err)
+		os.Exit(1)
+	}
+	tokenMgr.SetResetJournal(reportSvc.Journal())
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+
+	// 后台订阅刷新器
+	refresher := token.NewRefresher(tokenMgr, configMgr.GetConfig().SubscriptionRefresh)
+	go refresher.Run(ctx)
+
+	// 通知渠道与订阅到期提醒
+	notifier := notify.NewDispatcher(configMgr.GetConfig().Notifications)
+	go runExpiryWatcher(ctx, tokenMgr, configMgr, notifier)
+	go runCreditWatcher(ctx, tokenMgr, configMgr, store, notifier)
+	go endpoints.Run(ctx, api.DefaultProbeInterval)
+	go runUsageSampler(ctx, tokenMgr, configMgr)
+	go runReportScheduler(ctx, reportSvc, configMgr, store, notifier)
+
+	// 应用动态配置并监听变更
+	applyDynamicConfig(configMgr.GetConfig(), store, tokenMgr, refresher, notifier)
+	go watchConfigChanges(configMgr, store, tokenMgr, refresher, notifier)
+
 	// 创建 Web 服务器
 	port := *webPort
 	if envPort := os.Getenv("WEB_PORT"); envPort != "" {
 		fmt.Sscanf(envPort, "%d", &port)
 	}
 
-	webServer := web.NewServer(port, tokenMgr, configMgr, store, adminToken, Version)
+	backupSvc := backup.NewService(tokenStorage, tokenMgr, store, configMgr, Version)
+
+	serviceTokens, err := auth.NewStore(*dataDir)
+	if err != nil {
+		logger.Error("初始化服务令牌存储失败: %v", err)
+		os.Exit(1)
+	}
+
+	webCfg := appconfig.GetWebServerConfig(models.WebServerConfig{
+		BindAddress:      *webBind,
+		Port:             port,
+		AllowedOrigins:   appconfig.ParseOrigins(*corsOrigins),
+		TLSCertFile:      *tlsCert,
+		TLSKeyFile:       *tlsKey,
+		HTTPRedirectPort: *httpRedirectPort,
+		BasePath:         *basePath,
+		TrustedProxies:   appconfig.ParseList(*trustedProxies),
+	})
+
+	webServer := web.NewServer(webCfg, tokenMgr, configMgr, store, backupSvc, reportSvc, serviceTokens, adminToken, Version)
 
 	// 启动 Web 服务器（在 goroutine 中）
 	go func() {
@@ -122,7 +323,7 @@ func runWebMode(store *storage.Storage) {
 	// 等待中断信号
 	logger.Info("========================================")
 	logger.Info("系统已启动")
-	logger.Info("Web 管理界面: http://localhost:%d", port)
+	logger.Info("Web 管理界面: %s", webServer.URL())
 	logger.Info("管理员Token: %s", adminToken)
 	logger.Info("按 Ctrl+C 停止")
 	logger.Info("========================================")
@@ -132,17 +333,297 @@ func runWebMode(store *storage.Storage) {
 	<-sigChan
 
 	logger.Info("\n正在关闭服务...")
+	stop()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := webServer.Stop(ctx); err != nil {
+	if err := webServer.Stop(shutdownCtx); err != nil {
 		logger.Error("Web 服务器关闭失败: %v", err)
 	}
 
 	logger.Info("服务已停止")
 }
 
+// httpClientConfig 合并 API 网络配置：命令行参数和环境变量优先于动态配置
+func httpClientConfig(base models.HTTPClientConfig) models.HTTPClientConfig {
+	return appconfig.MergeHTTPClientConfig(base, appconfig.GetHTTPClientConfig(models.HTTPClientConfig{
+		ProxyURL:       *proxyURL,
+		CAFile:         *caFile,
+		ClientCertFile: *clientCert,
+		ClientKeyFile:  *clientKey,
+		TimeoutSeconds: *httpTimeout,
+		UserAgent:      *userAgent,
+		Headers:        appconfig.ParseHeaders(*extraHeaders),
+	}))
+}
+
+// newClientOptions 创建 API 网络选项并接入共享的上游地址列表
+func newClientOptions(cfg models.HTTPClientConfig) (*api.ClientOptions, error) {
+	opts, err := api.NewClientOptions(httpClientConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	opts.Endpoints = endpoints
+	endpoints.SetProbeClient(opts.HTTPClient)
+	return opts, nil
+}
+
+// applyDynamicConfig 将动态配置应用到运行中的组件
+func applyDynamicConfig(cfg models.DynamicConfig, store *storage.Storage, tokenMgr *token.Manager, refresher *token.Refresher, notifier *notify.Dispatcher) {
+	if opts, err := newClientOptions(cfg.HTTPClient); err != nil {
+		logger.Error("API 网络配置无效，继续使用之前的配置: %v", err)
+	} else {
+		tokenMgr.SetClientOptions(opts)
+	}
+	api.DefaultCache.SetTTL(time.Duration(cfg.HTTPClient.CacheTTLSeconds) * time.Second)
+	store.SetSystemLogRetention(cfg.SystemLogRetention)
+	tokenMgr.SetAutoDisableAuthFailures(cfg.AutoDisableAuthFailures)
+	tokenMgr.SetResetVerification(cfg.ResetVerification)
+	if loc, err := time.LoadLocation(cfg.Timezone); err == nil {
+		tokenMgr.SetLocation(loc)
+	}
+	notifier.Configure(cfg.Notifications)
+	refresher.UpdateConfig(cfg.SubscriptionRefresh)
+}
+
+// watchConfigChanges 监听配置变更并实时应用
+func watchConfigChanges(configMgr *config.DynamicConfigManager, store *storage.Storage, tokenMgr *token.Manager, refresher *token.Refresher, notifier *notify.Dispatcher) {
+	updates := make(chan models.DynamicConfig, 1)
+	configMgr.Subscribe(updates)
+
+	for cfg := range updates {
+		applyDynamicConfig(cfg, store, tokenMgr, refresher, notifier)
+	}
+}
+
+// runExpiryWatcher 定期检查订阅到期情况并发送提醒
+func runExpiryWatcher(ctx context.Context, tokenMgr *token.Manager, configMgr *config.DynamicConfigManager, notifier *notify.Dispatcher) {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		cfg := configMgr.GetConfig().ExpiryAlert
+		if cfg.Enabled {
+			for _, alert := range tokenMgr.CheckExpiry(cfg.LeadDays) {
+				event, level := "subscription_expiring", "warning"
+				if alert.Expired {
+					event, level = "subscription_expired", "error"
+				}
+				notifier.Notify(notify.Notification{
+					Event:   event,
+					Level:   level,
+					Title:   i18n.T(i18n.Default(), "token.notify_title.expiry"),
+					Message: alert.Message(),
+					TokenID: alert.TokenID,
+				})
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCreditWatcher 在两次定时重置之间轮询剩余额度，低于阈值时提醒，按配置立即重置
+func runCreditWatcher(ctx context.Context, tokenMgr *token.Manager, configMgr *config.DynamicConfigManager, store *storage.Storage, notifier *notify.Dispatcher) {
+	updates := make(chan models.DynamicConfig, 1)
+	configMgr.Subscribe(updates)
+
+	for {
+		cfg := configMgr.GetConfig()
+		watch := cfg.CreditWatch
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if watch.Enabled && watch.IntervalMinutes > 0 {
+			timer = time.NewTimer(time.Duration(watch.IntervalMinutes) * time.Minute)
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-updates:
+			if timer != nil {
+				timer.Stop()
+			}
+			continue
+		case <-fire:
+		}
+
+		if _, err := tokenMgr.RefreshAll(cfg.SubscriptionRefresh.Concurrency); err != nil {
+			logger.Debug("低额度检查前刷新跳过: %v", err)
+		}
+
+		for _, alert := range tokenMgr.CheckCredits(watch) {
+			event, level := "low_credit", "warning"
+			if alert.Recovered {
+				event, level = "low_credit_recovered", "info"
+			}
+			notifier.Notify(notify.Notification{
+				Event:   event,
+				Level:   level,
+				Title:   i18n.T(i18n.Default(), "token.notify_title.low_credit"),
+				Message: alert.Message(),
+				TokenID: alert.TokenID,
+			})
+
+			if watch.AutoReset && !alert.Recovered && alert.ResetEligible {
+				resetLowCreditToken(tokenMgr, store, alert)
+			}
+		}
+	}
+}
+
+// runUsageSampler 定期采样各 Token 的积分用量，并清理超过保留天数的数据
+func runUsageSampler(ctx context.Context, tokenMgr *token.Manager, configMgr *config.DynamicConfigManager) {
+	updates := make(chan models.DynamicConfig, 1)
+	configMgr.Subscribe(updates)
+
+	var lastPrune time.Time
+	for {
+		sampling := configMgr.GetConfig().UsageSampling
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if sampling.Enabled && sampling.IntervalMinutes > 0 {
+			timer = time.NewTimer(time.Duration(sampling.IntervalMinutes) * time.Minute)
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-updates:
+			if timer != nil {
+				timer.Stop()
+			}
+			continue
+		case <-fire:
+		}
+
+		sampled, failed := tokenMgr.SampleUsage()
+		logger.Debug("用量采样完成: 成功 %d, 失败 %d", sampled, failed)
+
+		if time.Since(lastPrune) >= 24*time.Hour {
+			tokenMgr.PruneUsage(sampling.RetentionDays)
+			lastPrune = time.Now()
+		}
+	}
+}
+
+// runReportScheduler 按配置的时间生成日报/周报，按配置发送到通知渠道，并清理超过保留天数的报告
+func runReportScheduler(ctx context.Context, reports *report.Service, configMgr *config.DynamicConfigManager, store *storage.Storage, notifier *notify.Dispatcher) {
+	updates := make(chan models.DynamicConfig, 1)
+	configMgr.Subscribe(updates)
+
+	for {
+		cfg := configMgr.GetConfig()
+		at, periods := config.NextReport(cfg, time.Now())
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if len(periods) > 0 {
+			timer = time.NewTimer(time.Until(at))
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-updates:
+			if timer != nil {
+				timer.Stop()
+			}
+			continue
+		case <-fire:
+		}
+
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			loc = time.Local
+		}
+
+		// 以计划时间作为报告截止时间，相邻两份报告的时间范围首尾相接
+		for _, period := range periods {
+			rep, err := reports.Generate(period, at, loc)
+			if err != nil {
+				logger.Error("生成报告失败: %s - %v", period, err)
+				store.AddSystemMessage("error", i18n.M("report.log.failed", i18n.Label("report.period."+period), err))
+				continue
+			}
+			store.AddSystemMessage("info", i18n.M("report.log.generated",
+				rep.Name, rep.Totals.Succeeded, rep.Totals.Skipped, rep.Totals.Failed, rep.Totals.Pending))
+
+			if cfg.Reports.Notify {
+				notifyReport(notifier, rep, loc)
+			}
+		}
+
+		if err := reports.Prune(at.AddDate(0, 0, -cfg.Reports.RetentionDays)); err != nil {
+			logger.Warn("清理过期报告失败: %v", err)
+		}
+	}
+}
+
+// notifyReport 将报告的 Markdown 版本发送到通知渠道
+func notifyReport(notifier *notify.Dispatcher, rep *models.ResetReport, loc *time.Location) {
+	data, err := report.Render(rep, report.FormatMarkdown, i18n.Default(), loc)
+	if err != nil {
+		logger.Error("渲染报告失败: %s - %v", rep.Name, err)
+		return
+	}
+
+	level := "info"
+	if rep.Totals.Failed > 0 {
+		level = "warning"
+	}
+	notifier.Notify(notify.Notification{
+		Event:   "reset_report",
+		Level:   level,
+		Title:   i18n.T(i18n.Default(), "report.notify_title."+rep.Period),
+		Message: string(data),
+	})
+}
+
+// resetLowCreditToken 对低额度的 Token 立即执行一次重置
+func resetLowCreditToken(tokenMgr *token.Manager, store *storage.Storage, alert token.CreditAlert) {
+	if err := store.AcquireLock("low_credit_reset"); err != nil {
+		logger.Warn("无法获取锁，跳过低额度立即重置: %v", err)
+		return
+	}
+	defer store.ReleaseLock()
+
+	runID := uuid.New().String()
+	addRunLog(store, "info", i18n.M("token.log.low_credit_reset",
+		alert.Name, fmt.Sprintf("%.1f", alert.Threshold)), alert.TokenID, runID)
+
+	if _, err := tokenMgr.ResetTokenWithRunID(alert.TokenID, reset.ResetTypeLowCredit, alert.Threshold, runID); err != nil {
+		logger.Error("低额度立即重置失败: %s - %v", alert.Name, err)
+		addRunLog(store, "error", i18n.M("token.log.low_credit_reset_failed", alert.Name, err), alert.TokenID, runID)
+	}
+}
+
+// addRunLog 记录关联 Token 和执行批次的系统日志
+func addRunLog(store *storage.Storage, logType string, msg i18n.Message, tokenID, runID string) {
+	entry := models.SystemLog{Type: logType, TokenID: tokenID, RunID: runID}
+	entry.SetMessage(msg)
+	store.AddSystemLogEntry(entry)
+}
+
 // runTokenBasedScheduler 基于 Token 管理器运行调度器
 func runTokenBasedScheduler(tokenMgr *token.Manager, configMgr *config.DynamicConfigManager, store *storage.Storage) {
 	logger.Info("启动定时重置调度器...")
@@ -177,7 +658,7 @@ func checkAndExecuteReset(tokenMgr *token.Manager, configMgr *config.DynamicConf
 		logger.Info("========================================")
 		logger.Info("触发第一次重置任务")
 		logger.Info("========================================")
-		executeTokenReset(tokenMgr, "first", cfg.FirstReset.ThresholdPercent, store)
+		executeTokenReset(tokenMgr, "first", cfg.FirstReset, store)
 		return
 	}
 
@@ -186,13 +667,13 @@ func checkAndExecuteReset(tokenMgr *token.Manager, configMgr *config.DynamicConf
 		logger.Info("========================================")
 		logger.Info("触发第二次重置任务")
 		logger.Info("========================================")
-		executeTokenReset(tokenMgr, "second", cfg.SecondReset.ThresholdPercent, store)
+		executeTokenReset(tokenMgr, "second", cfg.SecondReset, store)
 		return
 	}
 }
 
 // executeTokenReset 执行 Token 重置
-func executeTokenReset(tokenMgr *token.Manager, resetType string, threshold float64, store *storage.Storage) {
+func executeTokenReset(tokenMgr *token.Manager, resetType string, schedule models.ResetConfig, store *storage.Storage) {
 	// 获取锁
 	operation := fmt.Sprintf("%s_reset", resetType)
 	if err := store.AcquireLock(operation); err != nil {
@@ -216,34 +697,41 @@ func executeTokenReset(tokenMgr *token.Manager, resetType string, threshold floa
 		status.SecondResetToday = false
 	}
 
-	if resetType == "first" && status.FirstResetToday {
-		logger.Info("今天已执行过第一次重置，跳过")
+	if eval := reset.EvaluateRun(resetType, status, schedule.DisabledRules); !eval.Allowed() {
+		logger.Info("%s，跳过", eval.Decisive.Message)
 		return
 	}
 
-	if resetType == "second" && status.SecondResetToday {
-		logger.Info("今天已执行过第二次重置，跳过")
-		return
+	// 获取所有启用的 Token，跳过订阅已到期的
+	tokens, expired := tokenMgr.ListResettableTokens()
+	for _, t := range expired {
+		logger.Info("跳过订阅已到期的 Token: %s", t.Name)
 	}
-
-	// 获取所有启用的 Token
-	tokens := tokenMgr.ListEnabledTokens()
 	if len(tokens) == 0 {
 		logger.Warn("没有启用的 Token")
 		return
 	}
 
-	logger.Info("开始重置 %d 个启用的 Token...", len(tokens))
+	runID := uuid.New().String()
+	logger.Info("开始重置 %d 个启用的 Token (批次: %s)...", len(tokens), runID)
+	addRunLog(store, "info", i18n.M("scheduler.run_started",
+		reset.ResetTypeLabel(resetType), len(tokens), len(expired)), "", runID)
 
 	successCount := 0
 	failCount := 0
 
-	for _, t := range tokens {
-		logger.Info("[%d/%d] 重置 Token: %s", successCount+failCount+1, len(tokens), t.Name)
+	for i, t := range tokens {
+		logger.Info("[%d/%d] 重置 Token: %s", i+1, len(tokens), t.Name)
 
-		updatedToken, err := tokenMgr.ResetToken(t.ID, resetType, threshold)
+		updatedToken, err := tokenMgr.ResetTokenWithRequest(t.ID, token.ResetRequest{
+			ResetType:        resetType,
+			ThresholdPercent: schedule.ThresholdPercent,
+			RunID:            runID,
+			DisabledRules:    schedule.DisabledRules,
+		})
 		if err != nil {
 			logger.Error("  重置失败: %v", err)
+			addRunLog(store, "error", i18n.M("token.log.reset_failed", t.Name, err), t.ID, runID)
 			failCount++
 			continue
 		}
@@ -253,6 +741,9 @@ func executeTokenReset(tokenMgr *token.Manager, resetType string, threshold floa
 				updatedToken.LastReset.BeforeCredits,
 				updatedToken.LastReset.AfterCredits)
 			successCount++
+		} else if updatedToken.LastReset != nil && updatedToken.LastReset.Pending() {
+			// 待核实的重置不计入失败，下次刷新订阅时重新核实
+			logger.Info("  ⏳ 重置待核实: %s", updatedToken.LastReset.Message)
 		} else {
 			logger.Warn("  ⚠️ 重置跳过: %s",
 				updatedToken.LastReset.Message)
@@ -263,6 +754,8 @@ func executeTokenReset(tokenMgr *token.Manager, resetType string, threshold floa
 	logger.Info("========================================")
 	logger.Info("重置完成: 成功 %d, 失败/跳过 %d", successCount, failCount)
 	logger.Info("========================================")
+	addRunLog(store, "info", i18n.M("scheduler.run_finished",
+		reset.ResetTypeLabel(resetType), successCount, failCount), "", runID)
 
 	// 更新状态
 	if resetType == "first" {
@@ -283,6 +776,68 @@ func executeTokenReset(tokenMgr *token.Manager, resetType string, threshold floa
 	}
 }
 
+// runBackupMode 在命令行中备份或恢复 data 目录
+func runBackupMode(store *storage.Storage) {
+	secret := *passphrase
+	if secret == "" {
+		secret = os.Getenv("BACKUP_PASSPHRASE")
+	}
+
+	configMgr, err := config.NewDynamicConfigManager(*dataDir)
+	if err != nil {
+		logger.Error("初始化配置管理器失败: %v", err)
+		os.Exit(1)
+	}
+
+	tokenStorage, err := token.NewStorage(*dataDir)
+	if err != nil {
+		logger.Error("初始化 Token 存储失败: %v", err)
+		os.Exit(1)
+	}
+
+	tokenMgr := token.NewManager(tokenStorage, *baseURL, store)
+	svc := backup.NewService(tokenStorage, tokenMgr, store, configMgr, Version)
+
+	switch *mode {
+	case "backup":
+		path := *backupFile
+		if path == "" {
+			path = fmt.Sprintf("backup_%s.json", time.Now().Format("20060102_150405"))
+		}
+
+		data, err := svc.Backup(secret)
+		if err != nil {
+			logger.Error("备份失败: %v", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			logger.Error("写入备份文件失败: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("备份完成: %s (加密=%v)", path, secret != "")
+
+	case "restore":
+		if *backupFile == "" {
+			logger.Error("恢复模式需要通过 -backup-file 指定备份文件")
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(*backupFile)
+		if err != nil {
+			logger.Error("读取备份文件失败: %v", err)
+			os.Exit(1)
+		}
+
+		logger.Warn("恢复将覆盖 %s 中的同名数据文件；如 Web 模式正在运行，请改用 POST /api/backup/restore", *dataDir)
+		result, err := svc.Restore(data, secret)
+		if err != nil {
+			logger.Error("恢复失败: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("恢复完成: %d 个文件 (备份时间: %s)", len(result.Files), result.BackupCreatedAt.Format("2006-01-02 15:04:05"))
+	}
+}
+
 // runLegacyMode 运行传统模式（兼容旧版本）
 func runLegacyMode(store *storage.Storage) {
 	// 解析配置
@@ -311,7 +866,14 @@ func runLegacyMode(store *storage.Storage) {
 	plans := appconfig.ParsePlans(*planNames)
 	keys := appconfig.GetAllAPIKeys(*apiKey, *apiKeys)
 
+	clientOpts, err := newClientOptions(appconfig.DefaultHTTPClient())
+	if err != nil {
+		logger.Error("API 网络配置无效: %v", err)
+		os.Exit(1)
+	}
+
 	accountMgr := account.NewManager(store, *baseURL)
+	accountMgr.SetClientOptions(clientOpts)
 
 	cfg := appconfig.Settings{
 		Mode:               *mode,
@@ -327,7 +889,28 @@ func runLegacyMode(store *storage.Storage) {
 		EnableFirstReset:   firstReset,
 	}
 
+	// 重置报告：调度器的重置结果写入事件日志，按动态配置生成日报/周报
+	configMgr, err := config.NewDynamicConfigManager(*dataDir)
+	if err != nil {
+		logger.Error("初始化配置管理器失败: %v", err)
+		os.Exit(1)
+	}
+	reportSvc, err := report.NewService(*dataDir)
+	if err != nil {
+		logger.Error("初始化报告服务失败: %v", err)
+		os.Exit(1)
+	}
+
 	application := app.New(cfg, store, accountMgr)
+	application.ClientOpts = clientOpts
+	application.Journal = reportSvc.Journal()
+
+	probeCtx, stopProbe := context.WithCancel(context.Background())
+	defer stopProbe()
+	go endpoints.Run(probeCtx, api.DefaultProbeInterval)
+	notifier := notify.NewDispatcher(configMgr.GetConfig().Notifications)
+	go runReportScheduler(probeCtx, reportSvc, configMgr, store, notifier)
+
 	if err := application.Run(); err != nil {
 		logger.Error("程序运行失败: %v", err)
 		os.Exit(1)
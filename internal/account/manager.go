@@ -12,8 +12,9 @@ import (
 
 // Manager 账号管理器
 type Manager struct {
-	storage *storage.Storage
-	baseURL string
+	storage    *storage.Storage
+	baseURL    string
+	clientOpts *api.ClientOptions // API 网络选项，为空时使用默认值
 }
 
 // NewManager 创建账号管理器
@@ -24,6 +25,11 @@ func NewManager(storage *storage.Storage, baseURL string) *Manager {
 	}
 }
 
+// SetClientOptions 设置创建 API 客户端时使用的网络选项
+func (m *Manager) SetClientOptions(opts *api.ClientOptions) {
+	m.clientOpts = opts
+}
+
 // SyncAccountsFromAPIKeys 从 API Keys 同步账号信息（持久化到配置文件）
 // 这个方法会：
 // 1. 为新的 API Key 创建账号记录
@@ -57,7 +63,7 @@ func (m *Manager) SyncAccountsFromAPIKeys(apiKeys []string, targetPlans []string
 		logger.Info("[%d/%d] 正在处理 API Key...", i+1, len(apiKeys))
 
 		// 创建临时客户端获取账号信息
-		client := api.NewClient(m.baseURL, apiKey, targetPlans)
+		client := api.NewClientWithOptions(m.baseURL, apiKey, targetPlans, m.clientOpts)
 		client.Storage = m.storage
 		accountConfig, err := client.GetAccountInfo()
 		if err != nil {
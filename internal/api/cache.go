@@ -0,0 +1,158 @@
+package api
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// DefaultCacheTTL 订阅列表缓存的默认有效期
+const DefaultCacheTTL = 10 * time.Second
+
+// cacheKeySeparator 缓存 key 中服务地址与 API Key 的分隔符
+const cacheKeySeparator = "\x00"
+
+// DefaultCache 所有客户端共享的订阅列表缓存，按 BaseURL + API Key 区分
+var DefaultCache = NewResponseCache(DefaultCacheTTL)
+
+// CacheStats 缓存统计
+type CacheStats struct {
+	TTLSeconds    float64 `json:"ttl_seconds"`
+	Entries       int     `json:"entries"`
+	Hits          int64   `json:"hits"`          // 直接使用缓存的次数
+	Coalesced     int64   `json:"coalesced"`     // 合并到进行中请求的次数
+	Upstream      int64   `json:"upstream"`      // 实际请求上游的次数
+	Invalidations int64   `json:"invalidations"` // 主动失效次数
+	Saved         int64   `json:"saved"`         // 节省的上游请求数（Hits + Coalesced）
+}
+
+// ResponseCache 短时缓存订阅列表，并合并同一 API Key 的并发请求
+type ResponseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+
+	hits          atomic.Int64
+	coalesced     atomic.Int64
+	upstream      atomic.Int64
+	invalidations atomic.Int64
+}
+
+type cacheEntry struct {
+	subs      []models.Subscription
+	fetchedAt time.Time
+	inflight  *cacheCall
+}
+
+type cacheCall struct {
+	done chan struct{}
+	subs []models.Subscription
+	err  error
+}
+
+// NewResponseCache 创建缓存，ttl <= 0 时不缓存结果，只合并并发请求
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{ttl: ttl, entries: make(map[string]*cacheEntry)}
+}
+
+// SetTTL 调整缓存有效期
+func (rc *ResponseCache) SetTTL(ttl time.Duration) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.ttl = ttl
+}
+
+// Invalidate 使指定 key 的缓存失效，进行中的请求结果也不会再写入缓存
+func (rc *ResponseCache) Invalidate(key string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, ok := rc.entries[key]; ok {
+		delete(rc.entries, key)
+		rc.invalidations.Add(1)
+	}
+}
+
+// InvalidateAPIKey 使某个 API Key 在所有服务地址下的缓存失效，用于 Token 被删除或上游设置变更后
+func (rc *ResponseCache) InvalidateAPIKey(apiKey string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for key := range rc.entries {
+		if strings.HasSuffix(key, cacheKeySeparator+apiKey) {
+			delete(rc.entries, key)
+			rc.invalidations.Add(1)
+		}
+	}
+}
+
+// Stats 返回缓存统计
+func (rc *ResponseCache) Stats() CacheStats {
+	rc.mu.Lock()
+	ttl, entries := rc.ttl, len(rc.entries)
+	rc.mu.Unlock()
+
+	hits, coalesced := rc.hits.Load(), rc.coalesced.Load()
+	return CacheStats{
+		TTLSeconds:    ttl.Seconds(),
+		Entries:       entries,
+		Hits:          hits,
+		Coalesced:     coalesced,
+		Upstream:      rc.upstream.Load(),
+		Invalidations: rc.invalidations.Load(),
+		Saved:         hits + coalesced,
+	}
+}
+
+// subscriptions 获取订阅列表：fresh=false 时优先使用未过期的缓存；同一 key 同时只有一个上游请求
+func (rc *ResponseCache) subscriptions(key string, fresh bool, fetch func() ([]models.Subscription, error)) ([]models.Subscription, error) {
+	rc.mu.Lock()
+	entry := rc.entries[key]
+	if entry == nil {
+		entry = &cacheEntry{}
+		rc.entries[key] = entry
+	}
+
+	if !fresh && entry.subs != nil && time.Since(entry.fetchedAt) < rc.ttl {
+		subs := cloneSubscriptions(entry.subs)
+		rc.mu.Unlock()
+		rc.hits.Add(1)
+		return subs, nil
+	}
+
+	if call := entry.inflight; call != nil {
+		rc.mu.Unlock()
+		rc.coalesced.Add(1)
+		<-call.done
+		return cloneSubscriptions(call.subs), call.err
+	}
+
+	call := &cacheCall{done: make(chan struct{})}
+	entry.inflight = call
+	rc.mu.Unlock()
+
+	rc.upstream.Add(1)
+	call.subs, call.err = fetch()
+
+	rc.mu.Lock()
+	entry.inflight = nil
+	if call.err == nil && rc.entries[key] == entry && rc.ttl > 0 {
+		entry.subs = call.subs
+		entry.fetchedAt = time.Now()
+	}
+	if entry.subs == nil && rc.entries[key] == entry {
+		delete(rc.entries, key)
+	}
+	rc.mu.Unlock()
+	close(call.done)
+
+	return cloneSubscriptions(call.subs), call.err
+}
+
+func cloneSubscriptions(subs []models.Subscription) []models.Subscription {
+	if subs == nil {
+		return nil
+	}
+	return append([]models.Subscription(nil), subs...)
+}
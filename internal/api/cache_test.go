@@ -0,0 +1,108 @@
+package api
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestResponseCache_HitsAndInvalidation(t *testing.T) {
+	cache := NewResponseCache(time.Minute)
+	calls := 0
+	fetch := func() ([]models.Subscription, error) {
+		calls++
+		return []models.Subscription{{ID: calls}}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		subs, err := cache.subscriptions("k", false, fetch)
+		if err != nil || len(subs) != 1 || subs[0].ID != 1 {
+			t.Fatalf("subscriptions() = %+v, %v", subs, err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", calls)
+	}
+
+	if subs, _ := cache.subscriptions("k", true, fetch); subs[0].ID != 2 {
+		t.Fatalf("expected fresh fetch to bypass cache, got %+v", subs)
+	}
+
+	cache.Invalidate("k")
+	if subs, _ := cache.subscriptions("k", false, fetch); subs[0].ID != 3 {
+		t.Fatalf("expected refetch after invalidation, got %+v", subs)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 2 || stats.Upstream != 3 || stats.Invalidations != 1 || stats.Saved != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestResponseCache_CoalescesConcurrentCalls(t *testing.T) {
+	cache := NewResponseCache(0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	fetch := func() ([]models.Subscription, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		close(started)
+		<-release
+		return []models.Subscription{{ID: 1}}, nil
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cache.subscriptions("k", false, fetch)
+	}()
+	<-started
+
+	const waiters = 5
+	for i := 0; i < waiters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if subs, err := cache.subscriptions("k", false, fetch); err != nil || len(subs) != 1 {
+				t.Errorf("subscriptions() = %+v, %v", subs, err)
+			}
+		}()
+	}
+
+	// 等待所有调用者挂到进行中的请求上
+	for cache.Stats().Coalesced < waiters {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected a single upstream call, got %d", calls)
+	}
+	if stats := cache.Stats(); stats.Entries != 0 {
+		t.Fatalf("expected nothing cached with ttl=0, got %+v", stats)
+	}
+}
+
+func TestResponseCache_InvalidateAPIKey(t *testing.T) {
+	cache := NewResponseCache(time.Minute)
+	fetch := func() ([]models.Subscription, error) { return []models.Subscription{{ID: 1}}, nil }
+	for _, key := range []string{
+		"https://a" + cacheKeySeparator + "key1",
+		"https://b" + cacheKeySeparator + "key1",
+		"https://a" + cacheKeySeparator + "key2",
+	} {
+		cache.subscriptions(key, false, fetch)
+	}
+
+	cache.InvalidateAPIKey("key1")
+	if stats := cache.Stats(); stats.Entries != 1 || stats.Invalidations != 2 {
+		t.Fatalf("unexpected stats after InvalidateAPIKey: %+v", stats)
+	}
+}
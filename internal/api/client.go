@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/pkg/logger"
 )
@@ -21,59 +22,164 @@ type Client struct {
 	TargetPlans []string // 目标订阅计划名称列表
 	Storage     interface {
 		SaveAPIResponse(endpoint, method string, requestBody, responseBody []byte, statusCode int) error
-		AddSystemLog(logType, message string) error
+		AddSystemMessage(logType string, msg i18n.Message) error
 	} // 存储接口，用于保存响应和系统日志
+	Cache     *ResponseCache    // 订阅列表缓存，为空时每次都请求上游
+	UserAgent string            // 自定义 User-Agent，留空使用 Go 默认值
+	Headers   map[string]string // 每个请求附加的请求头（不会覆盖认证头）
+	Endpoints *EndpointPool     // 上游地址列表（主地址 + 镜像），为空时只使用 BaseURL
 }
 
-// NewClient 创建新的 API 客户端
+// NewClient 创建新的 API 客户端（使用默认网络选项）
 func NewClient(baseURL, apiKey string, targetPlans []string) *Client {
-	return &Client{
+	return NewClientWithOptions(baseURL, apiKey, targetPlans, nil)
+}
+
+// NewClientWithOptions 使用指定网络选项创建 API 客户端，opts 为 nil 时使用默认选项
+func NewClientWithOptions(baseURL, apiKey string, targetPlans []string, opts *ClientOptions) *Client {
+	client := &Client{
 		BaseURL:     baseURL,
 		APIKey:      apiKey,
 		TargetPlans: targetPlans,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: DefaultTimeout,
 		},
+		Cache: DefaultCache,
+	}
+	if opts != nil {
+		if opts.HTTPClient != nil {
+			client.HTTPClient = opts.HTTPClient
+		}
+		client.UserAgent = opts.UserAgent
+		client.Headers = opts.Headers
+		client.Endpoints = opts.Endpoints
+	}
+	return client
+}
+
+// newRequest 创建带认证头和自定义请求头的请求
+func (c *Client) newRequest(method, url string, requestData []byte) (*http.Request, error) {
+	var reqBody io.Reader
+	if requestData != nil {
+		reqBody = bytes.NewReader(requestData)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	// 设置请求头 - 使用 Bearer 认证（适配管理后台 API）
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// cacheKey 缓存按服务地址和 API Key 区分
+func (c *Client) cacheKey() string {
+	return c.BaseURL + cacheKeySeparator + c.APIKey
+}
+
+// GetSubscriptions 获取订阅列表，短时间内的重复调用使用缓存
+func (c *Client) GetSubscriptions() ([]models.Subscription, error) {
+	if c.Cache == nil {
+		return c.fetchSubscriptions()
+	}
+	return c.Cache.subscriptions(c.cacheKey(), false, c.fetchSubscriptions)
+}
+
+// RefreshSubscriptions 跳过缓存获取最新的订阅列表，并更新缓存
+func (c *Client) RefreshSubscriptions() ([]models.Subscription, error) {
+	if c.Cache == nil {
+		return c.fetchSubscriptions()
+	}
+	return c.Cache.subscriptions(c.cacheKey(), true, c.fetchSubscriptions)
+}
+
+// GetSubscription 获取单个订阅（来自缓存的订阅列表），fresh 为 true 时跳过缓存
+func (c *Client) GetSubscription(id int, fresh bool) (*models.Subscription, error) {
+	get := c.GetSubscriptions
+	if fresh {
+		get = c.RefreshSubscriptions
+	}
+	subs, err := get()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subs {
+		if subs[i].ID == id {
+			return &subs[i], nil
+		}
+	}
+	return nil, i18n.Errorf("api.subscription_not_found", id)
+}
+
+// InvalidateCache 使该客户端的订阅列表缓存失效
+func (c *Client) InvalidateCache() {
+	if c.Cache != nil {
+		c.Cache.Invalidate(c.cacheKey())
 	}
 }
 
 // makeRequest 通用的 HTTP 请求方法
 func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	url := c.BaseURL + endpoint
-
-	var reqBody io.Reader
 	var requestData []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("序列化请求体失败: %w", err)
+			return nil, i18n.Errorf("api.marshal_request_failed", err)
 		}
 		requestData = jsonData
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequest(method, url, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+	// 配置了多个上游地址时，网络错误会按顺序切换地址重试
+	bases := []string{c.BaseURL}
+	if c.Endpoints != nil {
+		bases = c.Endpoints.candidates()
 	}
 
-	// 设置请求头 - 使用 Bearer 认证（适配管理后台 API）
-	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("Content-Type", "application/json")
+	var resp *http.Response
+	var err error
+	for i, base := range bases {
+		var req *http.Request
+		req, err = c.newRequest(method, base+endpoint, requestData)
+		if err != nil {
+			return nil, i18n.Errorf("api.create_request_failed", err)
+		}
 
-	logger.Debug("发起请求: %s %s", method, url)
+		logger.Debug("发起请求: %s %s", method, req.URL)
 
-	// 发送请求
-	resp, err := c.HTTPClient.Do(req)
+		// 发送请求
+		resp, err = c.HTTPClient.Do(req)
+		if c.Endpoints == nil {
+			break
+		}
+		if err == nil {
+			c.Endpoints.markSuccess(base)
+			break
+		}
+		c.Endpoints.markFailure(base, err)
+		if i == len(bases)-1 || !retryableOnOtherEndpoint(method, err) {
+			break
+		}
+		logger.Warn("上游地址 %s 请求失败: %v，切换到 %s", base, err, bases[i+1])
+	}
 	if err != nil {
-		return nil, fmt.Errorf("请求失败: %w", err)
+		return nil, i18n.Errorf("api.request_failed", err)
 	}
 	defer resp.Body.Close()
 
 	// 读取响应
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("读取响应失败: %w", err)
+		return nil, i18n.Errorf("api.read_response_failed", err)
 	}
 
 	logger.Debug("响应状态码: %d", resp.StatusCode)
@@ -93,9 +199,18 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte,
 			Type  string               `json:"type"`
 		}
 		if err := json.Unmarshal(respBody, &errorResp); err == nil && errorResp.Type == "error" {
-			return nil, fmt.Errorf("API错误 [%d]: %s", errorResp.Error.Code, errorResp.Error.Message)
+			return nil, &APIError{
+				StatusCode: resp.StatusCode,
+				Code:       errorResp.Error.Code,
+				Message:    errorResp.Error.Message,
+				text:       i18n.Errorf("api.upstream_error", errorResp.Error.Code, errorResp.Error.Message),
+			}
+		}
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Message:    string(respBody),
+			text:       i18n.Errorf("api.http_error", resp.StatusCode, string(respBody)),
 		}
-		return nil, fmt.Errorf("请求失败，状态码: %d, 响应: %s", resp.StatusCode, string(respBody))
 	}
 
 	return respBody, nil
@@ -107,13 +222,13 @@ func (c *Client) GetUsage() (*models.UsageResponse, error) {
 
 	// 记录 API 调用日志
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("info", "调用 88code API: 获取用量信息")
+		c.Storage.AddSystemMessage("info", i18n.M("api.log.usage_call"))
 	}
 
 	respBody, err := c.makeRequest("POST", "/api/usage", nil)
 	if err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 调用失败: 获取用量信息 - %v", err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.usage_failed", err))
 		}
 		return nil, err
 	}
@@ -121,32 +236,33 @@ func (c *Client) GetUsage() (*models.UsageResponse, error) {
 	var usage models.UsageResponse
 	if err := json.Unmarshal(respBody, &usage); err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 响应解析失败: 用量信息 - %v", err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.usage_parse_failed", err))
 		}
-		return nil, fmt.Errorf("解析用量响应失败: %w", err)
+		return nil, i18n.Errorf("api.parse_usage_failed", err)
 	}
 
 	logger.Info("用量信息获取成功: 当前积分=%.4f, 限制=%.2f", usage.CurrentCredits, usage.CreditLimit)
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("success", fmt.Sprintf("88code API 调用成功: 获取用量信息 (积分=%.4f/%.2f)", usage.CurrentCredits, usage.CreditLimit))
+		c.Storage.AddSystemMessage("success", i18n.M("api.log.usage_succeeded",
+			fmt.Sprintf("%.4f", usage.CurrentCredits), fmt.Sprintf("%.2f", usage.CreditLimit)))
 	}
 	return &usage, nil
 }
 
-// GetSubscriptions 获取所有订阅信息（使用管理后台 API）
-func (c *Client) GetSubscriptions() ([]models.Subscription, error) {
+// fetchSubscriptions 请求上游获取订阅列表
+func (c *Client) fetchSubscriptions() ([]models.Subscription, error) {
 	logger.Info("获取订阅列表...")
 
 	// 记录 API 调用日志
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("info", "调用 88code API: 获取订阅列表")
+		c.Storage.AddSystemMessage("info", i18n.M("api.log.subscriptions_call"))
 	}
 
 	// 使用管理后台 API 端点
 	respBody, err := c.makeRequest("GET", "/admin-api/cc-admin/system/subscription/my", nil)
 	if err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 调用失败: 获取订阅列表 - %v", err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.subscriptions_failed", err))
 		}
 		return nil, err
 	}
@@ -162,22 +278,27 @@ func (c *Client) GetSubscriptions() ([]models.Subscription, error) {
 
 	if err := json.Unmarshal(respBody, &adminResp); err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 响应解析失败: %v", err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.subscriptions_parse_failed", err))
 		}
-		return nil, fmt.Errorf("解析订阅列表失败: %w", err)
+		return nil, i18n.Errorf("api.parse_subscriptions_failed", err)
 	}
 
 	// 检查响应是否成功
 	if !adminResp.OK {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 返回错误: %s (错误码: %d)", adminResp.Msg, adminResp.Code))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.subscriptions_rejected", adminResp.Msg, adminResp.Code))
+		}
+		return nil, &APIError{
+			StatusCode: http.StatusOK,
+			Code:       adminResp.Code,
+			Message:    adminResp.Msg,
+			text:       i18n.Errorf("api.subscriptions_rejected", adminResp.Msg, adminResp.Code),
 		}
-		return nil, fmt.Errorf("获取订阅列表失败: %s (错误码: %d)", adminResp.Msg, adminResp.Code)
 	}
 
 	logger.Info("订阅列表获取成功，共 %d 个订阅", len(adminResp.Data))
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("success", fmt.Sprintf("88code API 调用成功: 获取到 %d 个订阅", len(adminResp.Data)))
+		c.Storage.AddSystemMessage("success", i18n.M("api.log.subscriptions_succeeded", len(adminResp.Data)))
 	}
 	return adminResp.Data, nil
 }
@@ -198,12 +319,7 @@ func (c *Client) GetTargetSubscription() (*models.Subscription, error) {
 
 		// 🚨 PAYGO 保护：永远不重置 PAYGO 类型订阅
 		// 检查套餐名称或 PlanType 是否为 PAYGO/PAY_PER_USE
-		isPAYGO := sub.SubscriptionName == "PAYGO" ||
-			sub.SubscriptionPlan.SubscriptionName == "PAYGO" ||
-			sub.SubscriptionPlan.PlanType == "PAYGO" ||
-			sub.SubscriptionPlan.PlanType == "PAY_PER_USE"
-
-		if isPAYGO {
+		if sub.IsPAYGO() {
 			logger.Error("🚨 检测到 PAYGO 订阅 (ID=%d, 名称=%s, 类型=%s)，已自动跳过",
 				sub.ID, sub.SubscriptionName, sub.SubscriptionPlan.PlanType)
 			logger.Error("🚨 PAYGO 订阅为按量付费，不应进行重置操作")
@@ -217,7 +333,7 @@ func (c *Client) GetTargetSubscription() (*models.Subscription, error) {
 		return &sub, nil
 	}
 
-	return nil, fmt.Errorf("未找到符合条件的目标订阅（目标计划: %v）", c.TargetPlans)
+	return nil, i18n.Errorf("api.target_subscription_not_found", fmt.Sprint(c.TargetPlans))
 }
 
 // GetFreeSubscription 获取 FREE 订阅信息（保留向后兼容）
@@ -232,45 +348,37 @@ func (c *Client) GetFreeSubscription() (*models.Subscription, error) {
 
 // ResetCredits 重置订阅积分
 func (c *Client) ResetCredits(subscriptionID int) (*models.ResetResponse, error) {
-	// 🚨 PAYGO 保护：二次确认，防止误重置 PAYGO 订阅
-	subscriptions, err := c.GetSubscriptions()
+	// 🚨 PAYGO 保护：二次确认，防止误重置 PAYGO 订阅（订阅类型不会变化，使用缓存即可）
+	sub, err := c.GetSubscription(subscriptionID, false)
 	if err != nil {
 		logger.Warn("无法验证订阅类型: %v，继续重置", err)
 	} else {
-		for _, sub := range subscriptions {
-			if sub.ID == subscriptionID {
-				// 检查是否为 PAYGO 类型
-				isPAYGO := sub.SubscriptionName == "PAYGO" ||
-					sub.SubscriptionPlan.SubscriptionName == "PAYGO" ||
-					sub.SubscriptionPlan.PlanType == "PAYGO" ||
-					sub.SubscriptionPlan.PlanType == "PAY_PER_USE"
-
-				if isPAYGO {
-					errMsg := fmt.Sprintf("🚨 拒绝重置：订阅 ID=%d (名称=%s, 类型=%s) 为 PAYGO 类型，不允许重置",
-						subscriptionID, sub.SubscriptionName, sub.SubscriptionPlan.PlanType)
-					if c.Storage != nil {
-						c.Storage.AddSystemLog("error", errMsg)
-					}
-					return nil, fmt.Errorf(errMsg)
-				}
-				logger.Debug("已验证订阅 ID=%d 类型=%s，允许重置", subscriptionID, sub.SubscriptionPlan.PlanType)
-				break
+		if sub.IsPAYGO() {
+			if c.Storage != nil {
+				c.Storage.AddSystemMessage("error", i18n.M("api.log.paygo_refused",
+					subscriptionID, sub.SubscriptionName, sub.SubscriptionPlan.PlanType))
 			}
+			return nil, i18n.Errorf("api.paygo_refused",
+				ErrPAYGOProtected, subscriptionID, sub.SubscriptionName, sub.SubscriptionPlan.PlanType)
 		}
+		logger.Debug("已验证订阅 ID=%d 类型=%s，允许重置", subscriptionID, sub.SubscriptionPlan.PlanType)
 	}
 
+	// 无论成功与否，重置请求发出后订阅状态都可能变化
+	defer c.InvalidateCache()
+
 	endpoint := fmt.Sprintf("/admin-api/cc-admin/system/subscription/my/reset-credits/%d", subscriptionID)
 	logger.Info("重置订阅积分: subscriptionID=%d", subscriptionID)
 
 	// 记录 API 调用日志
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("info", fmt.Sprintf("调用 88code API: 重置订阅额度 (订阅ID=%d)", subscriptionID))
+		c.Storage.AddSystemMessage("info", i18n.M("api.log.reset_call", subscriptionID))
 	}
 
 	respBody, err := c.makeRequest("POST", endpoint, nil)
 	if err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 调用失败: 重置订阅ID=%d - %v", subscriptionID, err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.reset_failed", subscriptionID, err))
 		}
 		return nil, err
 	}
@@ -284,9 +392,9 @@ func (c *Client) ResetCredits(subscriptionID int) (*models.ResetResponse, error)
 
 	if err := json.Unmarshal(respBody, &adminResp); err != nil {
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 响应解析失败: 订阅ID=%d - %v", subscriptionID, err))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.reset_parse_failed", subscriptionID, err))
 		}
-		return nil, fmt.Errorf("解析重置响应失败: %w", err)
+		return nil, i18n.Errorf("api.parse_reset_failed", err)
 	}
 
 	// 检查响应是否成功
@@ -294,19 +402,29 @@ func (c *Client) ResetCredits(subscriptionID int) (*models.ResetResponse, error)
 		// 检查特定的错误码
 		if adminResp.Code == 30001 {
 			if c.Storage != nil {
-				c.Storage.AddSystemLog("warning", fmt.Sprintf("88code API 重置受限: 订阅ID=%d - %s (今日已重置或时间间隔不足5小时)", subscriptionID, adminResp.Msg))
+				c.Storage.AddSystemMessage("warning", i18n.M("api.log.reset_limited", subscriptionID, adminResp.Msg))
+			}
+			return nil, &APIError{
+				StatusCode: http.StatusOK,
+				Code:       adminResp.Code,
+				Message:    adminResp.Msg,
+				text:       i18n.Errorf("api.reset_limited", adminResp.Msg),
 			}
-			return nil, fmt.Errorf("重置失败: %s (今日已重置或时间间隔不足5小时)", adminResp.Msg)
 		}
 		if c.Storage != nil {
-			c.Storage.AddSystemLog("error", fmt.Sprintf("88code API 重置失败: 订阅ID=%d - %s (错误码: %d)", subscriptionID, adminResp.Msg, adminResp.Code))
+			c.Storage.AddSystemMessage("error", i18n.M("api.log.reset_rejected", subscriptionID, adminResp.Msg, adminResp.Code))
+		}
+		return nil, &APIError{
+			StatusCode: http.StatusOK,
+			Code:       adminResp.Code,
+			Message:    adminResp.Msg,
+			text:       i18n.Errorf("api.reset_rejected", adminResp.Msg, adminResp.Code),
 		}
-		return nil, fmt.Errorf("重置失败: %s (错误码: %d)", adminResp.Msg, adminResp.Code)
 	}
 
 	logger.Info("重置成功: %s", adminResp.Msg)
 	if c.Storage != nil {
-		c.Storage.AddSystemLog("success", fmt.Sprintf("88code API 调用成功: 订阅ID=%d 重置完成 - %s", subscriptionID, adminResp.Msg))
+		c.Storage.AddSystemMessage("success", i18n.M("api.log.reset_succeeded", subscriptionID, adminResp.Msg))
 	}
 
 	// 构造兼容的返回格式
@@ -320,10 +438,10 @@ func (c *Client) ResetCredits(subscriptionID int) (*models.ResetResponse, error)
 func (c *Client) TestConnection() error {
 	logger.Info("测试 API 连接...")
 
-	// 改用 GetSubscriptions 测试连接（不再依赖 GetUsage）
-	_, err := c.GetSubscriptions()
+	// 改用订阅列表测试连接（不再依赖 GetUsage），跳过缓存
+	_, err := c.RefreshSubscriptions()
 	if err != nil {
-		return fmt.Errorf("连接测试失败: %w", err)
+		return i18n.Errorf("api.connection_test_failed", err)
 	}
 
 	logger.Info("API 连接测试成功")
@@ -335,11 +453,11 @@ func (c *Client) GetAccountInfo() (*models.AccountConfig, error) {
 	// 直接从订阅列表获取账号信息
 	subscriptions, err := c.GetSubscriptions()
 	if err != nil {
-		return nil, fmt.Errorf("获取账号信息失败: %w", err)
+		return nil, i18n.Errorf("api.account_info_failed", err)
 	}
 
 	if len(subscriptions) == 0 {
-		return nil, fmt.Errorf("未找到任何订阅信息")
+		return nil, i18n.Errorf("api.no_subscriptions")
 	}
 
 	// 从第一个订阅中提取账号信息
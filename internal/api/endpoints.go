@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultProbeInterval 上游地址健康探测的默认间隔
+const DefaultProbeInterval = time.Minute
+
+// EndpointInfo 单个上游地址的状态
+type EndpointInfo struct {
+	URL       string     `json:"url"`
+	Healthy   bool       `json:"healthy"`
+	Failures  int        `json:"failures"` // 连续失败次数
+	LastError string     `json:"last_error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// EndpointStatus 上游地址列表状态
+type EndpointStatus struct {
+	Current   string         `json:"current"`
+	Endpoints []EndpointInfo `json:"endpoints"`
+	Failovers int64          `json:"failovers"` // 切换地址的次数
+}
+
+// EndpointPool 有序的上游地址列表（主地址 + 镜像）
+//
+// 请求优先使用最近一次成功的地址，遇到网络错误时按顺序切换到下一个地址；
+// 后台探测只更新健康状态，当前地址不可用时才切换，不会主动切回主地址。
+type EndpointPool struct {
+	mu        sync.Mutex
+	endpoints []EndpointInfo
+	current   int
+	failovers int64
+	probe     *http.Client
+}
+
+// NewEndpointPool 创建上游地址列表，urls 按优先级排列，至少包含一个地址
+func NewEndpointPool(urls []string) *EndpointPool {
+	p := &EndpointPool{probe: &http.Client{Timeout: 10 * time.Second}}
+	for _, u := range urls {
+		p.endpoints = append(p.endpoints, EndpointInfo{URL: u, Healthy: true})
+	}
+	return p
+}
+
+// SetProbeClient 设置健康探测使用的 http.Client（与 API 请求使用相同的代理和证书）
+func (p *EndpointPool) SetProbeClient(client *http.Client) {
+	if client == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.probe = client
+}
+
+// Current 当前优先使用的地址
+func (p *EndpointPool) Current() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.endpoints[p.current].URL
+}
+
+// Status 返回各地址的状态
+func (p *EndpointPool) Status() EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return EndpointStatus{
+		Current:   p.endpoints[p.current].URL,
+		Endpoints: append([]EndpointInfo(nil), p.endpoints...),
+		Failovers: p.failovers,
+	}
+}
+
+// candidates 返回本次请求依次尝试的地址：当前地址优先，其余健康地址在前、不健康地址在后
+func (p *EndpointPool) candidates() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	urls := []string{p.endpoints[p.current].URL}
+	var unhealthy []string
+	for i, e := range p.endpoints {
+		if i == p.current {
+			continue
+		}
+		if e.Healthy {
+			urls = append(urls, e.URL)
+		} else {
+			unhealthy = append(unhealthy, e.URL)
+		}
+	}
+	return append(urls, unhealthy...)
+}
+
+// markSuccess 记录地址可用，并把它设为当前地址
+func (p *EndpointPool) markSuccess(url string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.indexOf(url)
+	if i < 0 {
+		return
+	}
+	now := time.Now()
+	p.endpoints[i].Healthy = true
+	p.endpoints[i].Failures = 0
+	p.endpoints[i].LastError = ""
+	p.endpoints[i].CheckedAt = &now
+	if i != p.current {
+		p.current = i
+		p.failovers++
+	}
+}
+
+// markFailure 记录地址不可用；若为当前地址，切换到下一个健康地址
+func (p *EndpointPool) markFailure(url string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.indexOf(url)
+	if i < 0 {
+		return
+	}
+	now := time.Now()
+	p.endpoints[i].Healthy = false
+	p.endpoints[i].Failures++
+	p.endpoints[i].LastError = err.Error()
+	p.endpoints[i].CheckedAt = &now
+
+	if i != p.current {
+		return
+	}
+	for offset := 1; offset < len(p.endpoints); offset++ {
+		next := (i + offset) % len(p.endpoints)
+		if p.endpoints[next].Healthy {
+			p.current = next
+			p.failovers++
+			return
+		}
+	}
+}
+
+func (p *EndpointPool) indexOf(url string) int {
+	for i, e := range p.endpoints {
+		if e.URL == url {
+			return i
+		}
+	}
+	return -1
+}
+
+// Probe 探测全部地址：能收到 HTTP 响应（非 5xx）即视为可用
+func (p *EndpointPool) Probe(ctx context.Context) {
+	p.mu.Lock()
+	client := p.probe
+	urls := make([]string, len(p.endpoints))
+	for i, e := range p.endpoints {
+		urls[i] = e.URL
+	}
+	p.mu.Unlock()
+
+	for _, url := range urls {
+		err := probeEndpoint(ctx, client, url)
+		if ctx.Err() != nil {
+			return
+		}
+
+		p.mu.Lock()
+		i := p.indexOf(url)
+		now := time.Now()
+		p.endpoints[i].CheckedAt = &now
+		if err == nil {
+			p.endpoints[i].Healthy = true
+			p.endpoints[i].Failures = 0
+			p.endpoints[i].LastError = ""
+		}
+		p.mu.Unlock()
+
+		if err != nil {
+			p.markFailure(url, err)
+		}
+	}
+}
+
+// Run 按间隔执行健康探测，直到 ctx 结束；只有一个地址时不探测
+func (p *EndpointPool) Run(ctx context.Context, interval time.Duration) {
+	if len(p.endpoints) < 2 {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultProbeInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Probe(ctx)
+		}
+	}
+}
+
+func probeEndpoint(ctx context.Context, client *http.Client, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url+"/", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return errors.New(resp.Status)
+	}
+	return nil
+}
+
+// retryableOnOtherEndpoint 判断请求失败后能否换地址重试：
+// GET 请求遇到任何网络错误都可重试；其他请求只有连接未建立（请求一定未发出）时才重试，避免重复重置
+func retryableOnOtherEndpoint(method string, err error) bool {
+	if method == http.MethodGet {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func newDeadURL(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	return url
+}
+
+func TestMakeRequestFailsOverToMirror(t *testing.T) {
+	var hits atomic.Int32
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"code":0,"ok":true,"data":[]}`))
+	}))
+	defer mirror.Close()
+
+	primary := newDeadURL(t)
+	pool := NewEndpointPool([]string{primary, mirror.URL})
+	client := NewClientWithOptions(primary, "key", nil, &ClientOptions{Endpoints: pool})
+	client.Cache = nil
+
+	if _, err := client.GetSubscriptions(); err != nil {
+		t.Fatalf("GetSubscriptions() error = %v", err)
+	}
+	status := pool.Status()
+	if status.Current != mirror.URL || status.Failovers != 1 {
+		t.Fatalf("status = %+v, want current=mirror failovers=1", status)
+	}
+	if status.Endpoints[0].Healthy || status.Endpoints[0].LastError == "" {
+		t.Fatalf("primary should be marked unhealthy: %+v", status.Endpoints[0])
+	}
+
+	// 后续请求直接使用最近一次成功的地址
+	if _, err := client.GetSubscriptions(); err != nil {
+		t.Fatalf("second GetSubscriptions() error = %v", err)
+	}
+	if hits.Load() != 2 || pool.Status().Failovers != 1 {
+		t.Fatalf("hits=%d failovers=%d, want 2 and 1", hits.Load(), pool.Status().Failovers)
+	}
+
+	// 连接失败的 POST 请求一定未发出，也可以切换
+	if !retryableOnOtherEndpoint(http.MethodPost, requestErr(t, primary)) {
+		t.Fatal("dial error should allow POST failover")
+	}
+}
+
+func requestErr(t *testing.T, url string) error {
+	t.Helper()
+	_, err := http.Post(url, "application/json", nil)
+	if err == nil {
+		t.Fatal("expected request to dead endpoint to fail")
+	}
+	return err
+}
+
+func TestProbeSwitchesAwayFromUnhealthyCurrent(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer healthy.Close()
+
+	pool := NewEndpointPool([]string{newDeadURL(t), healthy.URL})
+	pool.Probe(context.Background())
+
+	status := pool.Status()
+	if status.Current != healthy.URL {
+		t.Fatalf("current = %s, want %s", status.Current, healthy.URL)
+	}
+	if status.Endpoints[0].Healthy || !status.Endpoints[1].Healthy {
+		t.Fatalf("unexpected health: %+v", status.Endpoints)
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"code88reset/internal/i18n"
+)
+
+// ErrPAYGOProtected 拒绝重置 PAYGO 订阅（请求未发出）
+var ErrPAYGOProtected = i18n.Errorf("api.paygo_protected")
+
+// APIError 88code API 返回的错误（HTTP 状态码非 2xx 或业务响应 ok=false）
+type APIError struct {
+	StatusCode int    // HTTP 状态码
+	Code       int    // 业务错误码，未知时为 0
+	Message    string // 服务端返回的错误信息
+	text       error  // 带消息码的说明文字，为空时使用通用格式
+}
+
+func (e *APIError) Error() string {
+	return e.Localize(i18n.Default())
+}
+
+// Localize 按语言渲染错误说明
+func (e *APIError) Localize(locale i18n.Locale) string {
+	if e.text != nil {
+		return i18n.Localize(e.text, locale)
+	}
+	return i18n.T(locale, "api.error", e.Code, e.Message, e.StatusCode)
+}
+
+// CodedMessage 转换为可持久化的消息
+func (e *APIError) CodedMessage() i18n.Message {
+	if e.text != nil {
+		return i18n.MessageOf(e.text)
+	}
+	return i18n.M("api.error", e.Code, e.Message, e.StatusCode)
+}
+
+// IsAuthError 判断错误是否为 API Key 鉴权失败（Key 无效或已被吊销）
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range []int{apiErr.StatusCode, apiErr.Code} {
+		if code == http.StatusUnauthorized || code == http.StatusForbidden {
+			return true
+		}
+	}
+	return false
+}
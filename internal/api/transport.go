@@ -0,0 +1,179 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+)
+
+// DefaultTimeout 默认请求超时
+const DefaultTimeout = 30 * time.Second
+
+// ClientOptions 客户端网络选项，由同一配置创建的客户端共享 http.Client 以复用连接
+type ClientOptions struct {
+	HTTPClient *http.Client
+	UserAgent  string
+	Headers    map[string]string
+	Endpoints  *EndpointPool // 上游地址列表，为空时只使用客户端的 BaseURL
+
+	proxied *proxyClients // 按代理地址复用的 http.Client（用于单个 Token 的代理设置）
+}
+
+type proxyClients struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}
+
+// NewClientOptions 根据配置创建客户端网络选项（代理、CA 证书、客户端证书、超时、请求头）
+func NewClientOptions(cfg models.HTTPClientConfig) (*ClientOptions, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if proxy := strings.TrimSpace(cfg.ProxyURL); proxy != "" {
+		proxyURL, err := ParseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	timeout := DefaultTimeout
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+
+	headers := make(map[string]string, len(cfg.Headers))
+	for name, value := range cfg.Headers {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	return &ClientOptions{
+		HTTPClient: &http.Client{Transport: transport, Timeout: timeout},
+		UserAgent:  strings.TrimSpace(cfg.UserAgent),
+		Headers:    headers,
+		proxied:    &proxyClients{clients: make(map[string]*http.Client)},
+	}, nil
+}
+
+// WithUpstream 返回使用单独上游地址和代理的选项副本
+//
+// baseURL 非空时不再使用共享的上游地址列表；proxyURL 非空时替换代理，证书、超时和请求头保持不变。
+func (o *ClientOptions) WithUpstream(baseURL, proxyURL string) (*ClientOptions, error) {
+	copied := *o
+	if baseURL != "" {
+		copied.Endpoints = nil
+	}
+	if proxyURL != "" {
+		client, err := o.proxyClient(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		copied.HTTPClient = client
+	}
+	return &copied, nil
+}
+
+// proxyClient 返回使用指定代理的 http.Client，同一代理地址复用连接
+func (o *ClientOptions) proxyClient(proxyURL string) (*http.Client, error) {
+	if o.proxied != nil {
+		o.proxied.mu.Lock()
+		defer o.proxied.mu.Unlock()
+		if client, ok := o.proxied.clients[proxyURL]; ok {
+			return client, nil
+		}
+	}
+
+	parsed, err := ParseProxyURL(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	base := &http.Client{Timeout: DefaultTimeout}
+	if o.HTTPClient != nil {
+		base = o.HTTPClient
+	}
+	transport, ok := base.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.Proxy = http.ProxyURL(parsed)
+
+	client := &http.Client{Transport: transport, Timeout: base.Timeout}
+	if o.proxied != nil {
+		o.proxied.clients[proxyURL] = client
+	}
+	return client, nil
+}
+
+// ParseProxyURL 解析代理地址，支持 http、https 和 socks5
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, i18n.Errorf("api.invalid_proxy", raw)
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https", "socks5":
+		return u, nil
+	default:
+		return nil, i18n.Errorf("api.unsupported_proxy_scheme", u.Scheme)
+	}
+}
+
+// newTLSConfig 加载额外信任的 CA 和客户端证书，均未配置时返回 nil 使用默认设置
+func newTLSConfig(cfg models.HTTPClientConfig) (*tls.Config, error) {
+	certFile, keyFile := strings.TrimSpace(cfg.ClientCertFile), strings.TrimSpace(cfg.ClientKeyFile)
+	caFile := strings.TrimSpace(cfg.CAFile)
+	if (certFile == "") != (keyFile == "") {
+		return nil, i18n.Errorf("api.client_cert_incomplete")
+	}
+	if caFile == "" && certFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, i18n.Errorf("api.read_ca_failed", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, i18n.Errorf("api.invalid_ca_pem", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, i18n.Errorf("api.load_client_cert_failed", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"code88reset/internal/models"
+)
+
+func TestNewClientOptionsAppliesHeadersAndProxy(t *testing.T) {
+	var gotHost, gotUA, gotEnv, gotAuth string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.URL.Host
+		gotUA = r.Header.Get("User-Agent")
+		gotEnv = r.Header.Get("X-Env")
+		gotAuth = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":0,"ok":true,"data":[]}`))
+	}))
+	defer proxy.Close()
+
+	opts, err := NewClientOptions(models.HTTPClientConfig{
+		ProxyURL:  proxy.URL,
+		UserAgent: "code88reset-test",
+		Headers:   map[string]string{"x-env": "prod", "Authorization": "ignored"},
+	})
+	if err != nil {
+		t.Fatalf("NewClientOptions() error = %v", err)
+	}
+
+	client := NewClientWithOptions("http://api.88code.invalid", "secret", nil, opts)
+	client.Cache = nil
+	if _, err := client.GetSubscriptions(); err != nil {
+		t.Fatalf("GetSubscriptions() error = %v", err)
+	}
+
+	if gotHost != "api.88code.invalid" {
+		t.Fatalf("request did not go through proxy, host = %q", gotHost)
+	}
+	if gotUA != "code88reset-test" || gotEnv != "prod" {
+		t.Fatalf("headers not applied: User-Agent=%q X-Env=%q", gotUA, gotEnv)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Fatalf("extra headers must not override Authorization, got %q", gotAuth)
+	}
+}
+
+func TestNewClientOptionsRejectsInvalidConfig(t *testing.T) {
+	cases := map[string]models.HTTPClientConfig{
+		"unsupported proxy scheme": {ProxyURL: "ftp://proxy:21"},
+		"proxy without host":       {ProxyURL: "socks5://"},
+		"cert without key":         {ClientCertFile: "client.pem"},
+		"missing ca file":          {CAFile: "/nonexistent/ca.pem"},
+	}
+	for name, cfg := range cases {
+		if _, err := NewClientOptions(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	if _, err := NewClientOptions(models.HTTPClientConfig{ProxyURL: "socks5://127.0.0.1:1080"}); err != nil {
+		t.Fatalf("socks5 proxy should be accepted: %v", err)
+	}
+}
+
+func TestNewClientOptionsRejectsInvalidCABundle(t *testing.T) {
+	path := t.TempDir() + "/ca.pem"
+	if err := os.WriteFile(path, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := NewClientOptions(models.HTTPClientConfig{CAFile: path})
+	if err == nil || !strings.Contains(err.Error(), "PEM") {
+		t.Fatalf("expected PEM error, got %v", err)
+	}
+}
@@ -28,7 +28,7 @@ type apiClient interface {
 }
 
 type dependencies struct {
-	newClient          func(store *storage.Storage, baseURL, apiKey string, plans []string) apiClient
+	newClient          func(store *storage.Storage, baseURL, apiKey string, plans []string, opts *api.ClientOptions) apiClient
 	runSingleScheduler func(app *App, client apiClient) error
 	runMultiScheduler  func(app *App, accounts []models.AccountConfig) error
 	sleep              func(d time.Duration)
@@ -39,6 +39,8 @@ type App struct {
 	Config     appconfig.Settings
 	Store      *storage.Storage
 	AccountMgr accountManager
+	ClientOpts *api.ClientOptions     // API 网络选项，为空时使用默认值
+	Journal    scheduler.ResetJournal // 重置事件日志，为空时不记录
 	deps       dependencies
 }
 
@@ -54,8 +56,8 @@ func New(cfg appconfig.Settings, store *storage.Storage, accountMgr accountManag
 
 func defaultDependencies() dependencies {
 	return dependencies{
-		newClient: func(store *storage.Storage, baseURL, apiKey string, plans []string) apiClient {
-			client := api.NewClient(baseURL, apiKey, plans)
+		newClient: func(store *storage.Storage, baseURL, apiKey string, plans []string, opts *api.ClientOptions) apiClient {
+			client := api.NewClientWithOptions(baseURL, apiKey, plans, opts)
 			client.Storage = store
 			return client
 		},
@@ -77,6 +79,7 @@ func defaultDependencies() dependencies {
 				return err
 			}
 
+			sched.SetResetJournal(app.Journal)
 			sched.Start()
 			return nil
 		},
@@ -96,6 +99,8 @@ func defaultDependencies() dependencies {
 				return err
 			}
 
+			multiSched.SetClientOptions(app.ClientOpts)
+			multiSched.SetResetJournal(app.Journal)
 			multiSched.Start()
 			return nil
 		},
@@ -256,8 +261,10 @@ func (a *App) runListMode() error {
 	}
 
 	if len(accounts) == 0 {
-		logger.Info("暂无账号，请先导入账号:")
-		logger.Info("  go run cmd/reset/main.go -mode=import -apikeys=key1,key2,key3")
+		logger.Info("暂无账号，run 模式传入多个 API Key 时会自动同步账号:")
+		logger.Info("  go run cmd/reset/main.go -mode=run -apikeys=key1,key2,key3")
+		logger.Info("Web 模式使用的 Token 请通过子命令管理:")
+		logger.Info("  go run cmd/reset/main.go token add -name 名称 <API Key>")
 		return nil
 	}
 
@@ -340,5 +347,5 @@ func (a *App) runMultiAccountMode(apiKeys []string) error {
 }
 
 func (a *App) newAPIClient(key string) apiClient {
-	return a.deps.newClient(a.Store, a.Config.BaseURL, key, a.Config.Plans)
+	return a.deps.newClient(a.Store, a.Config.BaseURL, key, a.Config.Plans, a.ClientOpts)
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"code88reset/internal/api"
 	appconfig "code88reset/internal/config"
 	"code88reset/internal/models"
 	"code88reset/internal/storage"
@@ -133,7 +134,7 @@ func TestAppRun_TestMode(t *testing.T) {
 		},
 	}
 
-	app.deps.newClient = func(*storage.Storage, string, string, []string) apiClient {
+	app.deps.newClient = func(*storage.Storage, string, string, []string, *api.ClientOptions) apiClient {
 		return client
 	}
 	app.deps.runSingleScheduler = func(*App, apiClient) error {
@@ -167,7 +168,7 @@ func TestAppRun_RunModeSingle(t *testing.T) {
 	app := newTestApp(t, cfg, mgr)
 
 	client := &fakeClient{}
-	app.deps.newClient = func(*storage.Storage, string, string, []string) apiClient { return client }
+	app.deps.newClient = func(*storage.Storage, string, string, []string, *api.ClientOptions) apiClient { return client }
 
 	called := false
 	app.deps.runSingleScheduler = func(a *App, c apiClient) error {
@@ -211,7 +212,7 @@ func TestAppRun_RunModeMulti(t *testing.T) {
 	app := newTestApp(t, cfg, mgr)
 
 	client := &fakeClient{}
-	app.deps.newClient = func(*storage.Storage, string, string, []string) apiClient { return client }
+	app.deps.newClient = func(*storage.Storage, string, string, []string, *api.ClientOptions) apiClient { return client }
 	app.deps.runSingleScheduler = func(*App, apiClient) error {
 		t.Fatalf("single scheduler should not run in multi-account scenario")
 		return nil
@@ -0,0 +1,272 @@
+// Package auth 管理 Web API 的服务令牌
+//
+// 服务令牌供 CI 等外部自动化调用管理接口，只授予所需的权限，可设置过期时间并随时吊销。
+// 令牌明文只在创建时返回一次，存储中只保留 SHA-256 哈希。
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// 可授予服务令牌的权限
+const (
+	ScopeStatusRead    = "status:read"    // 系统状态、重置规则
+	ScopeTokensRead    = "tokens:read"    // Token 列表、详情和用量（API Key 已遮蔽）
+	ScopeTokensRefresh = "tokens:refresh" // 刷新订阅信息
+	ScopeResetTrigger  = "reset:trigger"  // 手动重置
+	ScopeConfigRead    = "config:read"    // 读取动态配置
+	ScopeLogsRead      = "logs:read"      // 查询和导出系统日志
+	ScopeReportsRead   = "reports:read"   // 查看重置报告
+)
+
+// Scopes 全部可授予的权限
+var Scopes = []string{
+	ScopeStatusRead,
+	ScopeTokensRead,
+	ScopeTokensRefresh,
+	ScopeResetTrigger,
+	ScopeConfigRead,
+	ScopeLogsRead,
+	ScopeReportsRead,
+}
+
+const (
+	// SecretPrefix 服务令牌明文前缀，便于与管理员令牌区分
+	SecretPrefix = "crs_"
+
+	serviceTokensFile    = "service_tokens.json"
+	visiblePrefixLen     = len(SecretPrefix) + 8
+	lastUsedSaveInterval = time.Minute // 最近使用时间的最小落盘间隔
+)
+
+var (
+	ErrInvalidToken = i18n.Errorf("auth.invalid_token")
+	ErrTokenExpired = i18n.Errorf("auth.token_expired")
+	ErrTokenRevoked = i18n.Errorf("auth.token_revoked")
+	ErrNotFound     = i18n.Errorf("auth.token_not_found")
+)
+
+// Store 服务令牌存储
+type Store struct {
+	filePath string
+	mu       sync.Mutex
+	tokens   map[string]*models.ServiceToken // key: ID
+	byHash   map[string]*models.ServiceToken // key: 明文哈希
+	now      func() time.Time
+}
+
+// NewStore 创建服务令牌存储，数据保存在 data 目录的 service_tokens.json
+func NewStore(dataDir string) (*Store, error) {
+	s := &Store{
+		filePath: filepath.Join(dataDir, serviceTokensFile),
+		tokens:   make(map[string]*models.ServiceToken),
+		byHash:   make(map[string]*models.ServiceToken),
+		now:      time.Now,
+	}
+
+	data, err := os.ReadFile(s.filePath)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取 %s 失败: %w", serviceTokensFile, err)
+	}
+
+	var stored models.ServiceTokenStorage
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("解析 %s 失败: %w", serviceTokensFile, err)
+	}
+	for i := range stored.Tokens {
+		t := &stored.Tokens[i]
+		s.tokens[t.ID] = t
+		s.byHash[t.Hash] = t
+	}
+	return s, nil
+}
+
+// ValidateScopes 检查权限列表，至少包含一项且均为已知权限
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return i18n.Errorf("auth.scope_required")
+	}
+	for _, scope := range scopes {
+		if !containsScope(Scopes, scope) {
+			return i18n.Errorf("auth.unknown_scope", scope, strings.Join(Scopes, ", "))
+		}
+	}
+	return nil
+}
+
+// HasScope 判断服务令牌是否拥有指定权限
+func HasScope(t *models.ServiceToken, scope string) bool {
+	return containsScope(t.Scopes, scope)
+}
+
+func containsScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Create 创建服务令牌，返回令牌信息和只出现这一次的明文
+func (s *Store) Create(name string, scopes []string, expiresAt *time.Time) (*models.ServiceToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", i18n.Errorf("auth.name_required")
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, "", err
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("生成令牌失败: %w", err)
+	}
+	secret := SecretPrefix + hex.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", i18n.Errorf("auth.expiry_in_past")
+	}
+
+	t := &models.ServiceToken{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Prefix:    secret[:visiblePrefixLen],
+		Hash:      hashSecret(secret),
+		Scopes:    append([]string(nil), scopes...),
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	s.tokens[t.ID] = t
+	s.byHash[t.Hash] = t
+	if err := s.saveUnlocked(); err != nil {
+		delete(s.tokens, t.ID)
+		delete(s.byHash, t.Hash)
+		return nil, "", err
+	}
+
+	created := *t
+	return &created, secret, nil
+}
+
+// List 列出全部服务令牌（含已吊销和已过期的），按创建时间排序
+func (s *Store) List() []models.ServiceToken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]models.ServiceToken, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		list = append(list, *t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Revoke 吊销服务令牌，记录保留以便审计
+func (s *Store) Revoke(id string) (*models.ServiceToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if t.RevokedAt == nil {
+		now := s.now()
+		t.RevokedAt = &now
+		if err := s.saveUnlocked(); err != nil {
+			t.RevokedAt = nil
+			return nil, err
+		}
+	}
+
+	revoked := *t
+	return &revoked, nil
+}
+
+// Authenticate 校验令牌明文，成功时记录最近使用时间
+func (s *Store) Authenticate(secret string) (*models.ServiceToken, error) {
+	if !strings.HasPrefix(secret, SecretPrefix) {
+		return nil, ErrInvalidToken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.byHash[hashSecret(secret)]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	now := s.now()
+	if t.RevokedAt != nil {
+		return nil, ErrTokenRevoked
+	}
+	if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+		return nil, ErrTokenExpired
+	}
+
+	// 最近使用时间只用于展示，限制落盘频率；保存失败不影响认证
+	persist := t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= lastUsedSaveInterval
+	if persist {
+		t.LastUsedAt = &now
+		if err := s.saveUnlocked(); err != nil {
+			logger.Warn("保存服务令牌 %s 的最近使用时间失败: %v", t.Name, err)
+		}
+	}
+
+	authenticated := *t
+	return &authenticated, nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveUnlocked 写入文件，调用方需持有锁
+func (s *Store) saveUnlocked() error {
+	stored := models.ServiceTokenStorage{Tokens: make([]models.ServiceToken, 0, len(s.tokens))}
+	for _, t := range s.tokens {
+		stored.Tokens = append(stored.Tokens, *t)
+	}
+	sort.Slice(stored.Tokens, func(i, j int) bool {
+		return stored.Tokens[i].CreatedAt.Before(stored.Tokens[j].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化服务令牌失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.filePath), 0755); err != nil {
+		return fmt.Errorf("创建数据目录失败: %w", err)
+	}
+	if err := os.WriteFile(s.filePath, data, 0600); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", serviceTokensFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_CreateAuthenticateRevoke(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore error = %v", err)
+	}
+
+	created, secret, err := store.Create("ci", []string{ScopeStatusRead, ScopeTokensRefresh}, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if !strings.HasPrefix(secret, SecretPrefix) || !strings.HasPrefix(secret, created.Prefix) {
+		t.Fatalf("unexpected secret %q for prefix %q", secret, created.Prefix)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, serviceTokensFile))
+	if err != nil {
+		t.Fatalf("read store file: %v", err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Fatalf("secret must not be stored in plain text")
+	}
+
+	// 重新加载后仍能校验
+	reloaded, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("reload error = %v", err)
+	}
+	got, err := reloaded.Authenticate(secret)
+	if err != nil || got.ID != created.ID || got.LastUsedAt == nil {
+		t.Fatalf("Authenticate = %+v, %v", got, err)
+	}
+	if !HasScope(got, ScopeTokensRefresh) || HasScope(got, ScopeResetTrigger) {
+		t.Fatalf("unexpected scopes %v", got.Scopes)
+	}
+	if _, err := reloaded.Authenticate(secret + "x"); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("wrong secret error = %v", err)
+	}
+
+	if _, err := reloaded.Revoke(created.ID); err != nil {
+		t.Fatalf("Revoke error = %v", err)
+	}
+	if _, err := reloaded.Authenticate(secret); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("revoked token error = %v", err)
+	}
+	if _, err := reloaded.Revoke("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("revoke missing error = %v", err)
+	}
+}
+
+func TestStore_Expiry(t *testing.T) {
+	store, err := NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore error = %v", err)
+	}
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	past := now.Add(-time.Hour)
+	if _, _, err := store.Create("old", []string{ScopeStatusRead}, &past); err == nil {
+		t.Fatalf("expected error for expiry in the past")
+	}
+
+	expiresAt := now.Add(time.Hour)
+	_, secret, err := store.Create("ci", []string{ScopeStatusRead}, &expiresAt)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if _, err := store.Authenticate(secret); err != nil {
+		t.Fatalf("Authenticate before expiry error = %v", err)
+	}
+
+	now = expiresAt
+	if _, err := store.Authenticate(secret); !errors.Is(err, ErrTokenExpired) {
+		t.Fatalf("expired token error = %v", err)
+	}
+}
+
+func TestStore_AuthenticateIgnoresSaveFailure(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir)
+	if err != nil {
+		t.Fatalf("NewStore error = %v", err)
+	}
+	_, secret, err := store.Create("ci", []string{ScopeStatusRead}, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+
+	// 数据目录被文件占用，保存最近使用时间会失败
+	blocker := filepath.Join(dir, "blocker")
+	if err := os.WriteFile(blocker, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	store.filePath = filepath.Join(blocker, serviceTokensFile)
+
+	if _, err := store.Authenticate(secret); err != nil {
+		t.Fatalf("Authenticate error = %v", err)
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	if err := ValidateScopes(nil); err == nil {
+		t.Errorf("empty scopes should be rejected")
+	}
+	if err := ValidateScopes([]string{ScopeStatusRead, "admin"}); err == nil {
+		t.Errorf("unknown scope should be rejected")
+	}
+	if err := ValidateScopes(Scopes); err != nil {
+		t.Errorf("all known scopes should be accepted: %v", err)
+	}
+}
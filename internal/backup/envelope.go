@@ -0,0 +1,171 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	KindTokens = "tokens" // Token 导出归档
+	KindBackup = "backup" // data 目录完整备份
+
+	envelopeFormat  = "88code-reset"
+	envelopeVersion = 1
+
+	kdfIterations = 200000
+	kdfKeyLength  = 32
+	kdfSaltLength = 16
+)
+
+// ErrPassphraseRequired 归档已加密但未提供口令
+var ErrPassphraseRequired = errors.New("归档已加密，需要提供口令")
+
+// Envelope 归档文件外层结构，Payload 为明文 JSON，加密时改为 Ciphertext
+type Envelope struct {
+	Format     string          `json:"format"`
+	Kind       string          `json:"kind"`
+	Version    int             `json:"version"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Encrypted  bool            `json:"encrypted"`
+	Iterations int             `json:"iterations,omitempty"`
+	Salt       []byte          `json:"salt,omitempty"`
+	Nonce      []byte          `json:"nonce,omitempty"`
+	Ciphertext []byte          `json:"ciphertext,omitempty"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+}
+
+// Seal 将 payload 序列化为归档，passphrase 非空时使用 AES-256-GCM 加密
+func Seal(kind string, payload interface{}, passphrase string) ([]byte, error) {
+	plain, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("序列化归档内容失败: %w", err)
+	}
+
+	env := Envelope{
+		Format:    envelopeFormat,
+		Kind:      kind,
+		Version:   envelopeVersion,
+		CreatedAt: time.Now(),
+	}
+
+	if passphrase == "" {
+		env.Payload = plain
+		return json.MarshalIndent(env, "", "  ")
+	}
+
+	salt := make([]byte, kdfSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("生成随机盐失败: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, salt, kdfIterations)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %w", err)
+	}
+
+	env.Encrypted = true
+	env.Iterations = kdfIterations
+	env.Salt = salt
+	env.Nonce = nonce
+	env.Ciphertext = gcm.Seal(nil, nonce, plain, []byte(kind))
+
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// Open 解析归档并将内容反序列化到 v，kind 必须与归档类型一致
+func Open(data []byte, kind, passphrase string, v interface{}) (*Envelope, error) {
+	var env Envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("解析归档失败: %w", err)
+	}
+
+	if env.Format != envelopeFormat {
+		return nil, fmt.Errorf("不支持的归档格式: %q", env.Format)
+	}
+	if env.Kind != kind {
+		return nil, fmt.Errorf("归档类型不匹配: 需要 %s，实际为 %s", kind, env.Kind)
+	}
+	if env.Version > envelopeVersion {
+		return nil, fmt.Errorf("归档版本 %d 高于当前支持的版本 %d", env.Version, envelopeVersion)
+	}
+
+	plain := []byte(env.Payload)
+	if env.Encrypted {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+
+		gcm, err := newGCM(passphrase, env.Salt, env.Iterations)
+		if err != nil {
+			return nil, err
+		}
+
+		plain, err = gcm.Open(nil, env.Nonce, env.Ciphertext, []byte(env.Kind))
+		if err != nil {
+			return nil, fmt.Errorf("解密归档失败: 口令错误或文件已损坏")
+		}
+	}
+
+	if err := json.Unmarshal(plain, v); err != nil {
+		return nil, fmt.Errorf("解析归档内容失败: %w", err)
+	}
+
+	return &env, nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 || len(salt) == 0 {
+		return nil, fmt.Errorf("归档加密参数无效")
+	}
+
+	key := pbkdf2SHA256([]byte(passphrase), salt, iterations, kdfKeyLength)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("初始化加密失败: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 实现 RFC 8018 PBKDF2（HMAC-SHA256）
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	key := make([]byte, 0, blocks*hashLen)
+	buf := make([]byte, 4)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(buf, uint32(block))
+		prf.Write(buf)
+		u := prf.Sum(nil)
+
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+
+	return key[:keyLen]
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestPBKDF2SHA256_KnownVectors(t *testing.T) {
+	cases := []struct {
+		iterations int
+		want       string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+	}
+
+	for _, tc := range cases {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte("password"), []byte("salt"), tc.iterations, 32))
+		if got != tc.want {
+			t.Fatalf("pbkdf2 c=%d = %s, want %s", tc.iterations, got, tc.want)
+		}
+	}
+}
+
+func TestSealOpen_RoundTrip(t *testing.T) {
+	type payload struct {
+		Value string `json:"value"`
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		data, err := Seal(KindTokens, payload{Value: "hello"}, "")
+		if err != nil {
+			t.Fatalf("Seal() error = %v", err)
+		}
+
+		var got payload
+		env, err := Open(data, KindTokens, "", &got)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if env.Encrypted || got.Value != "hello" {
+			t.Fatalf("unexpected result: encrypted=%v value=%q", env.Encrypted, got.Value)
+		}
+	})
+
+	t.Run("encrypted", func(t *testing.T) {
+		data, err := Seal(KindBackup, payload{Value: "secret"}, "pass")
+		if err != nil {
+			t.Fatalf("Seal() error = %v", err)
+		}
+
+		var got payload
+		if _, err := Open(data, KindBackup, "", &got); !errors.Is(err, ErrPassphraseRequired) {
+			t.Fatalf("expected ErrPassphraseRequired, got %v", err)
+		}
+		if _, err := Open(data, KindBackup, "wrong", &got); err == nil {
+			t.Fatal("expected error for wrong passphrase")
+		}
+		if _, err := Open(data, KindBackup, "pass", &got); err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		if got.Value != "secret" {
+			t.Fatalf("decrypted value = %q", got.Value)
+		}
+	})
+
+	t.Run("kind mismatch", func(t *testing.T) {
+		data, err := Seal(KindTokens, payload{}, "")
+		if err != nil {
+			t.Fatalf("Seal() error = %v", err)
+		}
+		if _, err := Open(data, KindBackup, "", &payload{}); err == nil {
+			t.Fatal("expected kind mismatch error")
+		}
+	})
+}
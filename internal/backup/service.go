@@ -0,0 +1,238 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"code88reset/internal/config"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+	"code88reset/pkg/logger"
+)
+
+const (
+	tokensFile = "tokens.json"
+	configFile = "config.json"
+)
+
+// TokenArchive Token 导出内容
+type TokenArchive struct {
+	ExportedAt time.Time                     `json:"exported_at"`
+	Tokens     []models.Token                `json:"tokens"`
+	History    map[string][]models.SystemLog `json:"history,omitempty"` // key: Token ID
+}
+
+// Snapshot data 目录完整快照
+type Snapshot struct {
+	AppVersion string                     `json:"app_version,omitempty"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	Files      map[string]json.RawMessage `json:"files"` // key: 相对 data 目录的路径
+}
+
+// TokenImportResult Token 导入结果
+type TokenImportResult struct {
+	*token.ImportReport
+	HistoryImported int `json:"history_imported"`
+}
+
+// RestoreResult 备份恢复结果
+type RestoreResult struct {
+	BackupCreatedAt time.Time `json:"backup_created_at"`
+	AppVersion      string    `json:"app_version,omitempty"`
+	Files           []string  `json:"files"`
+}
+
+// Service 负责 Token 导入导出以及 data 目录的备份恢复
+type Service struct {
+	tokenStorage *token.Storage
+	tokenMgr     *token.Manager
+	store        *storage.Storage
+	configMgr    *config.DynamicConfigManager
+	appVersion   string
+}
+
+// NewService 创建备份服务
+func NewService(tokenStorage *token.Storage, tokenMgr *token.Manager, store *storage.Storage, configMgr *config.DynamicConfigManager, appVersion string) *Service {
+	return &Service{
+		tokenStorage: tokenStorage,
+		tokenMgr:     tokenMgr,
+		store:        store,
+		configMgr:    configMgr,
+		appVersion:   appVersion,
+	}
+}
+
+// ExportTokens 导出全部 Token（含配置与历史日志），passphrase 非空时加密
+func (s *Service) ExportTokens(passphrase string, includeHistory bool) ([]byte, error) {
+	archive := TokenArchive{
+		ExportedAt: time.Now(),
+		Tokens:     s.tokenStorage.Snapshot().Tokens,
+	}
+	sort.Slice(archive.Tokens, func(i, j int) bool {
+		return archive.Tokens[i].AddedAt.Before(archive.Tokens[j].AddedAt)
+	})
+
+	if includeHistory {
+		archive.History = make(map[string][]models.SystemLog)
+		for _, t := range archive.Tokens {
+			page, err := s.store.QuerySystemLogs(storage.SystemLogQuery{TokenID: t.ID})
+			if err != nil {
+				return nil, fmt.Errorf("读取 Token 历史日志失败: %w", err)
+			}
+			if len(page.Logs) > 0 {
+				archive.History[t.ID] = page.Logs
+			}
+		}
+	}
+
+	data, err := Seal(KindTokens, archive, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("已导出 %d 个 Token (加密=%v)", len(archive.Tokens), passphrase != "")
+	return data, nil
+}
+
+// ImportTokens 从归档导入 Token，并将历史日志关联到最终的 Token ID
+func (s *Service) ImportTokens(data []byte, passphrase string, mode token.ConflictMode) (*TokenImportResult, error) {
+	var archive TokenArchive
+	if _, err := Open(data, KindTokens, passphrase, &archive); err != nil {
+		return nil, err
+	}
+
+	report, err := s.tokenMgr.ImportTokens(archive.Tokens, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TokenImportResult{ImportReport: report}
+
+	var history []models.SystemLog
+	for sourceID, logs := range archive.History {
+		targetID, ok := report.IDMap[sourceID]
+		if !ok {
+			continue
+		}
+		for _, l := range logs {
+			l.TokenID = targetID
+			history = append(history, l)
+		}
+	}
+
+	if len(history) > 0 {
+		added, err := s.store.ImportSystemLogs(history)
+		if err != nil {
+			logger.Warn("导入 Token 历史日志失败: %v", err)
+		}
+		result.HistoryImported = added
+	}
+
+	return result, nil
+}
+
+// Backup 生成 data 目录的一致性快照
+//
+// 备份期间持有重置锁，避免与定时重置并发写入；Token 与配置取自内存中的最新状态。
+func (s *Service) Backup(passphrase string) ([]byte, error) {
+	if err := s.store.AcquireLock("backup"); err != nil {
+		return nil, fmt.Errorf("无法开始备份: %w", err)
+	}
+	defer s.store.ReleaseLock()
+
+	files, err := s.store.SnapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.tokenStorage != nil {
+		raw, err := json.MarshalIndent(s.tokenStorage.Snapshot(), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化 Token 数据失败: %w", err)
+		}
+		files[tokensFile] = raw
+	}
+
+	if s.configMgr != nil {
+		raw, err := json.MarshalIndent(s.configMgr.GetConfig(), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化配置失败: %w", err)
+		}
+		files[configFile] = raw
+	}
+
+	snapshot := Snapshot{
+		AppVersion: s.appVersion,
+		CreatedAt:  time.Now(),
+		Files:      files,
+	}
+
+	data, err := Seal(KindBackup, snapshot, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("已生成数据备份: %d 个文件 (加密=%v)", len(files), passphrase != "")
+	s.store.AddSystemMessage("info", i18n.M("backup.log.created", len(files)))
+	return data, nil
+}
+
+// Restore 从快照恢复 data 目录，并让运行中的组件重新加载数据
+func (s *Service) Restore(data []byte, passphrase string) (*RestoreResult, error) {
+	var snapshot Snapshot
+	if _, err := Open(data, KindBackup, passphrase, &snapshot); err != nil {
+		return nil, err
+	}
+
+	if raw, ok := snapshot.Files[tokensFile]; ok {
+		var tokens models.TokenStorage
+		if err := json.Unmarshal(raw, &tokens); err != nil {
+			return nil, fmt.Errorf("备份中的 tokens.json 无效: %w", err)
+		}
+	}
+	if raw, ok := snapshot.Files[configFile]; ok {
+		var cfg models.DynamicConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return nil, fmt.Errorf("备份中的 config.json 无效: %w", err)
+		}
+	}
+
+	if err := s.store.AcquireLock("restore"); err != nil {
+		return nil, fmt.Errorf("无法开始恢复: %w", err)
+	}
+	defer s.store.ReleaseLock()
+
+	if err := s.store.RestoreFiles(snapshot.Files); err != nil {
+		return nil, err
+	}
+
+	if s.tokenStorage != nil {
+		if err := s.tokenStorage.Reload(); err != nil {
+			return nil, fmt.Errorf("重新加载 Token 数据失败: %w", err)
+		}
+	}
+	if s.configMgr != nil {
+		if err := s.configMgr.Reload(); err != nil {
+			return nil, fmt.Errorf("重新加载配置失败: %w", err)
+		}
+	}
+
+	result := &RestoreResult{
+		BackupCreatedAt: snapshot.CreatedAt,
+		AppVersion:      snapshot.AppVersion,
+		Files:           make([]string, 0, len(snapshot.Files)),
+	}
+	for name := range snapshot.Files {
+		result.Files = append(result.Files, name)
+	}
+	sort.Strings(result.Files)
+
+	logger.Info("已从备份恢复 %d 个文件 (备份时间: %s)", len(result.Files), snapshot.CreatedAt.Format(time.RFC3339))
+	s.store.AddSystemMessage("warning", i18n.M("backup.log.restored",
+		len(result.Files), snapshot.CreatedAt.Format("2006-01-02 15:04:05")))
+	return result, nil
+}
@@ -0,0 +1,206 @@
+// Package cli 实现命令行管理子命令（token、config、logs、status）
+//
+// 子命令通过 Backend 操作数据：LocalBackend 与 Web 模式共用 token.Manager、DynamicConfigManager 和 storage.Storage，
+// RemoteBackend 通过 Web 管理 API 操作正在运行的实例。
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+// ErrUsage 命令行参数错误，调用方据此返回退出码 2
+var ErrUsage = errors.New("参数错误")
+
+// DefaultPollInterval logs tail -f 轮询新日志的间隔
+const DefaultPollInterval = 2 * time.Second
+
+// Backend 子命令操作的数据源
+type Backend interface {
+	Status() (*webapi.StatusResponse, error)
+	ListTokens() ([]*models.Token, error)
+	GetToken(tokenID string) (*models.Token, error)
+	AddToken(req token.AddRequest) (*models.Token, error)
+	DeleteToken(tokenID string) error
+	SetTokenEnabled(tokenID string, enabled bool) (*models.Token, error)
+	RefreshToken(tokenID string) (*models.Token, error)
+	RefreshAllTokens() (*token.RefreshSummary, error)
+	ResetToken(tokenID, resetType string) (*models.Token, error)
+	GetConfig() (*models.DynamicConfig, error)
+	UpdateConfig(cfg models.DynamicConfig) error
+	QuerySystemLogs(q storage.SystemLogQuery) (*storage.SystemLogPage, error)
+}
+
+// CLI 命令行子命令执行器
+type CLI struct {
+	Backend      Backend
+	In           io.Reader // token add 从标准输入读取 API Key
+	Out          io.Writer // 命令结果
+	Err          io.Writer // 用法和参数错误提示
+	PollInterval time.Duration
+}
+
+// New 创建命令行执行器，输出写入 out
+func New(backend Backend, out io.Writer) *CLI {
+	return &CLI{
+		Backend:      backend,
+		In:           os.Stdin,
+		Out:          out,
+		Err:          os.Stderr,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// Run 执行子命令，args 为去掉全局参数后的剩余参数（如 token list -json）
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		c.usage()
+		return fmt.Errorf("%w: 缺少子命令", ErrUsage)
+	}
+
+	switch args[0] {
+	case "token":
+		return c.runToken(args[1:])
+	case "config":
+		return c.runConfig(args[1:])
+	case "logs":
+		return c.runLogs(ctx, args[1:])
+	case "status":
+		return c.runStatus(args[1:])
+	case "help", "-h", "-help", "--help":
+		c.printUsage(c.Out)
+		return nil
+	default:
+		c.usage()
+		return fmt.Errorf("%w: 未知的子命令 %q", ErrUsage, args[0])
+	}
+}
+
+// usage 参数错误时向错误输出打印子命令列表
+func (c *CLI) usage() {
+	c.printUsage(c.Err)
+}
+
+// printUsage 输出子命令列表
+func (c *CLI) printUsage(w io.Writer) {
+	fmt.Fprint(w, `用法: reset [全局参数] <子命令> [参数]
+
+子命令:
+  status                                  查看系统状态和下次重置时间
+  token list                              列出所有 Token
+  token add [-name 名称] <API Key|->      添加 Token（- 表示从标准输入读取）
+  token rm <ID|名称>...                   删除 Token
+  token enable|disable <ID|名称>...       启用/禁用 Token
+  token refresh <ID|名称>... | -all       刷新订阅信息
+  token reset [-type second] <ID|名称>... 按调度项配置手动重置
+  config get [键]                         查看配置（键如 second_reset.hour）
+  config set 键=值...                     修改配置
+  logs tail [-n 20] [-f]                  查看最近的系统日志
+
+除 config 外的子命令支持 -json 输出 JSON；全局参数（如 -datadir）需写在子命令之前。
+`)
+}
+
+// parseArgs 解析子命令参数，允许参数和位置参数混写（如 token rm ID -json）
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet 创建子命令参数集，错误和帮助信息写入错误输出
+func (c *CLI) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.Err)
+	return fs
+}
+
+// printJSON 以缩进格式输出 JSON
+func (c *CLI) printJSON(v interface{}) error {
+	enc := json.NewEncoder(c.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// newTable 创建对齐输出的表格，header 为列名
+func (c *CLI) newTable(header ...string) *tabwriter.Writer {
+	tw := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	return tw
+}
+
+// runStatus 查看系统状态
+func (c *CLI) runStatus(args []string) error {
+	fs := c.newFlagSet("status")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	status, err := c.Backend.Status()
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return c.printJSON(status)
+	}
+
+	tw := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "当前时间:\t%s (%s)\n", status.CurrentTime.Format("2006-01-02 15:04:05"), status.Timezone)
+	if status.NextResetType != "" {
+		fmt.Fprintf(tw, "下次重置:\t%s (%s)\n", status.NextResetTime.Format("2006-01-02 15:04"), status.NextResetType)
+	} else {
+		fmt.Fprintf(tw, "下次重置:\t未启用定时重置\n")
+	}
+	fmt.Fprintf(tw, "第一次重置:\t%s\n", formatSchedule(status.FirstReset))
+	fmt.Fprintf(tw, "第二次重置:\t%s\n", formatSchedule(status.SecondReset))
+	fmt.Fprintf(tw, "Token:\t共 %d 个，启用 %d 个\n", status.TotalTokens, status.EnabledTokens)
+	if status.APIEndpoint.Current != "" {
+		fmt.Fprintf(tw, "上游地址:\t%s\n", status.APIEndpoint.Current)
+	}
+	for _, e := range status.ExpiringTokens {
+		if e.Expired {
+			fmt.Fprintf(tw, "已到期:\t%s (%s)\n", e.Name, e.EndDate)
+		} else {
+			fmt.Fprintf(tw, "即将到期:\t%s 剩余 %d 天 (%s)\n", e.Name, e.RemainingDays, e.EndDate)
+		}
+	}
+	return tw.Flush()
+}
+
+// formatSchedule 格式化调度项
+func formatSchedule(cfg models.ResetConfig) string {
+	if !cfg.Enabled {
+		return "已停用"
+	}
+	s := fmt.Sprintf("%02d:%02d", cfg.Hour, cfg.Minute)
+	if cfg.ThresholdPercent > 0 {
+		s += fmt.Sprintf("，额度高于 %.1f%% 时跳过", cfg.ThresholdPercent)
+	}
+	return s
+}
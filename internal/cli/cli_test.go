@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"code88reset/internal/config"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+)
+
+type testEnv struct {
+	cli       *CLI
+	out       *bytes.Buffer
+	tokens    *token.Manager
+	configMgr *config.DynamicConfigManager
+	store     *storage.Storage
+}
+
+func newTestEnv(t *testing.T, tokens ...models.Token) *testEnv {
+	t.Helper()
+
+	dir := t.TempDir()
+	store, err := storage.NewStorage(dir)
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	configMgr, err := config.NewDynamicConfigManager(dir)
+	if err != nil {
+		t.Fatalf("NewDynamicConfigManager() error = %v", err)
+	}
+	tokenStorage, err := token.NewStorage(dir)
+	if err != nil {
+		t.Fatalf("token.NewStorage() error = %v", err)
+	}
+	for i := range tokens {
+		if err := tokenStorage.Add(&tokens[i]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	tokenMgr := token.NewManager(tokenStorage, "http://127.0.0.1:0", store)
+
+	out := &bytes.Buffer{}
+	c := New(NewLocalBackend(tokenMgr, configMgr, store), out)
+	c.Err = &bytes.Buffer{}
+	c.PollInterval = 10 * time.Millisecond
+
+	return &testEnv{cli: c, out: out, tokens: tokenMgr, configMgr: configMgr, store: store}
+}
+
+func (e *testEnv) run(t *testing.T, args ...string) error {
+	t.Helper()
+	e.out.Reset()
+	return e.cli.Run(context.Background(), args)
+}
+
+func TestTokenEnableDisable_ByIDOrName(t *testing.T) {
+	env := newTestEnv(t,
+		models.Token{ID: "t1", Name: "alpha", APIKey: "key-1", Enabled: true},
+		models.Token{ID: "t2", Name: "beta", APIKey: "key-2", Enabled: false},
+	)
+
+	if err := env.run(t, "token", "disable", "alpha", "-json"); err != nil {
+		t.Fatalf("token disable error = %v", err)
+	}
+	var result []models.Token
+	if err := json.Unmarshal(env.out.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, env.out.String())
+	}
+	if len(result) != 1 || result[0].ID != "t1" || result[0].Enabled {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	// 已是目标状态时不切换
+	if err := env.run(t, "token", "disable", "t1"); err != nil {
+		t.Fatalf("token disable error = %v", err)
+	}
+	if got, _ := env.tokens.GetToken("t1"); got.Enabled {
+		t.Fatalf("t1 should stay disabled")
+	}
+
+	if err := env.run(t, "token", "enable", "t1", "beta"); err != nil {
+		t.Fatalf("token enable error = %v", err)
+	}
+	for _, id := range []string{"t1", "t2"} {
+		if got, _ := env.tokens.GetToken(id); !got.Enabled {
+			t.Fatalf("%s should be enabled", id)
+		}
+	}
+	if !strings.Contains(env.out.String(), "alpha") || !strings.Contains(env.out.String(), "ENABLED") {
+		t.Fatalf("expected table output, got:\n%s", env.out.String())
+	}
+}
+
+func TestTokenRemove_RejectsAmbiguousName(t *testing.T) {
+	env := newTestEnv(t,
+		models.Token{ID: "t1", Name: "dup", APIKey: "key-1", Enabled: true},
+		models.Token{ID: "t2", Name: "dup", APIKey: "key-2", Enabled: true},
+	)
+
+	if err := env.run(t, "token", "rm", "dup"); err == nil {
+		t.Fatalf("expected ambiguous name error")
+	}
+	if err := env.run(t, "token", "rm", "missing"); err == nil {
+		t.Fatalf("expected missing token error")
+	}
+	if err := env.run(t, "token", "rm"); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+
+	if err := env.run(t, "token", "rm", "t2"); err != nil {
+		t.Fatalf("token rm error = %v", err)
+	}
+	if len(env.tokens.ListTokens()) != 1 {
+		t.Fatalf("expected 1 token left")
+	}
+}
+
+func TestConfigSetAndGet(t *testing.T) {
+	env := newTestEnv(t)
+
+	if err := env.run(t, "config", "set", "second_reset.hour=22", "timezone=Asia/Hong_Kong", "credit_watch.enabled=true"); err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+	cfg := env.configMgr.GetConfig()
+	if cfg.SecondReset.Hour != 22 || cfg.Timezone != "Asia/Hong_Kong" || !cfg.CreditWatch.Enabled {
+		t.Fatalf("config not updated: %+v", cfg)
+	}
+
+	if err := env.run(t, "config", "get", "second_reset.hour"); err != nil {
+		t.Fatalf("config get error = %v", err)
+	}
+	if got := strings.TrimSpace(env.out.String()); got != "22" {
+		t.Fatalf("config get = %q, want 22", got)
+	}
+
+	// 未知键和类型错误均不保存
+	for _, arg := range []string{"second_reset.hourr=1", "second_reset.hour=abc", "second_reset.hour=30"} {
+		if err := env.run(t, "config", "set", arg); err == nil {
+			t.Fatalf("config set %s should fail", arg)
+		}
+	}
+	if env.configMgr.GetConfig().SecondReset.Hour != 22 {
+		t.Fatalf("invalid config set should not be saved")
+	}
+
+	if err := env.run(t, "config", "get", "no_such_key"); err == nil {
+		t.Fatalf("expected unknown key error")
+	}
+}
+
+func TestLogsTail_PrintsOldestFirst(t *testing.T) {
+	env := newTestEnv(t)
+
+	base := time.Now().Add(-time.Hour)
+	for i, msg := range []string{"first", "second", "third"} {
+		env.store.AddSystemLogEntry(models.SystemLog{
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Type:      "info",
+			Message:   msg,
+		})
+	}
+
+	if err := env.run(t, "logs", "tail", "-n", "2"); err != nil {
+		t.Fatalf("logs tail error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(env.out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "second") || !strings.Contains(lines[1], "third") {
+		t.Fatalf("unexpected output:\n%s", env.out.String())
+	}
+}
+
+func TestLogsTail_FollowPrintsNewEntries(t *testing.T) {
+	env := newTestEnv(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- env.cli.Run(ctx, []string{"logs", "tail", "-f", "-json"})
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	env.store.AddSystemLogEntry(models.SystemLog{Type: "error", Message: "boom"})
+	time.Sleep(60 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("logs tail -f error = %v", err)
+	}
+	var entry models.SystemLog
+	if err := json.Unmarshal(bytes.TrimSpace(env.out.Bytes()), &entry); err != nil {
+		t.Fatalf("invalid JSON line: %v\n%s", err, env.out.String())
+	}
+	if entry.Message != "boom" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestRun_UnknownCommand(t *testing.T) {
+	env := newTestEnv(t)
+
+	if err := env.run(t, "bogus"); !errors.Is(err, ErrUsage) {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+	if env.out.Len() != 0 {
+		t.Fatalf("usage should go to Err, got stdout:\n%s", env.out.String())
+	}
+}
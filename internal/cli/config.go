@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"code88reset/internal/models"
+)
+
+// runConfig 执行 config 子命令
+func (c *CLI) runConfig(args []string) error {
+	if len(args) == 0 {
+		c.usage()
+		return fmt.Errorf("%w: 缺少 config 子命令", ErrUsage)
+	}
+
+	switch args[0] {
+	case "get":
+		return c.configGet(args[1:])
+	case "set":
+		return c.configSet(args[1:])
+	default:
+		c.usage()
+		return fmt.Errorf("%w: 未知的 config 子命令 %q", ErrUsage, args[0])
+	}
+}
+
+// configGet 输出完整配置或指定键的值；字符串和数字直接输出，对象和数组输出 JSON
+func (c *CLI) configGet(args []string) error {
+	fs := c.newFlagSet("config get")
+	keys, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 1 {
+		return fmt.Errorf("%w: config get 只接受一个键", ErrUsage)
+	}
+
+	cfg, err := c.Backend.GetConfig()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return c.printJSON(cfg)
+	}
+
+	tree, err := configTree(*cfg)
+	if err != nil {
+		return err
+	}
+	value, err := lookupPath(tree, keys[0])
+	if err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}, []interface{}:
+		return c.printJSON(v)
+	case string:
+		fmt.Fprintln(c.Out, v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.Out, string(data))
+	}
+	return nil
+}
+
+// configSet 按 键=值 修改配置，多个键一次性校验并保存
+//
+// 值按 JSON 解析（数字、布尔、数组、对象），解析失败或原值为字符串时按字符串处理。
+func (c *CLI) configSet(args []string) error {
+	fs := c.newFlagSet("config set")
+	assignments, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(assignments) == 0 {
+		return fmt.Errorf("%w: config set 需要至少一个 键=值", ErrUsage)
+	}
+
+	cfg, err := c.Backend.GetConfig()
+	if err != nil {
+		return err
+	}
+	tree, err := configTree(*cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, assignment := range assignments {
+		key, raw, ok := strings.Cut(assignment, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: 无效的赋值 %q，格式应为 键=值", ErrUsage, assignment)
+		}
+		if err := setPath(tree, strings.TrimSpace(key), raw); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return err
+	}
+	var updated models.DynamicConfig
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&updated); err != nil {
+		return fmt.Errorf("配置项不存在或值类型不正确: %w", err)
+	}
+
+	if err := c.Backend.UpdateConfig(updated); err != nil {
+		return err
+	}
+	for _, assignment := range assignments {
+		key, _, _ := strings.Cut(assignment, "=")
+		fmt.Fprintf(c.Out, "已更新: %s\n", strings.TrimSpace(key))
+	}
+	return nil
+}
+
+// configTree 将配置转换为按 JSON 字段名索引的树
+func configTree(cfg models.DynamicConfig) (map[string]interface{}, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// lookupPath 按点分隔的键（如 second_reset.hour）查找配置值
+func lookupPath(tree map[string]interface{}, path string) (interface{}, error) {
+	var current interface{} = tree
+	for _, part := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("配置项不存在: %s", path)
+		}
+		current, ok = node[part]
+		if !ok {
+			return nil, fmt.Errorf("配置项不存在: %s", path)
+		}
+	}
+	return current, nil
+}
+
+// setPath 修改点分隔键对应的配置值，中间层级必须已存在
+//
+// 末级键可以不在树中（omitempty 字段），未知的键在转换回配置时由严格解码拒绝。
+func setPath(tree map[string]interface{}, path, raw string) error {
+	parts := strings.Split(path, ".")
+	parent := tree
+	for _, part := range parts[:len(parts)-1] {
+		node, ok := parent[part].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("配置项不存在: %s", path)
+		}
+		parent = node
+	}
+
+	last := parts[len(parts)-1]
+	if _, isString := parent[last].(string); isString {
+		parent[last] = raw
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		value = raw
+	}
+	parent[last] = value
+	return nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"time"
+
+	"code88reset/internal/config"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+// LocalBackend 直接读写本机 data 目录的数据源
+//
+// 与正在运行的 Web 模式进程并不共享内存状态，Web 模式运行时修改会在其下次保存时被覆盖。
+type LocalBackend struct {
+	tokens    *token.Manager
+	configMgr *config.DynamicConfigManager
+	storage   *storage.Storage
+}
+
+// NewLocalBackend 创建本地数据源
+func NewLocalBackend(tokens *token.Manager, configMgr *config.DynamicConfigManager, store *storage.Storage) *LocalBackend {
+	return &LocalBackend{
+		tokens:    tokens,
+		configMgr: configMgr,
+		storage:   store,
+	}
+}
+
+// Status 汇总系统状态，计算方式与 GET /api/status 相同
+func (b *LocalBackend) Status() (*webapi.StatusResponse, error) {
+	cfg := b.configMgr.GetConfig()
+	tokens := b.tokens.ListTokens()
+
+	enabledCount := 0
+	for _, t := range tokens {
+		if t.Enabled {
+			enabledCount++
+		}
+	}
+
+	now := time.Now()
+	if loc, err := time.LoadLocation(cfg.Timezone); err == nil {
+		now = now.In(loc)
+	}
+	nextResetTime, nextResetType := config.NextReset(cfg, now)
+
+	return &webapi.StatusResponse{
+		CurrentTime:    now,
+		Timezone:       cfg.Timezone,
+		NextResetTime:  nextResetTime,
+		NextResetType:  nextResetType,
+		TotalTokens:    len(tokens),
+		EnabledTokens:  enabledCount,
+		FirstReset:     cfg.FirstReset,
+		SecondReset:    cfg.SecondReset,
+		ExpiringTokens: b.tokens.ExpiringTokens(token.ExpiringWindowDays(cfg.ExpiryAlert)),
+		APIEndpoint:    b.tokens.EndpointStatus(),
+	}, nil
+}
+
+// ListTokens 列出所有 Token
+func (b *LocalBackend) ListTokens() ([]*models.Token, error) {
+	return b.tokens.ListTokens(), nil
+}
+
+// GetToken 获取单个 Token
+func (b *LocalBackend) GetToken(tokenID string) (*models.Token, error) {
+	return b.tokens.GetToken(tokenID)
+}
+
+// AddToken 添加 Token
+func (b *LocalBackend) AddToken(req token.AddRequest) (*models.Token, error) {
+	return b.tokens.AddTokenWithRequest(req)
+}
+
+// DeleteToken 删除 Token
+func (b *LocalBackend) DeleteToken(tokenID string) error {
+	return b.tokens.DeleteToken(tokenID)
+}
+
+// SetTokenEnabled 启用或禁用 Token，状态已符合时不做修改
+func (b *LocalBackend) SetTokenEnabled(tokenID string, enabled bool) (*models.Token, error) {
+	t, err := b.tokens.GetToken(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled == enabled {
+		return t, nil
+	}
+	return b.tokens.ToggleToken(tokenID)
+}
+
+// RefreshToken 刷新单个 Token 的订阅信息
+func (b *LocalBackend) RefreshToken(tokenID string) (*models.Token, error) {
+	return b.tokens.RefreshSubscription(tokenID)
+}
+
+// RefreshAllTokens 按配置的并发数刷新所有启用的 Token
+func (b *LocalBackend) RefreshAllTokens() (*token.RefreshSummary, error) {
+	return b.tokens.RefreshAll(b.configMgr.GetConfig().SubscriptionRefresh.Concurrency)
+}
+
+// ResetToken 按对应调度项的阈值和规则配置重置单个 Token
+func (b *LocalBackend) ResetToken(tokenID, resetType string) (*models.Token, error) {
+	req := token.ScheduleResetRequest(b.configMgr.GetConfig(), resetType, "")
+	return b.tokens.ResetTokenWithRequest(tokenID, req)
+}
+
+// GetConfig 获取动态配置
+func (b *LocalBackend) GetConfig() (*models.DynamicConfig, error) {
+	cfg := b.configMgr.GetConfig()
+	return &cfg, nil
+}
+
+// UpdateConfig 校验并保存动态配置
+func (b *LocalBackend) UpdateConfig(cfg models.DynamicConfig) error {
+	return b.configMgr.UpdateConfig(cfg)
+}
+
+// QuerySystemLogs 查询系统日志
+func (b *LocalBackend) QuerySystemLogs(q storage.SystemLogQuery) (*storage.SystemLogPage, error) {
+	return b.storage.QuerySystemLogs(q)
+}
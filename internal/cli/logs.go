@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+)
+
+// runLogs 执行 logs 子命令
+func (c *CLI) runLogs(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "tail" {
+		c.usage()
+		return fmt.Errorf("%w: logs 目前只支持 tail", ErrUsage)
+	}
+	return c.logsTail(ctx, args[1:])
+}
+
+// logsTail 按时间顺序输出最近的系统日志，-f 时持续输出新日志直到 ctx 结束
+func (c *CLI) logsTail(ctx context.Context, args []string) error {
+	fs := c.newFlagSet("logs tail")
+	limit := fs.Int("n", 20, "输出最近的日志条数")
+	types := fs.String("type", "", "日志类型（info/success/warning/error），多个用逗号分隔")
+	tokenID := fs.String("token", "", "只看指定 Token ID 的日志")
+	runID := fs.String("run", "", "只看指定执行批次的日志")
+	search := fs.String("q", "", "全文搜索")
+	follow := fs.Bool("f", false, "持续输出新日志")
+	asJSON := fs.Bool("json", false, "每行输出一条 JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+	if *limit < 1 {
+		return fmt.Errorf("%w: -n 必须大于 0", ErrUsage)
+	}
+
+	query := storage.SystemLogQuery{
+		TokenID: *tokenID,
+		RunID:   *runID,
+		Search:  *search,
+		Limit:   *limit,
+	}
+	if *types != "" {
+		for _, t := range strings.Split(*types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.Types = append(query.Types, t)
+			}
+		}
+	}
+
+	// 没有历史日志时，-f 从当前时间开始输出
+	last := time.Now()
+	page, err := c.Backend.QuerySystemLogs(query)
+	if err != nil {
+		return err
+	}
+
+	// 查询结果按时间倒序，输出时改为正序
+	for i := len(page.Logs) - 1; i >= 0; i-- {
+		if err := c.printLog(page.Logs[i], *asJSON); err != nil {
+			return err
+		}
+		last = page.Logs[i].Timestamp
+	}
+	if !*follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		since := last.Add(time.Nanosecond)
+		query.Since = &since
+		query.Limit = 0
+		page, err := c.Backend.QuerySystemLogs(query)
+		if err != nil {
+			return err
+		}
+		for i := len(page.Logs) - 1; i >= 0; i-- {
+			if err := c.printLog(page.Logs[i], *asJSON); err != nil {
+				return err
+			}
+			last = page.Logs[i].Timestamp
+		}
+	}
+}
+
+// printLog 输出单条系统日志
+func (c *CLI) printLog(log models.SystemLog, asJSON bool) error {
+	if asJSON {
+		// 逐行 JSON，便于管道处理
+		return json.NewEncoder(c.Out).Encode(log)
+	}
+
+	line := fmt.Sprintf("%s [%s] %s", log.Timestamp.Local().Format("2006-01-02 15:04:05"), strings.ToUpper(log.Type), log.Message)
+	if log.TokenID != "" {
+		line += " token=" + log.TokenID
+	}
+	if log.RunID != "" {
+		line += " run=" + log.RunID
+	}
+	_, err := fmt.Fprintln(c.Out, line)
+	return err
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+// remoteLogPageSize 不限条数时每次请求的日志数量（与服务端单页上限一致）
+const remoteLogPageSize = 1000
+
+// RemoteBackend 通过 Web 管理 API 操作正在运行的 Web 模式实例
+//
+// 修改由服务进程执行并立即生效，不会与其内存中的数据互相覆盖。
+type RemoteBackend struct {
+	client *webapi.Client
+}
+
+// NewRemoteBackend 创建远程数据源
+func NewRemoteBackend(client *webapi.Client) *RemoteBackend {
+	return &RemoteBackend{client: client}
+}
+
+// Status 获取系统状态
+func (b *RemoteBackend) Status() (*webapi.StatusResponse, error) {
+	return b.client.Status()
+}
+
+// ListTokens 列出所有 Token
+func (b *RemoteBackend) ListTokens() ([]*models.Token, error) {
+	return b.client.ListTokens()
+}
+
+// GetToken 获取单个 Token
+func (b *RemoteBackend) GetToken(tokenID string) (*models.Token, error) {
+	return b.client.GetToken(tokenID)
+}
+
+// AddToken 添加 Token
+func (b *RemoteBackend) AddToken(req token.AddRequest) (*models.Token, error) {
+	return b.client.AddToken(webapi.AddTokenRequest{
+		APIKey: req.APIKey,
+		Name:   req.Name,
+		UpstreamFields: webapi.UpstreamFields{
+			BaseURL:  req.BaseURL,
+			ProxyURL: req.ProxyURL,
+		},
+	})
+}
+
+// DeleteToken 删除 Token
+func (b *RemoteBackend) DeleteToken(tokenID string) error {
+	return b.client.DeleteToken(tokenID)
+}
+
+// SetTokenEnabled 启用或禁用 Token，状态已符合时不做修改
+func (b *RemoteBackend) SetTokenEnabled(tokenID string, enabled bool) (*models.Token, error) {
+	t, err := b.client.GetToken(tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Enabled == enabled {
+		return t, nil
+	}
+	return b.client.ToggleToken(tokenID)
+}
+
+// RefreshToken 刷新单个 Token 的订阅信息
+func (b *RemoteBackend) RefreshToken(tokenID string) (*models.Token, error) {
+	return b.client.RefreshToken(tokenID)
+}
+
+// RefreshAllTokens 刷新所有启用的 Token
+func (b *RemoteBackend) RefreshAllTokens() (*token.RefreshSummary, error) {
+	resp, err := b.client.RefreshAll()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Summary, nil
+}
+
+// ResetToken 按调度项配置手动重置单个 Token
+func (b *RemoteBackend) ResetToken(tokenID, resetType string) (*models.Token, error) {
+	return b.client.ResetToken(tokenID, resetType)
+}
+
+// GetConfig 获取动态配置
+func (b *RemoteBackend) GetConfig() (*models.DynamicConfig, error) {
+	return b.client.GetConfig()
+}
+
+// UpdateConfig 更新动态配置
+func (b *RemoteBackend) UpdateConfig(cfg models.DynamicConfig) error {
+	return b.client.UpdateConfig(cfg)
+}
+
+// QuerySystemLogs 查询系统日志，按 Limit 换算为分页参数
+func (b *RemoteBackend) QuerySystemLogs(q storage.SystemLogQuery) (*storage.SystemLogPage, error) {
+	pageSize := q.Limit
+	if pageSize <= 0 || pageSize > remoteLogPageSize {
+		pageSize = remoteLogPageSize
+	}
+
+	resp, err := b.client.SystemLogs(q, q.Offset/pageSize+1, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.SystemLogPage{
+		Logs:   resp.Logs,
+		Total:  resp.Total,
+		Offset: (resp.Page - 1) * resp.PageSize,
+		Limit:  resp.PageSize,
+	}, nil
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code88reset/internal/auth"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/web"
+	"code88reset/internal/webapi"
+)
+
+// newRemoteEnv 启动使用同一份数据的 Web 服务，返回通过管理 API 操作它的 CLI
+func newRemoteEnv(t *testing.T, adminToken string, tokens ...models.Token) (*testEnv, *CLI) {
+	t.Helper()
+
+	env := newTestEnv(t, tokens...)
+	srv := web.NewServer(models.WebServerConfig{}, env.tokens, env.configMgr, env.store, nil, nil, nil, "secret", "test")
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	remote := New(NewRemoteBackend(webapi.NewClient(ts.URL, adminToken)), env.out)
+	remote.Err = &bytes.Buffer{}
+	return env, remote
+}
+
+func TestRemoteBackend_TokenAndConfigCommands(t *testing.T) {
+	env, remote := newRemoteEnv(t, "secret",
+		models.Token{ID: "t1", Name: "alpha", APIKey: "key-1", Enabled: true},
+	)
+	run := func(args ...string) error {
+		env.out.Reset()
+		return remote.Run(context.Background(), args)
+	}
+
+	if err := run("token", "list", "-json"); err != nil {
+		t.Fatalf("token list error = %v", err)
+	}
+	var listed []models.Token
+	if err := json.Unmarshal(env.out.Bytes(), &listed); err != nil || len(listed) != 1 || listed[0].ID != "t1" {
+		t.Fatalf("unexpected token list: %v\n%s", err, env.out.String())
+	}
+
+	// 修改由服务进程执行，直接体现在其内存中的数据
+	if err := run("token", "disable", "alpha"); err != nil {
+		t.Fatalf("token disable error = %v", err)
+	}
+	if got, _ := env.tokens.GetToken("t1"); got.Enabled {
+		t.Fatalf("t1 should be disabled on the server")
+	}
+
+	if err := run("config", "set", "second_reset.minute=30"); err != nil {
+		t.Fatalf("config set error = %v", err)
+	}
+	if env.configMgr.GetConfig().SecondReset.Minute != 30 {
+		t.Fatalf("server config not updated")
+	}
+
+	if err := run("status", "-json"); err != nil {
+		t.Fatalf("status error = %v", err)
+	}
+	var status webapi.StatusResponse
+	if err := json.Unmarshal(env.out.Bytes(), &status); err != nil {
+		t.Fatalf("invalid status JSON: %v", err)
+	}
+	if status.TotalTokens != 1 || status.EnabledTokens != 0 || status.APICache == nil {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	env.store.AddSystemLogEntry(models.SystemLog{Type: "warning", Message: "remote log"})
+	if err := run("logs", "tail", "-type", "warning"); err != nil {
+		t.Fatalf("logs tail error = %v", err)
+	}
+	if !strings.Contains(env.out.String(), "remote log") {
+		t.Fatalf("expected log line, got:\n%s", env.out.String())
+	}
+
+	if err := run("token", "rm", "t1"); err != nil {
+		t.Fatalf("token rm error = %v", err)
+	}
+	if len(env.tokens.ListTokens()) != 0 {
+		t.Fatalf("token should be removed on the server")
+	}
+}
+
+func TestRemoteBackend_ReportsAPIErrors(t *testing.T) {
+	_, remote := newRemoteEnv(t, "wrong")
+
+	err := remote.Run(context.Background(), []string{"token", "list"})
+	var apiErr *webapi.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized ||
+		apiErr.Code != webapi.CodeUnauthorized || apiErr.MessageCode != "web.invalid_token" || apiErr.Message == "" {
+		t.Fatalf("expected 401 API error, got %v", err)
+	}
+}
+
+func TestRemoteBackend_MasksAPIKeys(t *testing.T) {
+	env, remote := newRemoteEnv(t, "secret",
+		models.Token{ID: "t1", Name: "alpha", APIKey: "88_secret-key-value", Enabled: true},
+	)
+
+	if err := remote.Run(context.Background(), []string{"token", "list", "-json"}); err != nil {
+		t.Fatalf("token list error = %v", err)
+	}
+	if strings.Contains(env.out.String(), "88_secret-key-value") || !strings.Contains(env.out.String(), "88_secre****") {
+		t.Fatalf("API key should be masked in web responses:\n%s", env.out.String())
+	}
+
+	client := remote.Backend.(*RemoteBackend).client
+	key, err := client.RevealAPIKey("t1")
+	if err != nil || key != "88_secret-key-value" {
+		t.Fatalf("RevealAPIKey = %q, %v", key, err)
+	}
+	page, err := env.store.QuerySystemLogs(storage.SystemLogQuery{Types: []string{"audit"}, TokenID: "t1"})
+	if err != nil || page.Total != 1 {
+		t.Fatalf("expected one audit log for reveal, got %+v, %v", page, err)
+	}
+}
+
+func TestRemoteBackend_ServiceTokenScopes(t *testing.T) {
+	env := newTestEnv(t, models.Token{ID: "t1", Name: "alpha", APIKey: "key-1", Enabled: true})
+	serviceTokens, err := auth.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("auth.NewStore error = %v", err)
+	}
+	srv := web.NewServer(models.WebServerConfig{}, env.tokens, env.configMgr, env.store, nil, nil, serviceTokens, "secret", "test")
+	ts := httptest.NewServer(srv.Handler())
+	t.Cleanup(ts.Close)
+
+	_, secret, err := serviceTokens.Create("ci", []string{auth.ScopeStatusRead, auth.ScopeTokensRead}, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	client := webapi.NewClient(ts.URL, secret)
+
+	if _, err := client.Status(); err != nil {
+		t.Fatalf("status with status:read error = %v", err)
+	}
+	if _, err := client.ListTokens(); err != nil {
+		t.Fatalf("list with tokens:read error = %v", err)
+	}
+
+	var apiErr *webapi.Error
+	if _, err := client.RefreshAll(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Code != webapi.CodeMissingScope {
+		t.Fatalf("refresh-all without scope: got %v", err)
+	}
+	// 未列入权限表的接口（删除、查看完整 Key 等）只允许管理员令牌
+	if err := client.DeleteToken("t1"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Code != webapi.CodeForbidden {
+		t.Fatalf("delete with service token: got %v", err)
+	}
+	if _, err := client.RevealAPIKey("t1"); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("reveal with service token: got %v", err)
+	}
+
+	admin := webapi.NewClient(ts.URL, "secret")
+	if err := admin.RevokeServiceToken(serviceTokens.List()[0].ID); err != nil {
+		t.Fatalf("revoke error = %v", err)
+	}
+	if _, err := client.Status(); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.Code != webapi.CodeTokenRevoked {
+		t.Fatalf("status with revoked token: got %v", err)
+	}
+}
@@ -0,0 +1,336 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"strings"
+
+	"code88reset/internal/models"
+	"code88reset/internal/token"
+)
+
+// runToken 执行 token 子命令
+func (c *CLI) runToken(args []string) error {
+	if len(args) == 0 {
+		c.usage()
+		return fmt.Errorf("%w: 缺少 token 子命令", ErrUsage)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		return c.tokenList(args[1:])
+	case "add":
+		return c.tokenAdd(args[1:])
+	case "rm", "remove", "delete":
+		return c.tokenRemove(args[1:])
+	case "enable":
+		return c.tokenSetEnabled(args[1:], true)
+	case "disable":
+		return c.tokenSetEnabled(args[1:], false)
+	case "refresh":
+		return c.tokenRefresh(args[1:])
+	case "reset":
+		return c.tokenReset(args[1:])
+	default:
+		c.usage()
+		return fmt.Errorf("%w: 未知的 token 子命令 %q", ErrUsage, args[0])
+	}
+}
+
+// tokenList 列出所有 Token
+func (c *CLI) tokenList(args []string) error {
+	fs := c.newFlagSet("token list")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	tokens, err := c.Backend.ListTokens()
+	if err != nil {
+		return err
+	}
+	return c.printTokens(tokens, *asJSON)
+}
+
+// tokenAdd 添加 Token
+func (c *CLI) tokenAdd(args []string) error {
+	fs := c.newFlagSet("token add")
+	name := fs.String("name", "", "Token 名称，留空时自动生成")
+	baseURL := fs.String("base-url", "", "单独的上游地址")
+	proxyURL := fs.String("proxy-url", "", "单独的代理地址")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%w: token add 需要且只接受一个 API Key（- 表示从标准输入读取）", ErrUsage)
+	}
+
+	apiKey := positional[0]
+	if apiKey == "-" {
+		line, err := bufio.NewReader(c.In).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("从标准输入读取 API Key 失败: %w", err)
+		}
+		apiKey = line
+	}
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return fmt.Errorf("%w: API Key 不能为空", ErrUsage)
+	}
+
+	t, err := c.Backend.AddToken(token.AddRequest{
+		APIKey:   apiKey,
+		Name:     *name,
+		BaseURL:  *baseURL,
+		ProxyURL: *proxyURL,
+	})
+	if err != nil {
+		return err
+	}
+	return c.printTokens([]*models.Token{t}, *asJSON)
+}
+
+// tokenRemove 删除 Token
+func (c *CLI) tokenRemove(args []string) error {
+	fs := c.newFlagSet("token rm")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	refs, err := c.parseTokenRefs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := c.resolveTokens(refs)
+	if err != nil {
+		return err
+	}
+
+	removed := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if err := c.Backend.DeleteToken(t.ID); err != nil {
+			return fmt.Errorf("删除 Token %s 失败: %w", t.Name, err)
+		}
+		removed = append(removed, t.ID)
+		if !*asJSON {
+			fmt.Fprintf(c.Out, "已删除: %s (%s)\n", t.Name, t.ID)
+		}
+	}
+
+	if *asJSON {
+		return c.printJSON(map[string]interface{}{"removed": removed})
+	}
+	return nil
+}
+
+// tokenSetEnabled 启用或禁用 Token
+func (c *CLI) tokenSetEnabled(args []string, enabled bool) error {
+	name := "token disable"
+	if enabled {
+		name = "token enable"
+	}
+	fs := c.newFlagSet(name)
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	refs, err := c.parseTokenRefs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	return c.applyToTokens(refs, *asJSON, func(t *models.Token) (*models.Token, error) {
+		return c.Backend.SetTokenEnabled(t.ID, enabled)
+	})
+}
+
+// tokenRefresh 刷新 Token 订阅信息
+func (c *CLI) tokenRefresh(args []string) error {
+	fs := c.newFlagSet("token refresh")
+	all := fs.Bool("all", false, "刷新所有启用的 Token")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	refs, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		if len(refs) > 0 {
+			return fmt.Errorf("%w: -all 不能与 Token 同时指定", ErrUsage)
+		}
+		summary, err := c.Backend.RefreshAllTokens()
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return c.printJSON(summary)
+		}
+		tw := c.newTable("ID", "NAME", "RESULT")
+		for _, item := range summary.Items {
+			result := "ok"
+			if item.Error != "" {
+				result = item.Error
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", item.TokenID, item.Name, result)
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Out, "刷新完成: 成功 %d, 失败 %d\n", summary.Succeeded, summary.Failed)
+		return nil
+	}
+
+	if len(refs) == 0 {
+		return fmt.Errorf("%w: 请指定 Token 的 ID 或名称，或使用 -all", ErrUsage)
+	}
+	return c.applyToTokens(refs, *asJSON, func(t *models.Token) (*models.Token, error) {
+		return c.Backend.RefreshToken(t.ID)
+	})
+}
+
+// tokenReset 按调度项配置手动重置 Token
+func (c *CLI) tokenReset(args []string) error {
+	fs := c.newFlagSet("token reset")
+	resetType := fs.String("type", "second", "重置类型: first 或 second（决定使用哪个调度项的阈值和规则）")
+	asJSON := fs.Bool("json", false, "输出 JSON")
+	refs, err := c.parseTokenRefs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *resetType != "first" && *resetType != "second" {
+		return fmt.Errorf("%w: -type 只能是 first 或 second", ErrUsage)
+	}
+
+	return c.applyToTokens(refs, *asJSON, func(t *models.Token) (*models.Token, error) {
+		return c.Backend.ResetToken(t.ID, *resetType)
+	})
+}
+
+// parseTokenRefs 解析参数并要求至少指定一个 Token
+func (c *CLI) parseTokenRefs(fs *flag.FlagSet, args []string) ([]string, error) {
+	refs, err := parseArgs(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("%w: %s 需要指定 Token 的 ID 或名称", ErrUsage, fs.Name())
+	}
+	return refs, nil
+}
+
+// applyToTokens 依次对指定的 Token 执行操作并输出结果，遇到错误时停止
+func (c *CLI) applyToTokens(refs []string, asJSON bool, op func(t *models.Token) (*models.Token, error)) error {
+	tokens, err := c.resolveTokens(refs)
+	if err != nil {
+		return err
+	}
+
+	updated := make([]*models.Token, 0, len(tokens))
+	for _, t := range tokens {
+		result, err := op(t)
+		if err != nil {
+			// 已处理的 Token 仍然输出，便于脚本判断进度
+			if len(updated) > 0 {
+				c.printTokens(updated, asJSON)
+			}
+			return fmt.Errorf("Token %s: %w", t.Name, err)
+		}
+		updated = append(updated, result)
+	}
+	return c.printTokens(updated, asJSON)
+}
+
+// resolveTokens 按 ID 或名称查找 Token，名称不唯一时报错
+func (c *CLI) resolveTokens(refs []string) ([]*models.Token, error) {
+	var all []*models.Token
+	result := make([]*models.Token, 0, len(refs))
+	for _, ref := range refs {
+		if t, err := c.Backend.GetToken(ref); err == nil {
+			result = append(result, t)
+			continue
+		}
+
+		if all == nil {
+			list, err := c.Backend.ListTokens()
+			if err != nil {
+				return nil, err
+			}
+			all = list
+		}
+
+		var matched []*models.Token
+		for _, t := range all {
+			if t.Name == ref {
+				matched = append(matched, t)
+			}
+		}
+		switch len(matched) {
+		case 0:
+			return nil, fmt.Errorf("Token 不存在: %s", ref)
+		case 1:
+			result = append(result, matched[0])
+		default:
+			return nil, fmt.Errorf("名称 %s 对应 %d 个 Token，请改用 ID", ref, len(matched))
+		}
+	}
+	return result, nil
+}
+
+// printTokens 以表格或 JSON 输出 Token 列表
+func (c *CLI) printTokens(tokens []*models.Token, asJSON bool) error {
+	if asJSON {
+		if tokens == nil {
+			tokens = []*models.Token{}
+		}
+		return c.printJSON(tokens)
+	}
+
+	tw := c.newTable("ID", "NAME", "ENABLED", "PLAN", "CREDITS", "HEALTH", "LAST RESET")
+	for _, t := range tokens {
+		fmt.Fprintf(tw, "%s\t%s\t%v\t%s\t%s\t%s\t%s\n",
+			t.ID, t.Name, t.Enabled, tokenPlan(t), tokenCredits(t), tokenHealth(t), tokenLastReset(t))
+	}
+	return tw.Flush()
+}
+
+// tokenPlan 订阅名称
+func tokenPlan(t *models.Token) string {
+	if t.Subscription == nil || t.Subscription.SubscriptionName == "" {
+		return "-"
+	}
+	return t.Subscription.SubscriptionName
+}
+
+// tokenCredits 当前额度和上限
+func tokenCredits(t *models.Token) string {
+	if t.Subscription == nil {
+		return "-"
+	}
+	s := t.Subscription
+	return fmt.Sprintf("%.2f/%.2f (%.1f%%)", s.CurrentCredits, s.CreditLimit, s.CreditPercent)
+}
+
+// tokenHealth 健康状态，API Key 失效时优先显示
+func tokenHealth(t *models.Token) string {
+	if t.KeyInvalid {
+		return models.HealthAuthFailed
+	}
+	if t.Health == nil || t.Health.State == "" {
+		return "-"
+	}
+	return t.Health.State
+}
+
+// tokenLastReset 最近一次重置时间和结果
+func tokenLastReset(t *models.Token) string {
+	r := t.LastReset
+	if r == nil {
+		return "-"
+	}
+	result := "ok"
+	switch {
+	case r.Pending():
+		result = "pending"
+	case !r.Success:
+		result = "skipped"
+	}
+	return fmt.Sprintf("%s %s %s", r.ResetAt.Local().Format("2006-01-02 15:04"), r.ResetType, result)
+}
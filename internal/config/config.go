@@ -3,8 +3,13 @@ package config
 import (
 	"bufio"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
 )
 
 const (
@@ -34,12 +39,59 @@ type Settings struct {
 	EnableFirstReset   bool
 }
 
+// maskedSecret 遮蔽后显示的占位内容
+const maskedSecret = "****"
+
 // MaskAPIKey 遮蔽 API Key 显示
 func MaskAPIKey(key string) string {
+	return VisibleAPIKeyPrefix(key) + maskedSecret
+}
+
+// MaskProxyURL 遮蔽代理地址中的密码，无法解析时整体遮蔽
+func MaskProxyURL(proxyURL string) string {
+	if proxyURL == "" {
+		return ""
+	}
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return maskedSecret
+	}
+	return u.Redacted()
+}
+
+// MaskWebhookURL 只保留 Webhook 地址的协议和主机，路径和查询参数中常带有密钥
+func MaskWebhookURL(webhookURL string) string {
+	if webhookURL == "" {
+		return ""
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Host == "" {
+		return maskedSecret
+	}
+	return u.Scheme + "://" + u.Host + "/" + maskedSecret
+}
+
+// MaskHeaders 遮蔽自定义请求头的值，请求头名称保持不变
+func MaskHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if value != "" {
+			value = maskedSecret
+		}
+		masked[name] = value
+	}
+	return masked
+}
+
+// VisibleAPIKeyPrefix API Key 允许明文显示的前缀，不超过 8 位的 Key 不显示任何部分
+func VisibleAPIKeyPrefix(key string) string {
 	if len(key) <= 8 {
-		return "****"
+		return ""
 	}
-	return key[:8] + "****"
+	return key[:8]
 }
 
 // ParsePlans 解析套餐名称列表
@@ -212,6 +264,232 @@ func GetAllAPIKeys(cmdKey, cmdKeys string) []string {
 	return allKeys
 }
 
+// GetBaseURLs 从多个来源获取上游地址列表（逗号分隔，按优先级排列，第一个为主地址）
+func GetBaseURLs(cmdBaseURL string) []string {
+	// 优先级: 命令行参数 > 环境变量 > .env 文件 > 默认值
+	for _, input := range []string{cmdBaseURL, os.Getenv("API_BASE_URLS"), readEnvValue(EnvFile, "API_BASE_URLS")} {
+		var urls []string
+		seen := make(map[string]bool)
+		for _, item := range strings.Split(input, ",") {
+			item = strings.TrimRight(strings.TrimSpace(item), "/")
+			if item != "" && !seen[item] {
+				seen[item] = true
+				urls = append(urls, item)
+			}
+		}
+		if len(urls) > 0 {
+			return urls
+		}
+	}
+	return []string{DefaultBaseURL}
+}
+
+// GetRemoteServer 获取命令行子命令连接的 Web 模式实例地址和管理员 Token
+//
+// 优先级: 命令行参数 > 环境变量（WEB_SERVER_URL、WEB_ADMIN_TOKEN）> .env 文件；
+// 地址为空表示直接操作本地 data 目录。
+func GetRemoteServer(cmdURL string) (serverURL, adminToken string) {
+	for _, v := range []string{cmdURL, os.Getenv("WEB_SERVER_URL"), readEnvValue(EnvFile, "WEB_SERVER_URL")} {
+		if v = strings.TrimRight(strings.TrimSpace(v), "/"); v != "" {
+			serverURL = v
+			break
+		}
+	}
+	for _, v := range []string{os.Getenv("WEB_ADMIN_TOKEN"), readEnvValue(EnvFile, "WEB_ADMIN_TOKEN")} {
+		if v = strings.TrimSpace(v); v != "" {
+			adminToken = v
+			break
+		}
+	}
+	return serverURL, adminToken
+}
+
+// GetLocale 获取默认语言，用于系统日志、命令行输出以及未指定语言的 API 请求
+//
+// 优先级: 命令行参数 > 环境变量 LOCALE > .env 文件，未设置时为 zh-CN；无法识别时返回 zh-CN 和错误。
+func GetLocale(cmdLocale string) (i18n.Locale, error) {
+	for _, v := range []string{cmdLocale, os.Getenv("LOCALE"), readEnvValue(EnvFile, "LOCALE")} {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		if locale, ok := i18n.Parse(v); ok {
+			return locale, nil
+		}
+		return i18n.ZhCN, i18n.Errorf("config.unknown_locale", v)
+	}
+	return i18n.ZhCN, nil
+}
+
+// GetHTTPClientConfig 从多个来源获取 API 网络配置（代理、证书、超时、请求头）
+//
+// 每一项的优先级: 命令行参数 > 环境变量 > .env 文件，未设置的项保持为空，
+// 由 MergeHTTPClientConfig 与动态配置合并。
+func GetHTTPClientConfig(cmd models.HTTPClientConfig) models.HTTPClientConfig {
+	pick := func(cmdValue, key string) string {
+		if cmdValue != "" {
+			return cmdValue
+		}
+		if env := os.Getenv(key); env != "" {
+			return env
+		}
+		return readEnvValue(EnvFile, key)
+	}
+
+	cfg := models.HTTPClientConfig{
+		ProxyURL:       pick(cmd.ProxyURL, "API_PROXY_URL"),
+		CAFile:         pick(cmd.CAFile, "API_CA_FILE"),
+		ClientCertFile: pick(cmd.ClientCertFile, "API_CLIENT_CERT"),
+		ClientKeyFile:  pick(cmd.ClientKeyFile, "API_CLIENT_KEY"),
+		UserAgent:      pick(cmd.UserAgent, "API_USER_AGENT"),
+		TimeoutSeconds: cmd.TimeoutSeconds,
+		Headers:        cmd.Headers,
+	}
+
+	if cfg.TimeoutSeconds <= 0 {
+		if seconds, err := strconv.Atoi(strings.TrimSpace(pick("", "API_TIMEOUT"))); err == nil && seconds > 0 {
+			cfg.TimeoutSeconds = seconds
+		}
+	}
+	if len(cfg.Headers) == 0 {
+		cfg.Headers = ParseHeaders(pick("", "API_HEADERS"))
+	}
+
+	return cfg
+}
+
+// DefaultHSTSMaxAge 启用 HTTPS 时默认的 HSTS 有效期（一年）
+const DefaultHSTSMaxAge = 365 * 24 * 60 * 60
+
+// Web 管理 API 限流与请求体大小的默认值
+const (
+	DefaultRateLimitPerIP    = 300      // 次/分钟
+	DefaultRateLimitPerToken = 120      // 次/分钟
+	DefaultMaxBodyBytes      = 1 << 20  // 1 MiB
+	DefaultMaxUploadBytes    = 64 << 20 // 64 MiB
+	DefaultActionCooldown    = 60       // 秒
+)
+
+// GetWebServerConfig 从多个来源获取 Web 服务的监听地址、跨域、HTTPS、安全响应头和限流设置
+//
+// 每一项的优先级: 命令行参数 > 环境变量 > .env 文件；端口由调用方设置。
+func GetWebServerConfig(cmd models.WebServerConfig) models.WebServerConfig {
+	pick := func(cmdValue, key string) string {
+		if cmdValue != "" {
+			return cmdValue
+		}
+		if env := os.Getenv(key); env != "" {
+			return env
+		}
+		return readEnvValue(EnvFile, key)
+	}
+	pickInt := func(cmdValue int, key string, fallback int) int {
+		if cmdValue > 0 {
+			return cmdValue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(pick("", key))); err == nil && n >= 0 {
+			return n
+		}
+		return fallback
+	}
+
+	cfg := models.WebServerConfig{
+		BindAddress:           strings.TrimSpace(pick(cmd.BindAddress, "WEB_BIND")),
+		Port:                  cmd.Port,
+		AllowedOrigins:        cmd.AllowedOrigins,
+		TLSCertFile:           pick(cmd.TLSCertFile, "WEB_TLS_CERT"),
+		TLSKeyFile:            pick(cmd.TLSKeyFile, "WEB_TLS_KEY"),
+		HTTPRedirectPort:      pickInt(cmd.HTTPRedirectPort, "WEB_HTTP_REDIRECT_PORT", 0),
+		HSTSMaxAge:            pickInt(cmd.HSTSMaxAge, "WEB_HSTS_MAX_AGE", DefaultHSTSMaxAge),
+		ContentSecurityPolicy: pick(cmd.ContentSecurityPolicy, "WEB_CSP"),
+		BasePath:              NormalizeBasePath(pick(cmd.BasePath, "WEB_BASE_PATH")),
+		TrustedProxies:        cmd.TrustedProxies,
+		RateLimitPerIP:        pickInt(cmd.RateLimitPerIP, "WEB_RATE_LIMIT_PER_IP", DefaultRateLimitPerIP),
+		RateLimitPerToken:     pickInt(cmd.RateLimitPerToken, "WEB_RATE_LIMIT_PER_TOKEN", DefaultRateLimitPerToken),
+		MaxBodyBytes:          pickInt(cmd.MaxBodyBytes, "WEB_MAX_BODY_BYTES", DefaultMaxBodyBytes),
+		MaxUploadBytes:        pickInt(cmd.MaxUploadBytes, "WEB_MAX_UPLOAD_BYTES", DefaultMaxUploadBytes),
+		ActionCooldown:        pickInt(cmd.ActionCooldown, "WEB_ACTION_COOLDOWN", DefaultActionCooldown),
+	}
+	if len(cfg.AllowedOrigins) == 0 {
+		cfg.AllowedOrigins = ParseOrigins(pick("", "WEB_CORS_ORIGINS"))
+	}
+	if len(cfg.TrustedProxies) == 0 {
+		cfg.TrustedProxies = splitAndTrim(pick("", "WEB_TRUSTED_PROXIES"))
+	}
+	return cfg
+}
+
+// NormalizeBasePath 规范化 URL 前缀：以 / 开头、不以 / 结尾，根路径返回空字符串
+func NormalizeBasePath(basePath string) string {
+	basePath = strings.Trim(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// ParseList 解析逗号分隔的列表，去掉空白项
+func ParseList(input string) []string {
+	return splitAndTrim(input)
+}
+
+// ParseOrigins 解析逗号分隔的跨域来源列表，去掉末尾的斜杠
+func ParseOrigins(input string) []string {
+	origins := splitAndTrim(input)
+	for i, origin := range origins {
+		origins[i] = strings.TrimRight(origin, "/")
+	}
+	return origins
+}
+
+// ParseHeaders 解析 "Name=Value;Name2=Value2" 格式的请求头列表
+func ParseHeaders(input string) map[string]string {
+	headers := make(map[string]string)
+	for _, item := range strings.Split(input, ";") {
+		name, value, ok := strings.Cut(item, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// MergeHTTPClientConfig 用 override 中已设置的项覆盖 base（命令行参数和环境变量优先于动态配置）
+func MergeHTTPClientConfig(base, override models.HTTPClientConfig) models.HTTPClientConfig {
+	merged := base
+	if override.ProxyURL != "" {
+		merged.ProxyURL = override.ProxyURL
+	}
+	if override.CAFile != "" {
+		merged.CAFile = override.CAFile
+	}
+	if override.ClientCertFile != "" || override.ClientKeyFile != "" {
+		merged.ClientCertFile = override.ClientCertFile
+		merged.ClientKeyFile = override.ClientKeyFile
+	}
+	if override.TimeoutSeconds > 0 {
+		merged.TimeoutSeconds = override.TimeoutSeconds
+	}
+	if override.UserAgent != "" {
+		merged.UserAgent = override.UserAgent
+	}
+	if len(override.Headers) > 0 {
+		headers := make(map[string]string, len(base.Headers)+len(override.Headers))
+		for name, value := range base.Headers {
+			headers[name] = value
+		}
+		for name, value := range override.Headers {
+			headers[name] = value
+		}
+		merged.Headers = headers
+	}
+	return merged
+}
+
 func splitAndTrim(input string) []string {
 	parts := strings.Split(input, ",")
 	result := make([]string, 0, len(parts))
@@ -390,3 +668,26 @@ func parseBool(s string) bool {
 	s = strings.TrimSpace(strings.ToLower(s))
 	return s == "true" || s == "1" || s == "yes" || s == "on" || s == "enabled"
 }
+
+// readEnvValue 从 .env 文件读取指定键的值
+func readEnvValue(filename, key string) string {
+	file, err := os.Open(filename)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, key+"=") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, key+"=")), `"'`)
+		}
+	}
+
+	return ""
+}
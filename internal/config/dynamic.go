@@ -3,24 +3,132 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
+	"code88reset/internal/reset"
 	"code88reset/pkg/logger"
 )
 
 const (
-	DefaultFirstResetHour      = 18
-	DefaultFirstResetMinute    = 50
-	DefaultSecondResetHour     = 23
-	DefaultSecondResetMinute   = 55
-	DefaultFirstThreshold      = 70.0
-	DefaultSecondThreshold     = 100.0
-	DefaultWebPort             = 8966
+	DefaultFirstResetHour     = 18
+	DefaultFirstResetMinute   = 50
+	DefaultSecondResetHour    = 23
+	DefaultSecondResetMinute  = 55
+	DefaultFirstThreshold     = 70.0
+	DefaultSecondThreshold    = 100.0
+	DefaultWebPort            = 8966
+	DefaultSystemLogRetention = 500    // 默认系统日志保留条数
+	MaxSystemLogRetention     = 100000 // 系统日志保留条数上限
+
+	DefaultRefreshIntervalMinutes = 30   // 默认后台订阅刷新间隔（分钟）
+	DefaultRefreshJitterSeconds   = 120  // 默认刷新随机延迟上限（秒）
+	DefaultRefreshConcurrency     = 4    // 默认刷新并发数
+	MaxRefreshIntervalMinutes     = 1440 // 刷新间隔上限（分钟）
+	MaxRefreshJitterSeconds       = 3600 // 随机延迟上限（秒）
+	MaxRefreshConcurrency         = 32   // 刷新并发数上限
+
+	DefaultAutoDisableAuthFailures = 3   // 默认连续鉴权失败 3 次后自动禁用 Token
+	MaxAutoDisableAuthFailures     = 100 // 自动禁用阈值上限
+
+	MaxExpiryLeadDays = 365 // 到期提醒提前天数上限
+
+	DefaultCreditWatchIntervalMinutes = 10   // 默认低额度轮询间隔（分钟）
+	DefaultLowCreditThreshold         = 20.0 // 默认低额度提醒阈值（%）
+	DefaultLowCreditHysteresis        = 10.0 // 默认解除提醒的回升幅度（%）
+
+	DefaultVerifyInitialDelaySeconds = 3   // 默认重置后首次核实前的等待时间（秒）
+	DefaultVerifyMaxIntervalSeconds  = 10  // 默认核实轮询间隔上限（秒）
+	DefaultVerifyTimeoutSeconds      = 30  // 默认核实截止时间（秒）
+	MaxVerifyTimeoutSeconds          = 600 // 核实截止时间上限（秒）
+
+	DefaultHTTPTimeoutSeconds = 30  // 默认 API 请求超时（秒）
+	MaxHTTPTimeoutSeconds     = 300 // API 请求超时上限（秒）
+	MaxCacheTTLSeconds        = 300 // 订阅列表缓存有效期上限（秒）
+
+	DefaultUsageIntervalMinutes = 15  // 默认用量采样间隔（分钟）
+	DefaultUsageRetentionDays   = 30  // 默认用量采样保留天数
+	MaxUsageRetentionDays       = 365 // 用量采样保留天数上限
+
+	DefaultReportHour          = 23   // 默认报告生成时间（时）
+	DefaultReportMinute        = 59   // 默认报告生成时间（分）
+	DefaultReportRetentionDays = 90   // 默认报告保留天数
+	MinReportRetentionDays     = 7    // 报告保留天数下限（周报需要 7 天的重置事件）
+	MaxReportRetentionDays     = 3650 // 报告保留天数上限
 )
 
+// DefaultExpiryAlert 返回默认的订阅到期提醒配置（提前 7/3/1 天）
+func DefaultExpiryAlert() models.ExpiryAlertConfig {
+	return models.ExpiryAlertConfig{
+		Enabled:  true,
+		LeadDays: []int{7, 3, 1},
+	}
+}
+
+// DefaultSubscriptionRefresh 返回默认的后台订阅刷新配置
+func DefaultSubscriptionRefresh() models.SubscriptionRefreshConfig {
+	return models.SubscriptionRefreshConfig{
+		Enabled:         true,
+		IntervalMinutes: DefaultRefreshIntervalMinutes,
+		JitterSeconds:   DefaultRefreshJitterSeconds,
+		Concurrency:     DefaultRefreshConcurrency,
+	}
+}
+
+// DefaultResetVerification 返回默认的重置核实配置
+func DefaultResetVerification() models.ResetVerificationConfig {
+	return models.ResetVerificationConfig{
+		InitialDelaySeconds: DefaultVerifyInitialDelaySeconds,
+		MaxIntervalSeconds:  DefaultVerifyMaxIntervalSeconds,
+		TimeoutSeconds:      DefaultVerifyTimeoutSeconds,
+	}
+}
+
+// DefaultHTTPClient 返回默认的 API 网络配置（不使用代理，超时 30 秒，订阅列表缓存 10 秒）
+func DefaultHTTPClient() models.HTTPClientConfig {
+	return models.HTTPClientConfig{
+		TimeoutSeconds:  DefaultHTTPTimeoutSeconds,
+		CacheTTLSeconds: int(api.DefaultCacheTTL / time.Second),
+	}
+}
+
+// DefaultUsageSampling 返回默认的用量采样配置（每 15 分钟采样，保留 30 天）
+func DefaultUsageSampling() models.UsageSamplingConfig {
+	return models.UsageSamplingConfig{
+		Enabled:         true,
+		IntervalMinutes: DefaultUsageIntervalMinutes,
+		RetentionDays:   DefaultUsageRetentionDays,
+	}
+}
+
+// DefaultReports 返回默认的报告配置（每天 23:59 生成日报，星期日生成周报，不发送通知）
+func DefaultReports() models.ReportConfig {
+	return models.ReportConfig{
+		Daily:         true,
+		Weekly:        true,
+		Hour:          DefaultReportHour,
+		Minute:        DefaultReportMinute,
+		Weekday:       int(time.Sunday),
+		RetentionDays: DefaultReportRetentionDays,
+	}
+}
+
+// DefaultCreditWatch 返回默认的低额度监控配置（默认不自动重置）
+func DefaultCreditWatch() models.CreditWatchConfig {
+	return models.CreditWatchConfig{
+		Enabled:           true,
+		IntervalMinutes:   DefaultCreditWatchIntervalMinutes,
+		ThresholdPercent:  DefaultLowCreditThreshold,
+		HysteresisPercent: DefaultLowCreditHysteresis,
+	}
+}
+
 // DynamicConfigManager 动态配置管理器
 type DynamicConfigManager struct {
 	configPath string
@@ -67,8 +175,17 @@ func (m *DynamicConfigManager) loadOrCreateDefault() error {
 			Minute:           DefaultSecondResetMinute,
 			ThresholdPercent: DefaultSecondThreshold,
 		},
-		Timezone: DefaultTimezone,
-		WebPort:  DefaultWebPort,
+		Timezone:                DefaultTimezone,
+		WebPort:                 DefaultWebPort,
+		SystemLogRetention:      DefaultSystemLogRetention,
+		SubscriptionRefresh:     DefaultSubscriptionRefresh(),
+		AutoDisableAuthFailures: DefaultAutoDisableAuthFailures,
+		ExpiryAlert:             DefaultExpiryAlert(),
+		CreditWatch:             DefaultCreditWatch(),
+		ResetVerification:       DefaultResetVerification(),
+		HTTPClient:              DefaultHTTPClient(),
+		UsageSampling:           DefaultUsageSampling(),
+		Reports:                 DefaultReports(),
 	}
 
 	// 保存默认配置
@@ -82,7 +199,17 @@ func (m *DynamicConfigManager) load() error {
 		return fmt.Errorf("读取配置文件失败: %w", err)
 	}
 
-	var config models.DynamicConfig
+	// 旧版配置文件缺少的配置项使用默认值
+	config := models.DynamicConfig{
+		SubscriptionRefresh:     DefaultSubscriptionRefresh(),
+		AutoDisableAuthFailures: DefaultAutoDisableAuthFailures,
+		ExpiryAlert:             DefaultExpiryAlert(),
+		CreditWatch:             DefaultCreditWatch(),
+		ResetVerification:       DefaultResetVerification(),
+		HTTPClient:              DefaultHTTPClient(),
+		UsageSampling:           DefaultUsageSampling(),
+		Reports:                 DefaultReports(),
+	}
 	if err := json.Unmarshal(data, &config); err != nil {
 		return fmt.Errorf("解析配置文件失败: %w", err)
 	}
@@ -119,6 +246,15 @@ func (m *DynamicConfigManager) save() error {
 	return nil
 }
 
+// Reload 从文件重新加载配置并通知监听器（用于恢复备份）
+func (m *DynamicConfigManager) Reload() error {
+	if err := m.load(); err != nil {
+		return err
+	}
+	m.notifyListeners(m.GetConfig())
+	return nil
+}
+
 // GetConfig 获取当前配置（只读）
 func (m *DynamicConfigManager) GetConfig() models.DynamicConfig {
 	m.mu.RLock()
@@ -130,7 +266,7 @@ func (m *DynamicConfigManager) GetConfig() models.DynamicConfig {
 func (m *DynamicConfigManager) UpdateConfig(newConfig models.DynamicConfig) error {
 	// 验证配置
 	if err := m.validateConfig(newConfig); err != nil {
-		return fmt.Errorf("配置验证失败: %w", err)
+		return i18n.Errorf("config.invalid", err)
 	}
 
 	m.mu.Lock()
@@ -153,34 +289,133 @@ func (m *DynamicConfigManager) UpdateConfig(newConfig models.DynamicConfig) erro
 func (m *DynamicConfigManager) validateConfig(config models.DynamicConfig) error {
 	// 验证第一次重置配置
 	if config.FirstReset.Hour < 0 || config.FirstReset.Hour > 23 {
-		return fmt.Errorf("第一次重置小时必须在 0-23 之间")
+		return i18n.Errorf("config.first_hour")
 	}
 	if config.FirstReset.Minute < 0 || config.FirstReset.Minute > 59 {
-		return fmt.Errorf("第一次重置分钟必须在 0-59 之间")
+		return i18n.Errorf("config.first_minute")
 	}
 	if config.FirstReset.ThresholdPercent < 0 || config.FirstReset.ThresholdPercent > 100 {
-		return fmt.Errorf("第一次重置阈值必须在 0-100 之间")
+		return i18n.Errorf("config.first_threshold")
+	}
+	if err := reset.ValidateDisabledRules(config.FirstReset.DisabledRules); err != nil {
+		return i18n.Errorf("config.first_reset_invalid", err)
 	}
 
 	// 验证第二次重置配置
 	if config.SecondReset.Hour < 0 || config.SecondReset.Hour > 23 {
-		return fmt.Errorf("第二次重置小时必须在 0-23 之间")
+		return i18n.Errorf("config.second_hour")
 	}
 	if config.SecondReset.Minute < 0 || config.SecondReset.Minute > 59 {
-		return fmt.Errorf("第二次重置分钟必须在 0-59 之间")
+		return i18n.Errorf("config.second_minute")
 	}
 	if config.SecondReset.ThresholdPercent < 0 || config.SecondReset.ThresholdPercent > 100 {
-		return fmt.Errorf("第二次重置阈值必须在 0-100 之间")
+		return i18n.Errorf("config.second_threshold")
+	}
+	if err := reset.ValidateDisabledRules(config.SecondReset.DisabledRules); err != nil {
+		return i18n.Errorf("config.second_reset_invalid", err)
 	}
 
 	// 验证 Web 端口
 	if config.WebPort < 1 || config.WebPort > 65535 {
-		return fmt.Errorf("Web 端口必须在 1-65535 之间")
+		return i18n.Errorf("config.web_port")
 	}
 
 	// 验证时区
 	if config.Timezone == "" {
-		return fmt.Errorf("时区不能为空")
+		return i18n.Errorf("config.timezone_required")
+	}
+
+	// 验证系统日志保留条数（0 表示使用默认值）
+	if config.SystemLogRetention < 0 || config.SystemLogRetention > MaxSystemLogRetention {
+		return i18n.Errorf("config.log_retention", MaxSystemLogRetention)
+	}
+
+	// 验证后台订阅刷新配置
+	refresh := config.SubscriptionRefresh
+	if refresh.Enabled && (refresh.IntervalMinutes < 1 || refresh.IntervalMinutes > MaxRefreshIntervalMinutes) {
+		return i18n.Errorf("config.refresh_interval", MaxRefreshIntervalMinutes)
+	}
+	if refresh.JitterSeconds < 0 || refresh.JitterSeconds > MaxRefreshJitterSeconds {
+		return i18n.Errorf("config.refresh_jitter", MaxRefreshJitterSeconds)
+	}
+	if refresh.Concurrency < 0 || refresh.Concurrency > MaxRefreshConcurrency {
+		return i18n.Errorf("config.refresh_concurrency", MaxRefreshConcurrency)
+	}
+
+	// 验证自动禁用阈值（0 表示不自动禁用）
+	if config.AutoDisableAuthFailures < 0 || config.AutoDisableAuthFailures > MaxAutoDisableAuthFailures {
+		return i18n.Errorf("config.auto_disable", MaxAutoDisableAuthFailures)
+	}
+
+	// 验证到期提醒天数
+	for _, days := range config.ExpiryAlert.LeadDays {
+		if days < 1 || days > MaxExpiryLeadDays {
+			return i18n.Errorf("config.expiry_lead_days", MaxExpiryLeadDays)
+		}
+	}
+
+	// 验证低额度监控配置
+	watch := config.CreditWatch
+	if watch.Enabled && (watch.IntervalMinutes < 1 || watch.IntervalMinutes > MaxRefreshIntervalMinutes) {
+		return i18n.Errorf("config.credit_watch_interval", MaxRefreshIntervalMinutes)
+	}
+	if watch.ThresholdPercent < 0 || watch.ThresholdPercent > 100 {
+		return i18n.Errorf("config.credit_watch_threshold")
+	}
+	if watch.HysteresisPercent < 0 || watch.HysteresisPercent > 100 {
+		return i18n.Errorf("config.credit_watch_hysteresis")
+	}
+
+	// 验证重置核实配置
+	verify := config.ResetVerification
+	if verify.TimeoutSeconds < 1 || verify.TimeoutSeconds > MaxVerifyTimeoutSeconds {
+		return i18n.Errorf("config.verify_timeout", MaxVerifyTimeoutSeconds)
+	}
+	if verify.InitialDelaySeconds < 1 || verify.InitialDelaySeconds > verify.TimeoutSeconds {
+		return i18n.Errorf("config.verify_initial_delay", verify.TimeoutSeconds)
+	}
+	if verify.MaxIntervalSeconds < 1 || verify.MaxIntervalSeconds > verify.TimeoutSeconds {
+		return i18n.Errorf("config.verify_max_interval", verify.TimeoutSeconds)
+	}
+
+	// 验证用量采样配置
+	usage := config.UsageSampling
+	if usage.Enabled && (usage.IntervalMinutes < 1 || usage.IntervalMinutes > MaxRefreshIntervalMinutes) {
+		return i18n.Errorf("config.usage_interval", MaxRefreshIntervalMinutes)
+	}
+	if usage.RetentionDays < 1 || usage.RetentionDays > MaxUsageRetentionDays {
+		return i18n.Errorf("config.usage_retention", MaxUsageRetentionDays)
+	}
+
+	// 验证报告配置
+	reports := config.Reports
+	if reports.Hour < 0 || reports.Hour > 23 || reports.Minute < 0 || reports.Minute > 59 {
+		return i18n.Errorf("config.report_time")
+	}
+	if reports.Weekday < 0 || reports.Weekday > 6 {
+		return i18n.Errorf("config.report_weekday")
+	}
+	if reports.RetentionDays < MinReportRetentionDays || reports.RetentionDays > MaxReportRetentionDays {
+		return i18n.Errorf("config.report_retention", MinReportRetentionDays, MaxReportRetentionDays)
+	}
+
+	// 验证 API 网络配置（会实际加载证书文件）
+	if t := config.HTTPClient.TimeoutSeconds; t < 0 || t > MaxHTTPTimeoutSeconds {
+		return i18n.Errorf("config.http_timeout", MaxHTTPTimeoutSeconds)
+	}
+	if t := config.HTTPClient.CacheTTLSeconds; t < 0 || t > MaxCacheTTLSeconds {
+		return i18n.Errorf("config.http_cache_ttl", MaxCacheTTLSeconds)
+	}
+	if _, err := api.NewClientOptions(config.HTTPClient); err != nil {
+		return i18n.Errorf("config.http_client_invalid", err)
+	}
+
+	// 验证通知 Webhook 地址
+	if hook := config.Notifications.WebhookURL; hook != "" {
+		u, err := url.Parse(hook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return i18n.Errorf("config.webhook_url", hook)
+		}
 	}
 
 	return nil
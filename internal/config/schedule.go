@@ -0,0 +1,92 @@
+package config
+
+import (
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// NextReset 按配置的时区计算下一次定时重置的时间和类型（first/second），两个调度项都停用时返回零值
+func NextReset(cfg models.DynamicConfig, now time.Time) (time.Time, string) {
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+	nowInTZ := now.In(loc)
+
+	var nextResetTime time.Time
+	var nextResetType string
+
+	// 检查第一次重置
+	if cfg.FirstReset.Enabled {
+		firstToday := time.Date(nowInTZ.Year(), nowInTZ.Month(), nowInTZ.Day(),
+			cfg.FirstReset.Hour, cfg.FirstReset.Minute, 0, 0, loc)
+		if nowInTZ.Before(firstToday) {
+			nextResetTime = firstToday
+			nextResetType = "first"
+		}
+	}
+
+	// 检查第二次重置
+	if cfg.SecondReset.Enabled {
+		secondToday := time.Date(nowInTZ.Year(), nowInTZ.Month(), nowInTZ.Day(),
+			cfg.SecondReset.Hour, cfg.SecondReset.Minute, 0, 0, loc)
+		if nowInTZ.Before(secondToday) {
+			if nextResetTime.IsZero() || secondToday.Before(nextResetTime) {
+				nextResetTime = secondToday
+				nextResetType = "second"
+			}
+		}
+	}
+
+	// 如果今天没有下次重置，计算明天的
+	if nextResetTime.IsZero() {
+		tomorrow := nowInTZ.AddDate(0, 0, 1)
+		if cfg.FirstReset.Enabled {
+			nextResetTime = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(),
+				cfg.FirstReset.Hour, cfg.FirstReset.Minute, 0, 0, loc)
+			nextResetType = "first"
+		} else if cfg.SecondReset.Enabled {
+			nextResetTime = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(),
+				cfg.SecondReset.Hour, cfg.SecondReset.Minute, 0, 0, loc)
+			nextResetType = "second"
+		}
+	}
+
+	return nextResetTime, nextResetType
+}
+
+// NextReport 按配置的时区计算下一次生成报告的时间及要生成的周期（daily/weekly），日报和周报都停用时返回零值
+func NextReport(cfg models.DynamicConfig, now time.Time) (time.Time, []string) {
+	reports := cfg.Reports
+	if !reports.Daily && !reports.Weekly {
+		return time.Time{}, nil
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+	nowInTZ := now.In(loc)
+
+	// 最多向后查找 8 天，保证周报生成日总能找到
+	for i := 0; i <= 7; i++ {
+		day := nowInTZ.AddDate(0, 0, i)
+		at := time.Date(day.Year(), day.Month(), day.Day(), reports.Hour, reports.Minute, 0, 0, loc)
+		if !at.After(nowInTZ) {
+			continue
+		}
+
+		var periods []string
+		if reports.Daily {
+			periods = append(periods, models.ReportDaily)
+		}
+		if reports.Weekly && int(at.Weekday()) == reports.Weekday {
+			periods = append(periods, models.ReportWeekly)
+		}
+		if len(periods) > 0 {
+			return at, periods
+		}
+	}
+	return time.Time{}, nil
+}
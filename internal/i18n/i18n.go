@@ -0,0 +1,269 @@
+// Package i18n 管理界面、API 和系统日志消息的多语言文案（zh-CN、en）
+//
+// 每条消息由稳定的消息码标识（如 "api.request_failed"），文案按语言保存在消息目录中。
+// 模板参数一律使用 %s，渲染前统一转为字符串：error、Label 参数按目标语言渲染，数字等需要
+// 特定格式的参数由调用方事先格式化。
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Locale 语言
+type Locale string
+
+// 支持的语言
+const (
+	ZhCN Locale = "zh-CN"
+	EN   Locale = "en"
+)
+
+// Locales 全部支持的语言
+var Locales = []Locale{ZhCN, EN}
+
+// catalogue 各语言的消息目录，消息码 → 模板
+var catalogue = map[Locale]map[string]string{
+	ZhCN: zhCN,
+	EN:   en,
+}
+
+var defaultLocale atomic.Value // Locale
+
+// SetDefault 设置默认语言，用于系统日志、命令行输出以及未指定语言的 API 请求
+func SetDefault(locale Locale) {
+	defaultLocale.Store(locale)
+}
+
+// Default 当前默认语言，未设置时为 zh-CN
+func Default() Locale {
+	if locale, ok := defaultLocale.Load().(Locale); ok {
+		return locale
+	}
+	return ZhCN
+}
+
+// Parse 解析语言标签，支持 zh、zh-CN、zh_Hans、en、en-US 等写法
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	switch primary {
+	case "zh":
+		return ZhCN, true
+	case "en":
+		return EN, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage 按 Accept-Language 的权重选择支持的语言
+func FromAcceptLanguage(header string) (Locale, bool) {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{locale, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale, true
+}
+
+// T 按语言渲染消息，目标语言缺少该消息时回退到 zh-CN，消息码未知时原样返回消息码和参数
+func T(locale Locale, code string, args ...interface{}) string {
+	template, ok := catalogue[locale][code]
+	if !ok {
+		template, ok = catalogue[ZhCN][code]
+	}
+	strs := make([]interface{}, len(args))
+	for i, arg := range args {
+		strs[i] = render(locale, arg)
+	}
+	if !ok {
+		if len(strs) == 0 {
+			return code
+		}
+		return code + ": " + fmt.Sprint(strs...)
+	}
+	return fmt.Sprintf(template, strs...)
+}
+
+// ArgCount 消息需要的参数个数，消息码未知时返回 -1
+func ArgCount(code string) int {
+	template, ok := catalogue[ZhCN][code]
+	if !ok {
+		return -1
+	}
+	return strings.Count(strings.ReplaceAll(template, "%%", ""), "%s")
+}
+
+// Label 作为模板参数的固定文案（消息码），按目标语言渲染，如重置类型“第一次/first”
+type Label string
+
+// render 将模板参数转为目标语言的字符串
+func render(locale Locale, arg interface{}) string {
+	switch v := arg.(type) {
+	case Label:
+		return T(locale, string(v))
+	case Message:
+		return v.Localize(locale)
+	case error:
+		return Localize(v, locale)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Message 带消息码的消息，参数已转为字符串，可持久化后按任意语言重新渲染
+type Message struct {
+	Code string `json:"code"`
+	Args []Arg  `json:"args,omitempty"`
+}
+
+// Arg 持久化的消息参数：普通文本、渲染时再翻译的 Label，或嵌套的消息（如被包装的错误）
+type Arg struct {
+	Text    string   `json:"text,omitempty"`
+	Label   string   `json:"label,omitempty"`
+	Message *Message `json:"message,omitempty"`
+}
+
+// Coded 可转换为带消息码消息的值，作为 M 的参数时保留消息码以便按其他语言渲染
+type Coded interface {
+	CodedMessage() Message
+}
+
+// M 创建消息，Label、Message 和 Coded 参数保留消息码，其他参数按默认语言转为字符串
+func M(code string, args ...interface{}) Message {
+	msg := Message{Code: code}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case Label:
+			msg.Args = append(msg.Args, Arg{Label: string(v)})
+		case Message:
+			msg.Args = append(msg.Args, Arg{Message: &v})
+		case Coded:
+			nested := v.CodedMessage()
+			msg.Args = append(msg.Args, Arg{Message: &nested})
+		default:
+			msg.Args = append(msg.Args, Arg{Text: render(Default(), arg)})
+		}
+	}
+	return msg
+}
+
+// Localize 按语言渲染消息
+func (m Message) Localize(locale Locale) string {
+	args := make([]interface{}, len(m.Args))
+	for i, arg := range m.Args {
+		switch {
+		case arg.Message != nil:
+			args[i] = *arg.Message
+		case arg.Label != "":
+			args[i] = Label(arg.Label)
+		default:
+			args[i] = arg.Text
+		}
+	}
+	return T(locale, m.Code, args...)
+}
+
+// String 按默认语言渲染消息
+func (m Message) String() string {
+	return m.Localize(Default())
+}
+
+// Error 带消息码的错误，Error() 按默认语言渲染，参数中的第一个 error 作为被包装的错误
+type Error struct {
+	Code string
+	Args []interface{}
+}
+
+// Errorf 创建带消息码的错误
+func Errorf(code string, args ...interface{}) error {
+	return &Error{Code: code, Args: args}
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	return T(Default(), e.Code, e.Args...)
+}
+
+// Localize 按语言渲染错误
+func (e *Error) Localize(locale Locale) string {
+	return T(locale, e.Code, e.Args...)
+}
+
+// CodedMessage 转换为可持久化的消息
+func (e *Error) CodedMessage() Message {
+	return M(e.Code, e.Args...)
+}
+
+// Unwrap 返回被包装的错误，支持 errors.Is / errors.As
+func (e *Error) Unwrap() error {
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// Localizer 可按语言渲染的错误
+type Localizer interface {
+	Localize(locale Locale) string
+}
+
+// Localize 按语言渲染错误：最外层实现了 Localizer（如 *Error）时使用消息目录，其他错误原样返回 Error()
+func Localize(err error, locale Locale) string {
+	if l, ok := err.(Localizer); ok {
+		return l.Localize(locale)
+	}
+	return err.Error()
+}
+
+// MessageOf 将错误转换为可持久化的消息：带消息码的错误保留消息码，其他错误使用 "text" 原样保存
+func MessageOf(err error) Message {
+	if c, ok := err.(Coded); ok {
+		return c.CodedMessage()
+	}
+	return M(TextCode, err.Error())
+}
+
+// TextCode 不需要翻译的原样文本
+const TextCode = "text"
+
+// CodeOf 错误链中第一个带消息码的错误的消息码，没有时返回空字符串
+func CodeOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
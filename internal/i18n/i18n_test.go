@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var otherVerbs = regexp.MustCompile(`%[^s]|%$`)
+
+func TestCatalogueConsistency(t *testing.T) {
+	for _, locale := range Locales {
+		for code, template := range catalogue[locale] {
+			if otherVerbs.MatchString(strings.ReplaceAll(template, "%%", "")) {
+				t.Errorf("%s %s: templates may only use %%s: %q", locale, code, template)
+			}
+		}
+	}
+	for code, zh := range catalogue[ZhCN] {
+		en, ok := catalogue[EN][code]
+		if !ok {
+			t.Errorf("en is missing %s", code)
+			continue
+		}
+		if want, got := ArgCount(code), strings.Count(strings.ReplaceAll(en, "%%", ""), "%s"); want != got {
+			t.Errorf("%s: zh-CN has %d args (%q), en has %d (%q)", code, want, zh, got, en)
+		}
+	}
+	for code := range catalogue[EN] {
+		if _, ok := catalogue[ZhCN][code]; !ok {
+			t.Errorf("zh-CN is missing %s", code)
+		}
+	}
+}
+
+func TestParseAndAcceptLanguage(t *testing.T) {
+	for tag, want := range map[string]Locale{"zh": ZhCN, "zh_Hans": ZhCN, "ZH-tw": ZhCN, "en-US": EN, " en ": EN} {
+		if got, ok := Parse(tag); !ok || got != want {
+			t.Errorf("Parse(%q) = %q, %v", tag, got, ok)
+		}
+	}
+	if _, ok := Parse("fr"); ok {
+		t.Error("fr should not be supported")
+	}
+
+	tests := map[string]Locale{
+		"en-US,en;q=0.9":               EN,
+		"fr-FR, zh-CN;q=0.8, en;q=0.5": ZhCN,
+		"zh;q=0.2, en;q=0.7":           EN,
+		"zh;q=0, en":                   EN,
+	}
+	for header, want := range tests {
+		if got, ok := FromAcceptLanguage(header); !ok || got != want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, %v, want %q", header, got, ok, want)
+		}
+	}
+	if _, ok := FromAcceptLanguage("fr, de;q=0.5"); ok {
+		t.Error("unsupported languages should not match")
+	}
+}
+
+func TestMessageRoundTrip(t *testing.T) {
+	inner := Errorf("api.request_failed", errors.New("timeout"))
+	msg := M("scheduler.run_failed", Label("reset.type.first"), inner)
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var restored Message
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := restored.Localize(EN), "The first reset failed: Request failed: timeout"; got != want {
+		t.Errorf("en = %q, want %q", got, want)
+	}
+	if got, want := restored.Localize(ZhCN), "执行第一次重置失败: 请求失败: timeout"; got != want {
+		t.Errorf("zh-CN = %q, want %q", got, want)
+	}
+}
+
+func TestError(t *testing.T) {
+	base := Errorf("token.no_eligible_subscription")
+	err := Errorf("token.no_eligible_subscription_hint", base)
+
+	if !errors.Is(err, base) {
+		t.Error("wrapped error should match with errors.Is")
+	}
+	if CodeOf(err) != "token.no_eligible_subscription_hint" {
+		t.Errorf("CodeOf = %q", CodeOf(err))
+	}
+	if got := Localize(err, EN); got != "No eligible subscription found (a MONTHLY, non-PAYGO subscription is required)" {
+		t.Errorf("Localize(en) = %q", got)
+	}
+	if got := Localize(errors.New("plain"), EN); got != "plain" {
+		t.Errorf("plain errors are returned as is, got %q", got)
+	}
+	if msg := MessageOf(errors.New("plain")); msg.Code != TextCode || msg.Localize(EN) != "plain" {
+		t.Errorf("MessageOf(plain) = %+v", msg)
+	}
+	if got := T(EN, "unknown.code", 1); got != "unknown.code: 1" {
+		t.Errorf("unknown code = %q", got)
+	}
+}
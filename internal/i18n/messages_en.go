@@ -0,0 +1,306 @@
+package i18n
+
+// en 英文消息目录
+var en = map[string]string{
+	// common
+	"text": "%s",
+
+	// api
+	"api.subscription_not_found":         "Subscription ID=%s not found",
+	"api.marshal_request_failed":         "Failed to encode request body: %s",
+	"api.create_request_failed":          "Failed to create request: %s",
+	"api.request_failed":                 "Request failed: %s",
+	"api.read_response_failed":           "Failed to read response: %s",
+	"api.upstream_error":                 "API error [%s]: %s",
+	"api.http_error":                     "Request failed with status %s, response: %s",
+	"api.error":                          "API error [%s]: %s (status: %s)",
+	"api.parse_usage_failed":             "Failed to parse usage response: %s",
+	"api.parse_subscriptions_failed":     "Failed to parse subscription list: %s",
+	"api.subscriptions_rejected":         "Failed to get subscriptions: %s (code: %s)",
+	"api.target_subscription_not_found":  "No matching target subscription (target plans: %s)",
+	"api.paygo_protected":                "Reset refused: PAYGO subscriptions must not be reset",
+	"api.paygo_refused":                  "🚨 %s (subscription ID=%s, name=%s, type=%s)",
+	"api.parse_reset_failed":             "Failed to parse reset response: %s",
+	"api.reset_limited":                  "Reset failed: %s (already reset today or less than 5 hours since the last reset)",
+	"api.reset_rejected":                 "Reset failed: %s (code: %s)",
+	"api.connection_test_failed":         "Connection test failed: %s",
+	"api.account_info_failed":            "Failed to get account info: %s",
+	"api.no_subscriptions":               "No subscriptions found",
+	"api.invalid_proxy":                  "Invalid proxy URL: %s",
+	"api.unsupported_proxy_scheme":       "Unsupported proxy scheme: %s (supported: http, https, socks5)",
+	"api.client_cert_incomplete":         "Client certificate and key must be set together",
+	"api.read_ca_failed":                 "Failed to read CA certificate: %s",
+	"api.invalid_ca_pem":                 "No valid PEM certificate in CA file: %s",
+	"api.load_client_cert_failed":        "Failed to load client certificate: %s",
+	"api.log.usage_call":                 "Calling 88code API: get usage",
+	"api.log.usage_failed":               "88code API call failed: get usage - %s",
+	"api.log.usage_parse_failed":         "Failed to parse 88code API response: usage - %s",
+	"api.log.usage_succeeded":            "88code API call succeeded: got usage (credits=%s/%s)",
+	"api.log.subscriptions_call":         "Calling 88code API: list subscriptions",
+	"api.log.subscriptions_failed":       "88code API call failed: list subscriptions - %s",
+	"api.log.subscriptions_parse_failed": "Failed to parse 88code API response: %s",
+	"api.log.subscriptions_rejected":     "88code API returned an error: %s (code: %s)",
+	"api.log.subscriptions_succeeded":    "88code API call succeeded: got %s subscriptions",
+	"api.log.paygo_refused":              "🚨 Reset refused: subscription ID=%s (name=%s, type=%s) is PAYGO and must not be reset",
+	"api.log.reset_call":                 "Calling 88code API: reset credits (subscription ID=%s)",
+	"api.log.reset_failed":               "88code API call failed: reset subscription ID=%s - %s",
+	"api.log.reset_parse_failed":         "Failed to parse 88code API response: subscription ID=%s - %s",
+	"api.log.reset_limited":              "88code API reset limited: subscription ID=%s - %s (already reset today or less than 5 hours since the last reset)",
+	"api.log.reset_rejected":             "88code API reset failed: subscription ID=%s - %s (code: %s)",
+	"api.log.reset_succeeded":            "88code API call succeeded: subscription ID=%s reset - %s",
+
+	// reset
+	"reset.type.first":                           "first",
+	"reset.type.second":                          "second",
+	"reset.type.low_credit":                      "low-credit",
+	"reset.rule_mandatory":                       "Rule %s cannot be disabled",
+	"reset.rule_unknown":                         "Unknown reset rule: %s",
+	"rule.paygo_protected":                       "PAYGO subscriptions must not be reset",
+	"rule.reset_times_insufficient":              "Not enough resetTimes (need >= %s)",
+	"rule.credit_sufficient":                     "Enough credits left (%s%% %s %s%%)",
+	"rule.reset_interval":                        "Less than %s hours since the last reset (%s to go, eligible from %s)",
+	"rule.already_reset_today":                   "The %s reset has already run today",
+	"reset.verify_failed":                        "Failed to verify the status of subscription %s: %s",
+	"reset.request_unconfirmed":                  "Reset request failed and could not be confirmed within %s (will not retry): %s",
+	"reset.pending":                              "Reset request sent but not confirmed within %s, refresh later to check (will not retry)",
+	"reset.confirmed_by_verification":            "Reset request result was unknown, verification confirmed it applied",
+	"reset.not_applied_no_times":                 "Reset did not take effect and resetTimes=%s is not enough to retry",
+	"reset.not_applied":                          "Reset still not applied after retrying (resetTimes=%s, credits=%s)",
+	"reset.rule_description.paygo":               "PAYGO (pay-as-you-go) subscriptions are never reset",
+	"reset.rule_description.reset_times":         "The first reset needs resetTimes >= 2, the second needs >= 1",
+	"reset.rule_description.credit_threshold":    "Skip when the credit ratio reaches the schedule threshold",
+	"reset.rule_description.reset_interval":      "Skip when less than 5 hours have passed since the last reset recorded by the server",
+	"reset.rule_description.already_reset_today": "Each schedule runs at most once per day",
+
+	// config
+	"config.invalid":                 "Invalid configuration: %s",
+	"config.first_hour":              "First reset hour must be between 0 and 23",
+	"config.first_minute":            "First reset minute must be between 0 and 59",
+	"config.first_threshold":         "First reset threshold must be between 0 and 100",
+	"config.first_reset_invalid":     "Invalid first reset settings: %s",
+	"config.second_hour":             "Second reset hour must be between 0 and 23",
+	"config.second_minute":           "Second reset minute must be between 0 and 59",
+	"config.second_threshold":        "Second reset threshold must be between 0 and 100",
+	"config.second_reset_invalid":    "Invalid second reset settings: %s",
+	"config.web_port":                "Web port must be between 1 and 65535",
+	"config.timezone_required":       "Timezone is required",
+	"config.log_retention":           "System log retention must be between 0 and %s",
+	"config.refresh_interval":        "Subscription refresh interval must be between 1 and %s minutes",
+	"config.refresh_jitter":          "Subscription refresh jitter must be between 0 and %s seconds",
+	"config.refresh_concurrency":     "Subscription refresh concurrency must be between 0 and %s",
+	"config.auto_disable":            "Auto-disable threshold must be between 0 and %s",
+	"config.expiry_lead_days":        "Expiry alert days must be between 1 and %s",
+	"config.credit_watch_interval":   "Low credit check interval must be between 1 and %s minutes",
+	"config.credit_watch_threshold":  "Low credit threshold must be between 0 and 100",
+	"config.credit_watch_hysteresis": "Low credit recovery margin must be between 0 and 100",
+	"config.verify_timeout":          "Reset verification timeout must be between 1 and %s seconds",
+	"config.verify_initial_delay":    "Reset verification initial delay must be between 1 and %s seconds",
+	"config.verify_max_interval":     "Reset verification max poll interval must be between 1 and %s seconds",
+	"config.usage_interval":          "Usage sampling interval must be between 1 and %s minutes",
+	"config.usage_retention":         "Usage retention days must be between 1 and %s",
+	"config.http_cache_ttl":          "Subscription cache TTL must be between 0 and %s seconds (0 disables caching)",
+	"config.http_timeout":            "API request timeout must be between 0 and %s seconds (0 means the default of 30 seconds)",
+	"config.http_client_invalid":     "Invalid API network settings: %s",
+	"config.webhook_url":             "Invalid notification webhook URL: %s",
+	"config.unknown_locale":          "Unsupported locale: %s (one of zh-CN, en)",
+	"config.report_time":             "Invalid report time (hour 0-23, minute 0-59)",
+	"config.report_weekday":          "Weekly report day must be between 0 and 6 (0 is Sunday)",
+	"config.report_retention":        "Report retention days must be between %s and %s",
+
+	// auth
+	"auth.invalid_token":   "Invalid service token",
+	"auth.token_expired":   "Service token expired",
+	"auth.token_revoked":   "Service token revoked",
+	"auth.token_not_found": "Service token not found",
+	"auth.scope_required":  "At least one scope is required",
+	"auth.unknown_scope":   "Unknown scope: %s (available: %s)",
+	"auth.name_required":   "Name is required",
+	"auth.expiry_in_past":  "Expiry must be in the future",
+
+	// token
+	"token.not_found":                       "Token not found: %s",
+	"token.id_exists":                       "Token ID already exists: %s",
+	"token.disabled":                        "Token is disabled",
+	"token.get_subscriptions_failed":        "Failed to get subscriptions: %s",
+	"token.no_subscriptions":                "No subscriptions are associated with this API key",
+	"token.no_eligible_subscription":        "No eligible subscription found",
+	"token.no_eligible_subscription_hint":   "%s (a MONTHLY, non-PAYGO subscription is required)",
+	"token.reset_failed":                    "Reset failed: %s",
+	"token.nothing_reset":                   "No subscription was reset",
+	"token.invalid_upstream":                "Invalid token upstream settings: %s",
+	"token.invalid_base_url":                "Invalid upstream URL: %s",
+	"token.invalid_credit_threshold":        "Low credit threshold must be between 0 and 100",
+	"token.unknown_conflict_mode":           "Unknown conflict mode: %s (one of skip/overwrite/merge)",
+	"token.refresh_in_progress":             "A bulk refresh is already in progress",
+	"token.duplicate":                       "Duplicate account: %s (existing token: %s)",
+	"token.duplicate_reason.token_id":       "Token ID already exists",
+	"token.duplicate_reason.api_key":        "API key already exists",
+	"token.duplicate_reason.employee_id":    "Employee ID already exists",
+	"token.duplicate_reason.employee_email": "Employee email already exists",
+	"token.no_duplicates":                   "Token %s has no duplicates",
+	"token.merge_mismatch":                  "Tokens %s and %s belong to different accounts, refusing to merge",
+	"token.subscription_expired":            "Subscription has expired, reset skipped",
+	"token.subscription_ended":              "Subscription expired on %s",
+	"token.subscription_expired_alert":      "The subscription of token %s expired on %s, automatic resets have stopped",
+	"token.subscription_expiring":           "The subscription of token %s expires in %s days (%s), please renew",
+	"token.credit_recovered":                "Token %s credits recovered to %s%%, low credit alert cleared",
+	"token.credit_low":                      "Token %s has only %s%% credits left (threshold %s%%)",
+	"token.credit_low_blocked":              "Token %s has only %s%% credits left (threshold %s%%), cannot reset now: %s",
+	"token.reset_times_exhausted":           "No resets left",
+	"token.reset_times_reserved":            "The remaining reset is reserved for the scheduled second reset",
+	"token.reset_interval_pending":          "Less than %s since the last reset, eligible from %s",
+	"token.reset_skipped":                   "Skipped: %s",
+	"token.reset_succeeded":                 "Reset succeeded (%s → %s, resetTimes: %s → %s)",
+	"token.health_changed":                  "Token %s health: %s → %s",
+	"token.health_changed_reason":           "Token %s health: %s → %s (%s)",
+	"token.auto_disabled":                   "Token %s failed authentication %s times in a row and was disabled: %s",
+	"token.log.duplicate_rejected":          "Rejected duplicate token %s: %s",
+	"token.log.merged":                      "Merged %s duplicate tokens into %s",
+	"token.log.refresh_summary":             "Bulk subscription refresh finished: %s total, %s succeeded, %s failed (%s invalid keys)",
+	"token.log.reset_succeeded":             "Token %s reset succeeded: %s",
+	"token.log.reset_incomplete":            "Token %s reset incomplete: %s",
+	"token.log.reset_pending":               "Token %s reset pending verification: %s",
+	"token.log.low_credit_reset":            "Token %s credits below %s%%, resetting now",
+	"token.log.low_credit_reset_failed":     "Immediate low credit reset of token %s failed: %s",
+	"token.log.reset_failed":                "Token %s reset failed: %s",
+	"token.api_key_required":                "API key is missing",
+	"token.notify_title.expiry":             "88code subscription expiry alert",
+	"token.notify_title.low_credit":         "88code low credit alert",
+
+	// scheduler
+	"scheduler.triggered":        "%s reset triggered",
+	"scheduler.skipped":          "%s, skipped",
+	"scheduler.run_failed":       "The %s reset failed: %s",
+	"scheduler.no_subscriptions": "%s reset: no subscriptions to process",
+	"scheduler.reset_failed":     "%s reset failed: [%s] %s",
+	"scheduler.reset_skipped":    "%s reset skipped: [%s] %s",
+	"scheduler.reset_succeeded":  "%s reset succeeded: [%s] %s",
+	"scheduler.run_started":      "Scheduled %s reset started: %s enabled tokens (%s expired skipped)",
+	"scheduler.run_finished":     "Scheduled %s reset finished: %s succeeded, %s failed/skipped",
+
+	// backup
+	"backup.log.created":  "Backup created: %s files",
+	"backup.log.restored": "Data restored from backup: %s files (backup time: %s)",
+
+	// web
+	"web.not_found":                    "Not found",
+	"web.method_not_allowed":           "Method not allowed",
+	"web.missing_authorization":        "Missing authorization header",
+	"web.invalid_authorization":        "Invalid authorization header format",
+	"web.invalid_token":                "Invalid token",
+	"web.token_expired":                "Token expired",
+	"web.token_revoked":                "Token revoked",
+	"web.verify_token_failed":          "Failed to verify token: %s",
+	"web.admin_token_required":         "Admin token required",
+	"web.missing_scope":                "Missing scope: %s",
+	"web.invalid_json":                 "Invalid JSON: %s",
+	"web.request_too_large":            "Request body too large (limit %s bytes)",
+	"web.rate_limited":                 "Too many requests",
+	"web.rate_limited_token":           "Too many requests for this token",
+	"web.action_cooling_down":          "Action %s is cooling down",
+	"web.token_not_found":              "Token not found",
+	"web.duplicate_token":              "Duplicate token: %s",
+	"web.token_id_required":            "Token ID is required",
+	"web.unknown_operation":            "Unknown operation",
+	"web.operation_required":           "Operation is required",
+	"web.api_key_required":             "API Key is required",
+	"web.api_keys_required":            "API Keys are required",
+	"web.add_token_failed":             "Failed to add token: %s",
+	"web.token_added":                  "Token added successfully",
+	"web.batch_duplicate":              "Duplicate of %s in this batch",
+	"web.batch_added":                  "Batch add finished: %s added, %s duplicates, %s failed",
+	"web.token_deleted":                "Token deleted successfully",
+	"web.token_toggled":                "Token status toggled",
+	"web.token_settings_updated":       "Token settings updated",
+	"web.refresh_failed":               "Failed to refresh subscription: %s",
+	"web.subscription_refreshed":       "Subscription refreshed",
+	"web.refresh_all_failed":           "Failed to refresh subscriptions: %s",
+	"web.refresh_all_completed":        "Bulk refresh finished: %s succeeded, %s failed",
+	"web.reset_failed":                 "Reset failed: %s",
+	"web.reset_completed":              "The %s reset completed",
+	"web.invalid_reset_type":           "Invalid reset_type, must be 'first' or 'second'",
+	"web.no_enabled_tokens":            "No enabled tokens",
+	"web.subscription_expired_skipped": "Subscription expired on %s, reset skipped",
+	"web.keep_id_required":             "keep_id is required",
+	"web.merge_failed":                 "Merge failed: %s",
+	"web.duplicates_merged":            "Duplicate tokens merged successfully",
+	"web.invalid_page_size":            "Invalid page_size",
+	"web.invalid_page":                 "Invalid page",
+	"web.invalid_since":                "Invalid since: %s",
+	"web.invalid_until":                "Invalid until: %s",
+	"web.invalid_log_format":           "Invalid format, must be 'csv' or 'ndjson'",
+	"web.load_logs_failed":             "Failed to load system logs: %s",
+	"web.clear_logs_failed":            "Failed to clear system logs: %s",
+	"web.delete_logs_failed":           "Failed to delete system logs: %s",
+	"web.logs_cleared":                 "System logs cleared successfully",
+	"web.logs_deleted":                 "Deleted %s system logs",
+	"web.invalid_time":                 "Invalid time %s: use RFC3339 or YYYY-MM-DD",
+	"web.invalid_usage_range":          "from must be before to",
+	"web.invalid_usage_points":         "points must be between 2 and %s",
+	"web.load_usage_failed":            "Failed to load usage: %s",
+	"web.config_update_failed":         "Configuration update failed: %s",
+	"web.config_updated":               "Configuration updated successfully",
+	"web.archive_required":             "Archive is required",
+	"web.export_failed":                "Export failed: %s",
+	"web.import_failed":                "Import failed: %s",
+	"web.import_completed":             "Import completed: added %s, overwritten %s, merged %s, skipped %s, failed %s",
+	"web.backup_failed":                "Backup failed: %s",
+	"web.restore_failed":               "Restore failed: %s",
+	"web.restored":                     "Restored %s files",
+	"web.audit_log_failed":             "Failed to write audit log: %s",
+	"web.service_tokens_disabled":      "Service tokens are not enabled",
+	"web.invalid_expires_in_days":      "expires_in_days must be positive",
+	"web.create_service_token_failed":  "Failed to create service token: %s",
+	"web.service_token_created":        "Service token created, the secret is only shown once",
+	"web.service_token_not_found":      "Service token not found",
+	"web.revoke_service_token_failed":  "Failed to revoke service token: %s",
+	"web.service_token_revoked":        "Service token revoked",
+	"web.reports_disabled":             "Reset reports are not enabled",
+	"web.invalid_report_period":        "Invalid period, must be 'daily' or 'weekly'",
+	"web.invalid_report_format":        "Invalid format, must be 'json', 'markdown' or 'html'",
+	"web.report_not_found":             "Report not found",
+	"web.load_reports_failed":          "Failed to load reports: %s",
+	"web.generate_report_failed":       "Failed to generate report: %s",
+	"web.report_generated":             "Report %s generated",
+	"web.audit.reveal_api_key":         "Full API key of %s viewed via web API (from %s)",
+	"web.audit.service_token_created":  "Service token %s (%s) created via web API with scopes %s (from %s)",
+	"web.audit.service_token_revoked":  "Service token %s (%s) revoked via web API (from %s)",
+
+	// report
+	"report.not_found":                   "Report not found",
+	"report.unknown_period":              "Unknown report period: %s (use daily or weekly)",
+	"report.unknown_format":              "Unknown report format: %s (use %s)",
+	"report.title.daily":                 "Daily reset report %s",
+	"report.title.weekly":                "Weekly reset report %s",
+	"report.range":                       "Period: %s to %s (%s)",
+	"report.generated_at":                "Generated at: %s",
+	"report.empty":                       "No resets in this period",
+	"report.section.summary":             "Summary",
+	"report.section.tokens":              "Per token",
+	"report.section.failures":            "Failures",
+	"report.total":                       "Total",
+	"report.column.token":                "Token",
+	"report.column.attempts":             "Attempts",
+	"report.column.succeeded":            "Succeeded",
+	"report.column.skipped":              "Skipped",
+	"report.column.failed":               "Failed",
+	"report.column.pending":              "Pending",
+	"report.column.credits_restored":     "Credits restored",
+	"report.column.reset_times_used":     "resetTimes used",
+	"report.column.skip_reasons":         "Skip reasons",
+	"report.list_separator":              ", ",
+	"report.failure":                     "%s %s (%s): %s",
+	"report.reason.paygo":                "PAYGO subscription",
+	"report.reason.reset_times":          "Not enough resetTimes",
+	"report.reason.credit_threshold":     "Credits above threshold",
+	"report.reason.reset_interval":       "Less than 5 hours since the last reset",
+	"report.reason.already_reset_today":  "Already ran today",
+	"report.reason.subscription_expired": "Subscription expired",
+	"report.reason.other":                "Other",
+	"report.notify_title.daily":          "88code daily reset report",
+	"report.notify_title.weekly":         "88code weekly reset report",
+	"report.log.generated":               "Generated report %s (%s succeeded, %s skipped, %s failed, %s pending)",
+	"report.log.failed":                  "Failed to generate %s: %s",
+	"report.period.daily":                "daily report",
+	"report.period.weekly":               "weekly report",
+}
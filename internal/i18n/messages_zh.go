@@ -0,0 +1,306 @@
+package i18n
+
+// zhCN 简体中文消息目录
+var zhCN = map[string]string{
+	// common
+	"text": "%s",
+
+	// api
+	"api.subscription_not_found":         "未找到订阅 ID=%s",
+	"api.marshal_request_failed":         "序列化请求体失败: %s",
+	"api.create_request_failed":          "创建请求失败: %s",
+	"api.request_failed":                 "请求失败: %s",
+	"api.read_response_failed":           "读取响应失败: %s",
+	"api.upstream_error":                 "API错误 [%s]: %s",
+	"api.http_error":                     "请求失败，状态码: %s, 响应: %s",
+	"api.error":                          "API错误 [%s]: %s (状态码: %s)",
+	"api.parse_usage_failed":             "解析用量响应失败: %s",
+	"api.parse_subscriptions_failed":     "解析订阅列表失败: %s",
+	"api.subscriptions_rejected":         "获取订阅列表失败: %s (错误码: %s)",
+	"api.target_subscription_not_found":  "未找到符合条件的目标订阅（目标计划: %s）",
+	"api.paygo_protected":                "拒绝重置：PAYGO 订阅不允许重置",
+	"api.paygo_refused":                  "🚨 %s (订阅 ID=%s, 名称=%s, 类型=%s)",
+	"api.parse_reset_failed":             "解析重置响应失败: %s",
+	"api.reset_limited":                  "重置失败: %s (今日已重置或时间间隔不足5小时)",
+	"api.reset_rejected":                 "重置失败: %s (错误码: %s)",
+	"api.connection_test_failed":         "连接测试失败: %s",
+	"api.account_info_failed":            "获取账号信息失败: %s",
+	"api.no_subscriptions":               "未找到任何订阅信息",
+	"api.invalid_proxy":                  "代理地址无效: %s",
+	"api.unsupported_proxy_scheme":       "不支持的代理协议: %s（支持 http、https、socks5）",
+	"api.client_cert_incomplete":         "客户端证书和私钥必须同时设置",
+	"api.read_ca_failed":                 "读取 CA 证书失败: %s",
+	"api.invalid_ca_pem":                 "CA 证书文件中没有有效的 PEM 证书: %s",
+	"api.load_client_cert_failed":        "加载客户端证书失败: %s",
+	"api.log.usage_call":                 "调用 88code API: 获取用量信息",
+	"api.log.usage_failed":               "88code API 调用失败: 获取用量信息 - %s",
+	"api.log.usage_parse_failed":         "88code API 响应解析失败: 用量信息 - %s",
+	"api.log.usage_succeeded":            "88code API 调用成功: 获取用量信息 (积分=%s/%s)",
+	"api.log.subscriptions_call":         "调用 88code API: 获取订阅列表",
+	"api.log.subscriptions_failed":       "88code API 调用失败: 获取订阅列表 - %s",
+	"api.log.subscriptions_parse_failed": "88code API 响应解析失败: %s",
+	"api.log.subscriptions_rejected":     "88code API 返回错误: %s (错误码: %s)",
+	"api.log.subscriptions_succeeded":    "88code API 调用成功: 获取到 %s 个订阅",
+	"api.log.paygo_refused":              "🚨 拒绝重置：订阅 ID=%s (名称=%s, 类型=%s) 为 PAYGO 类型，不允许重置",
+	"api.log.reset_call":                 "调用 88code API: 重置订阅额度 (订阅ID=%s)",
+	"api.log.reset_failed":               "88code API 调用失败: 重置订阅ID=%s - %s",
+	"api.log.reset_parse_failed":         "88code API 响应解析失败: 订阅ID=%s - %s",
+	"api.log.reset_limited":              "88code API 重置受限: 订阅ID=%s - %s (今日已重置或时间间隔不足5小时)",
+	"api.log.reset_rejected":             "88code API 重置失败: 订阅ID=%s - %s (错误码: %s)",
+	"api.log.reset_succeeded":            "88code API 调用成功: 订阅ID=%s 重置完成 - %s",
+
+	// reset
+	"reset.type.first":                           "第一次",
+	"reset.type.second":                          "第二次",
+	"reset.type.low_credit":                      "低额度",
+	"reset.rule_mandatory":                       "规则 %s 不允许停用",
+	"reset.rule_unknown":                         "未知的重置规则: %s",
+	"rule.paygo_protected":                       "PAYGO 订阅不允许重置",
+	"rule.reset_times_insufficient":              "resetTimes不足(需要>=%s)",
+	"rule.credit_sufficient":                     "额度充足(%s%% %s %s%%)",
+	"rule.reset_interval":                        "距上次重置不足%s小时(还需%s，最早 %s 可重置)",
+	"rule.already_reset_today":                   "今天已执行过%s重置",
+	"reset.verify_failed":                        "验证订阅 %s 状态失败: %s",
+	"reset.request_unconfirmed":                  "重置请求失败且 %s 内无法确认是否生效（不会重复重置）: %s",
+	"reset.pending":                              "重置请求已发出，但 %s 内未确认生效，请稍后刷新查看（不会重复重置）",
+	"reset.confirmed_by_verification":            "重置请求结果未知，核实确认已生效",
+	"reset.not_applied_no_times":                 "重置未生效且 resetTimes=%s 不足以继续重置",
+	"reset.not_applied":                          "多次重置后仍未生效 (resetTimes=%s, credits=%s)",
+	"reset.rule_description.paygo":               "PAYGO 按量付费订阅永远不重置",
+	"reset.rule_description.reset_times":         "第一次重置需要 resetTimes >= 2，第二次需要 >= 1",
+	"reset.rule_description.credit_threshold":    "额度比例达到调度项阈值时跳过",
+	"reset.rule_description.reset_interval":      "距服务端记录的上次重置不足 5 小时时跳过",
+	"reset.rule_description.already_reset_today": "同一调度项每天只执行一次",
+
+	// config
+	"config.invalid":                 "配置验证失败: %s",
+	"config.first_hour":              "第一次重置小时必须在 0-23 之间",
+	"config.first_minute":            "第一次重置分钟必须在 0-59 之间",
+	"config.first_threshold":         "第一次重置阈值必须在 0-100 之间",
+	"config.first_reset_invalid":     "第一次重置配置无效: %s",
+	"config.second_hour":             "第二次重置小时必须在 0-23 之间",
+	"config.second_minute":           "第二次重置分钟必须在 0-59 之间",
+	"config.second_threshold":        "第二次重置阈值必须在 0-100 之间",
+	"config.second_reset_invalid":    "第二次重置配置无效: %s",
+	"config.web_port":                "Web 端口必须在 1-65535 之间",
+	"config.timezone_required":       "时区不能为空",
+	"config.log_retention":           "系统日志保留条数必须在 0-%s 之间",
+	"config.refresh_interval":        "订阅刷新间隔必须在 1-%s 分钟之间",
+	"config.refresh_jitter":          "订阅刷新随机延迟必须在 0-%s 秒之间",
+	"config.refresh_concurrency":     "订阅刷新并发数必须在 0-%s 之间",
+	"config.auto_disable":            "自动禁用阈值必须在 0-%s 之间",
+	"config.expiry_lead_days":        "到期提醒天数必须在 1-%s 之间",
+	"config.credit_watch_interval":   "低额度轮询间隔必须在 1-%s 分钟之间",
+	"config.credit_watch_threshold":  "低额度提醒阈值必须在 0-100 之间",
+	"config.credit_watch_hysteresis": "低额度解除回升幅度必须在 0-100 之间",
+	"config.verify_timeout":          "重置核实截止时间必须在 1-%s 秒之间",
+	"config.verify_initial_delay":    "重置核实首次等待时间必须在 1-%s 秒之间",
+	"config.verify_max_interval":     "重置核实轮询间隔上限必须在 1-%s 秒之间",
+	"config.usage_interval":          "用量采样间隔必须在 1-%s 分钟之间",
+	"config.usage_retention":         "用量采样保留天数必须在 1-%s 之间",
+	"config.http_cache_ttl":          "订阅列表缓存有效期必须在 0-%s 秒之间（0 表示不缓存）",
+	"config.http_timeout":            "API 请求超时必须在 0-%s 秒之间（0 表示默认 30 秒）",
+	"config.http_client_invalid":     "API 网络配置无效: %s",
+	"config.webhook_url":             "通知 Webhook 地址无效: %s",
+	"config.unknown_locale":          "不支持的语言: %s（可选 zh-CN、en）",
+	"config.report_time":             "报告生成时间无效（小时 0-23，分钟 0-59）",
+	"config.report_weekday":          "周报生成日必须在 0-6 之间（0 为星期日）",
+	"config.report_retention":        "报告保留天数必须在 %s-%s 之间",
+
+	// auth
+	"auth.invalid_token":   "无效的服务令牌",
+	"auth.token_expired":   "服务令牌已过期",
+	"auth.token_revoked":   "服务令牌已吊销",
+	"auth.token_not_found": "服务令牌不存在",
+	"auth.scope_required":  "至少需要一项权限",
+	"auth.unknown_scope":   "未知的权限: %s (可选: %s)",
+	"auth.name_required":   "名称不能为空",
+	"auth.expiry_in_past":  "过期时间必须晚于当前时间",
+
+	// token
+	"token.not_found":                       "Token 不存在: %s",
+	"token.id_exists":                       "Token ID 已存在: %s",
+	"token.disabled":                        "Token 已禁用",
+	"token.get_subscriptions_failed":        "获取订阅失败: %s",
+	"token.no_subscriptions":                "该 API Key 没有关联的订阅",
+	"token.no_eligible_subscription":        "未找到合适的订阅",
+	"token.no_eligible_subscription_hint":   "%s（需要 MONTHLY 类型且非 PAYGO）",
+	"token.reset_failed":                    "执行重置失败: %s",
+	"token.nothing_reset":                   "没有订阅被重置",
+	"token.invalid_upstream":                "Token 代理设置无效: %s",
+	"token.invalid_base_url":                "上游地址无效: %s",
+	"token.invalid_credit_threshold":        "低额度提醒阈值必须在 0-100 之间",
+	"token.unknown_conflict_mode":           "未知的冲突处理方式: %s（可选 skip/overwrite/merge）",
+	"token.refresh_in_progress":             "已有批量刷新正在进行",
+	"token.duplicate":                       "重复的账号: %s (已存在 Token: %s)",
+	"token.duplicate_reason.token_id":       "Token ID 已存在",
+	"token.duplicate_reason.api_key":        "API Key 已存在",
+	"token.duplicate_reason.employee_id":    "员工 ID 已存在",
+	"token.duplicate_reason.employee_email": "员工邮箱已存在",
+	"token.no_duplicates":                   "Token %s 没有重复项",
+	"token.merge_mismatch":                  "Token %s 与 %s 不属于同一账号，拒绝合并",
+	"token.subscription_expired":            "订阅已到期，跳过重置",
+	"token.subscription_ended":              "订阅已于 %s 到期",
+	"token.subscription_expired_alert":      "Token %s 的订阅已于 %s 到期，已停止自动重置",
+	"token.subscription_expiring":           "Token %s 的订阅将在 %s 天后到期 (%s)，请及时续费",
+	"token.credit_recovered":                "Token %s 额度已回升至 %s%%，低额度提醒解除",
+	"token.credit_low":                      "Token %s 额度仅剩 %s%%（阈值 %s%%）",
+	"token.credit_low_blocked":              "Token %s 额度仅剩 %s%%（阈值 %s%%），暂不能立即重置: %s",
+	"token.reset_times_exhausted":           "剩余重置次数不足",
+	"token.reset_times_reserved":            "剩余重置次数需保留给定时的第二次重置",
+	"token.reset_interval_pending":          "距上次重置不足 %s，最早 %s 可重置",
+	"token.reset_skipped":                   "已跳过: %s",
+	"token.reset_succeeded":                 "重置成功 (%s → %s, resetTimes: %s → %s)",
+	"token.health_changed":                  "Token %s 健康状态: %s → %s",
+	"token.health_changed_reason":           "Token %s 健康状态: %s → %s (%s)",
+	"token.auto_disabled":                   "Token %s 连续 %s 次鉴权失败，已自动禁用: %s",
+	"token.log.duplicate_rejected":          "拒绝添加重复 Token %s: %s",
+	"token.log.merged":                      "合并 %s 个重复 Token 到 %s",
+	"token.log.refresh_summary":             "批量刷新订阅完成: 共 %s, 成功 %s, 失败 %s (Key 失效 %s)",
+	"token.log.reset_succeeded":             "Token %s 重置成功: %s",
+	"token.log.reset_incomplete":            "Token %s 重置未完成: %s",
+	"token.log.reset_pending":               "Token %s 重置待核实: %s",
+	"token.log.low_credit_reset":            "Token %s 额度低于 %s%%，立即执行重置",
+	"token.log.low_credit_reset_failed":     "Token %s 低额度立即重置失败: %s",
+	"token.log.reset_failed":                "Token %s 重置失败: %s",
+	"token.api_key_required":                "缺少 API Key",
+	"token.notify_title.expiry":             "88code 订阅到期提醒",
+	"token.notify_title.low_credit":         "88code 低额度提醒",
+
+	// scheduler
+	"scheduler.triggered":        "触发%s重置任务",
+	"scheduler.skipped":          "%s，跳过",
+	"scheduler.run_failed":       "执行%s重置失败: %s",
+	"scheduler.no_subscriptions": "%s重置: 未找到需要处理的订阅",
+	"scheduler.reset_failed":     "%s重置失败: [%s] %s",
+	"scheduler.reset_skipped":    "%s重置跳过: [%s] 跳过: %s",
+	"scheduler.reset_succeeded":  "%s重置成功: [%s] %s",
+	"scheduler.run_started":      "定时%s重置开始: %s 个启用的 Token（跳过已到期 %s 个）",
+	"scheduler.run_finished":     "定时%s重置完成: 成功 %s, 失败/跳过 %s",
+
+	// backup
+	"backup.log.created":  "生成数据备份: %s 个文件",
+	"backup.log.restored": "已从备份恢复数据: %s 个文件 (备份时间: %s)",
+
+	// web
+	"web.not_found":                    "接口不存在",
+	"web.method_not_allowed":           "不支持的请求方法",
+	"web.missing_authorization":        "缺少 Authorization 请求头",
+	"web.invalid_authorization":        "Authorization 请求头格式错误",
+	"web.invalid_token":                "令牌无效",
+	"web.token_expired":                "令牌已过期",
+	"web.token_revoked":                "令牌已吊销",
+	"web.verify_token_failed":          "校验令牌失败: %s",
+	"web.admin_token_required":         "需要管理员令牌",
+	"web.missing_scope":                "缺少权限: %s",
+	"web.invalid_json":                 "JSON 格式错误: %s",
+	"web.request_too_large":            "请求体过大（上限 %s 字节）",
+	"web.rate_limited":                 "请求过于频繁",
+	"web.rate_limited_token":           "该令牌请求过于频繁",
+	"web.action_cooling_down":          "操作 %s 冷却中",
+	"web.token_not_found":              "Token 不存在",
+	"web.duplicate_token":              "重复的 Token: %s",
+	"web.token_id_required":            "缺少 Token ID",
+	"web.unknown_operation":            "未知的操作",
+	"web.operation_required":           "缺少操作类型",
+	"web.api_key_required":             "缺少 API Key",
+	"web.api_keys_required":            "缺少 API Key 列表",
+	"web.add_token_failed":             "添加 Token 失败: %s",
+	"web.token_added":                  "Token 添加成功",
+	"web.batch_duplicate":              "与本批次中的 %s 重复",
+	"web.batch_added":                  "批量添加完成: 成功 %s, 重复 %s, 失败 %s",
+	"web.token_deleted":                "Token 已删除",
+	"web.token_toggled":                "Token 状态已切换",
+	"web.token_settings_updated":       "Token 设置已更新",
+	"web.refresh_failed":               "刷新订阅失败: %s",
+	"web.subscription_refreshed":       "订阅已刷新",
+	"web.refresh_all_failed":           "批量刷新订阅失败: %s",
+	"web.refresh_all_completed":        "批量刷新完成: 成功 %s, 失败 %s",
+	"web.reset_failed":                 "重置失败: %s",
+	"web.reset_completed":              "%s重置完成",
+	"web.invalid_reset_type":           "reset_type 无效，必须为 'first' 或 'second'",
+	"web.no_enabled_tokens":            "没有启用的 Token",
+	"web.subscription_expired_skipped": "订阅已于 %s 到期，跳过重置",
+	"web.keep_id_required":             "缺少 keep_id",
+	"web.merge_failed":                 "合并失败: %s",
+	"web.duplicates_merged":            "重复 Token 已合并",
+	"web.invalid_page_size":            "page_size 无效",
+	"web.invalid_page":                 "page 无效",
+	"web.invalid_since":                "since 无效: %s",
+	"web.invalid_until":                "until 无效: %s",
+	"web.invalid_log_format":           "format 无效，必须为 'csv' 或 'ndjson'",
+	"web.load_logs_failed":             "加载系统日志失败: %s",
+	"web.clear_logs_failed":            "清空系统日志失败: %s",
+	"web.delete_logs_failed":           "删除系统日志失败: %s",
+	"web.logs_cleared":                 "系统日志已清空",
+	"web.logs_deleted":                 "已删除 %s 条系统日志",
+	"web.invalid_time":                 "时间格式无效 %s: 请使用 RFC3339 或 YYYY-MM-DD",
+	"web.invalid_usage_range":          "from 必须早于 to",
+	"web.invalid_usage_points":         "points 必须在 2 到 %s 之间",
+	"web.load_usage_failed":            "加载用量数据失败: %s",
+	"web.config_update_failed":         "配置更新失败: %s",
+	"web.config_updated":               "配置已更新",
+	"web.archive_required":             "缺少归档内容",
+	"web.export_failed":                "导出失败: %s",
+	"web.import_failed":                "导入失败: %s",
+	"web.import_completed":             "导入完成: 新增 %s, 覆盖 %s, 合并 %s, 跳过 %s, 失败 %s",
+	"web.backup_failed":                "备份失败: %s",
+	"web.restore_failed":               "恢复失败: %s",
+	"web.restored":                     "已恢复 %s 个文件",
+	"web.audit_log_failed":             "写入审计日志失败: %s",
+	"web.service_tokens_disabled":      "未启用服务令牌",
+	"web.invalid_expires_in_days":      "expires_in_days 必须为正数",
+	"web.create_service_token_failed":  "创建服务令牌失败: %s",
+	"web.service_token_created":        "服务令牌已创建，密钥只显示这一次",
+	"web.service_token_not_found":      "服务令牌不存在",
+	"web.revoke_service_token_failed":  "吊销服务令牌失败: %s",
+	"web.service_token_revoked":        "服务令牌已吊销",
+	"web.reports_disabled":             "未启用重置报告",
+	"web.invalid_report_period":        "period 无效，必须为 'daily' 或 'weekly'",
+	"web.invalid_report_format":        "format 无效，必须为 'json'、'markdown' 或 'html'",
+	"web.report_not_found":             "报告不存在",
+	"web.load_reports_failed":          "加载报告失败: %s",
+	"web.generate_report_failed":       "生成报告失败: %s",
+	"web.report_generated":             "已生成报告 %s",
+	"web.audit.reveal_api_key":         "通过 Web API 查看完整 API Key: %s (来源 %s)",
+	"web.audit.service_token_created":  "通过 Web API 创建服务令牌: %s (%s) 权限 %s (来源 %s)",
+	"web.audit.service_token_revoked":  "通过 Web API 吊销服务令牌: %s (%s) (来源 %s)",
+
+	// report
+	"report.not_found":                   "报告不存在",
+	"report.unknown_period":              "未知的报告周期: %s（可选 daily、weekly）",
+	"report.unknown_format":              "未知的报告格式: %s（可选 %s）",
+	"report.title.daily":                 "重置日报 %s",
+	"report.title.weekly":                "重置周报 %s",
+	"report.range":                       "统计区间：%s 至 %s（%s）",
+	"report.generated_at":                "生成时间：%s",
+	"report.empty":                       "本周期内没有重置记录",
+	"report.section.summary":             "汇总",
+	"report.section.tokens":              "各 Token 统计",
+	"report.section.failures":            "失败记录",
+	"report.total":                       "合计",
+	"report.column.token":                "Token",
+	"report.column.attempts":             "尝试",
+	"report.column.succeeded":            "成功",
+	"report.column.skipped":              "跳过",
+	"report.column.failed":               "失败",
+	"report.column.pending":              "待核实",
+	"report.column.credits_restored":     "恢复积分",
+	"report.column.reset_times_used":     "消耗 resetTimes",
+	"report.column.skip_reasons":         "跳过原因",
+	"report.list_separator":              "，",
+	"report.failure":                     "%s %s（%s）：%s",
+	"report.reason.paygo":                "PAYGO 订阅",
+	"report.reason.reset_times":          "resetTimes 不足",
+	"report.reason.credit_threshold":     "额度充足",
+	"report.reason.reset_interval":       "距上次重置不足 5 小时",
+	"report.reason.already_reset_today":  "今日已执行",
+	"report.reason.subscription_expired": "订阅已到期",
+	"report.reason.other":                "其他",
+	"report.notify_title.daily":          "88code 重置日报",
+	"report.notify_title.weekly":         "88code 重置周报",
+	"report.log.generated":               "已生成报告 %s（成功 %s，跳过 %s，失败 %s，待核实 %s）",
+	"report.log.failed":                  "生成%s失败: %s",
+	"report.period.daily":                "日报",
+	"report.period.weekly":               "周报",
+}
@@ -1,6 +1,11 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+)
 
 // Config 应用配置
 type Config struct {
@@ -66,6 +71,15 @@ type Subscription struct {
 	SubscriptionPlan   SubscriptionPlan `json:"subscriptionPlan"`
 }
 
+// IsPAYGO 判断是否为按量付费（PAYGO / PAY_PER_USE）订阅，此类订阅永远不应重置
+func (s Subscription) IsPAYGO() bool {
+	if strings.EqualFold(s.SubscriptionName, "PAYGO") || strings.EqualFold(s.SubscriptionPlan.SubscriptionName, "PAYGO") {
+		return true
+	}
+	planType := strings.ToUpper(strings.TrimSpace(s.SubscriptionPlan.PlanType))
+	return planType == "PAYGO" || planType == "PAY_PER_USE"
+}
+
 // UsageResponse 用量响应
 type UsageResponse struct {
 	ID                     int            `json:"id"`
@@ -142,46 +156,333 @@ type LockFile struct {
 
 // ResetConfig 重置配置
 type ResetConfig struct {
-	Enabled          bool    `json:"enabled"`
-	Hour             int     `json:"hour"`
-	Minute           int     `json:"minute"`
-	ThresholdPercent float64 `json:"threshold_percent"`
+	Enabled          bool     `json:"enabled"`
+	Hour             int      `json:"hour"`
+	Minute           int      `json:"minute"`
+	ThresholdPercent float64  `json:"threshold_percent"`
+	DisabledRules    []string `json:"disabled_rules,omitempty"` // 该调度项停用的重置规则（PAYGO 保护不可停用）
 }
 
 // DynamicConfig 动态配置（可热重载）
 type DynamicConfig struct {
-	FirstReset  ResetConfig `json:"first_reset"`
-	SecondReset ResetConfig `json:"second_reset"`
-	Timezone    string      `json:"timezone"`
-	WebPort     int         `json:"web_port"`
+	FirstReset         ResetConfig `json:"first_reset"`
+	SecondReset        ResetConfig `json:"second_reset"`
+	Timezone           string      `json:"timezone"`
+	WebPort            int         `json:"web_port"`
+	SystemLogRetention int         `json:"system_log_retention"` // 系统日志保留条数，0 表示使用默认值
+
+	SubscriptionRefresh     SubscriptionRefreshConfig `json:"subscription_refresh"`
+	AutoDisableAuthFailures int                       `json:"auto_disable_auth_failures"` // 连续鉴权失败多少次后自动禁用 Token，0 表示不自动禁用
+	ExpiryAlert             ExpiryAlertConfig         `json:"expiry_alert"`
+	Notifications           NotificationConfig        `json:"notifications"`
+	CreditWatch             CreditWatchConfig         `json:"credit_watch"`
+	ResetVerification       ResetVerificationConfig   `json:"reset_verification"`
+	HTTPClient              HTTPClientConfig          `json:"http_client"`
+	UsageSampling           UsageSamplingConfig       `json:"usage_sampling"`
+	Reports                 ReportConfig              `json:"reports"`
+}
+
+// ReportConfig 重置日报/周报配置
+type ReportConfig struct {
+	Daily         bool `json:"daily"`  // 每天生成日报（统计生成前 24 小时）
+	Weekly        bool `json:"weekly"` // 每周生成周报（统计生成前 7 天）
+	Hour          int  `json:"hour"`   // 生成时间（按 timezone）
+	Minute        int  `json:"minute"`
+	Weekday       int  `json:"weekday"`        // 周报生成日，0 为星期日
+	Notify        bool `json:"notify"`         // 生成后发送到通知渠道
+	RetentionDays int  `json:"retention_days"` // 报告和重置事件的保留天数
+}
+
+// UsageSamplingConfig 用量采样配置
+type UsageSamplingConfig struct {
+	Enabled         bool `json:"enabled"`
+	IntervalMinutes int  `json:"interval_minutes"` // 采样间隔（分钟）
+	RetentionDays   int  `json:"retention_days"`   // 采样数据保留天数
+}
+
+// UsagePoint 一次用量采样
+type UsagePoint struct {
+	Time        time.Time `json:"time"`
+	Credits     float64   `json:"credits"`
+	CreditLimit float64   `json:"credit_limit"`
+	ResetTimes  int       `json:"reset_times"`
+	Event       string    `json:"event,omitempty"` // 非空表示事件点，例如 "reset"
+}
+
+// DailyUsage 按天汇总的用量
+type DailyUsage struct {
+	Date     string  `json:"date"`     // YYYY-MM-DD
+	Consumed float64 `json:"consumed"` // 当天消耗的积分（相邻采样点之间积分下降的总和，重置带来的上升不计入）
+	Resets   int     `json:"resets"`
+	Samples  int     `json:"samples"`
+}
+
+// 重置事件的结果
+const (
+	ResetOutcomeSuccess = "success"
+	ResetOutcomeSkipped = "skipped"
+	ResetOutcomeFailed  = "failed"
+	ResetOutcomePending = "pending" // 重置请求已发出但尚未确认生效
+)
+
+// ResetEvent 一次重置尝试的结果，供日报/周报汇总
+type ResetEvent struct {
+	Time          time.Time  `json:"time"`
+	TokenID       string     `json:"token_id"`
+	TokenName     string     `json:"token_name"`
+	ResetType     string     `json:"reset_type"`
+	RunID         string     `json:"run_id,omitempty"`
+	Outcome       string     `json:"outcome"`               // success / skipped / failed / pending
+	SkipReason    string     `json:"skip_reason,omitempty"` // 跳过原因：起决定作用的规则名，或 subscription_expired
+	Message       string     `json:"message,omitempty"`     // 默认语言的结果说明
+	Code          string     `json:"code,omitempty"`        // 结果说明的消息码
+	Args          []i18n.Arg `json:"args,omitempty"`        // 结果说明的参数
+	BeforeCredits float64    `json:"before_credits"`
+	AfterCredits  float64    `json:"after_credits"`
+	BeforeResets  int        `json:"before_resets"`
+	AfterResets   int        `json:"after_resets"`
+}
+
+// SetMessage 设置带消息码的结果说明，Message 保存默认语言的渲染结果
+func (e *ResetEvent) SetMessage(msg i18n.Message) {
+	e.Message = msg.String()
+	e.Code = msg.Code
+	e.Args = msg.Args
+}
+
+// Localize 按语言渲染结果说明，没有消息码时返回原文
+func (e ResetEvent) Localize(locale i18n.Locale) string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return i18n.Message{Code: e.Code, Args: e.Args}.Localize(locale)
+}
+
+// 报告周期
+const (
+	ReportDaily  = "daily"
+	ReportWeekly = "weekly"
+)
+
+// ResetReport 一个周期内的重置汇总报告
+type ResetReport struct {
+	Name        string          `json:"name"`   // 例如 daily-2025-01-02，日期为生成日期
+	Period      string          `json:"period"` // daily / weekly
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	GeneratedAt time.Time       `json:"generated_at"`
+	Totals      ReportSummary   `json:"totals"`
+	Tokens      []TokenReport   `json:"tokens"`
+	Failures    []ReportFailure `json:"failures,omitempty"`
+}
+
+// ReportSummary 重置结果统计
+type ReportSummary struct {
+	Attempts        int            `json:"attempts"`
+	Succeeded       int            `json:"succeeded"`
+	Skipped         int            `json:"skipped"`
+	Failed          int            `json:"failed"`
+	Pending         int            `json:"pending"`                // 已发出但尚未确认生效的重置，不计入成功或失败
+	SkipReasons     map[string]int `json:"skip_reasons,omitempty"` // 按跳过原因统计
+	CreditsRestored float64        `json:"credits_restored"`       // 重置恢复的积分（重置后减重置前）
+	ResetTimesUsed  int            `json:"reset_times_used"`       // 消耗的 resetTimes 次数
+}
+
+// TokenReport 单个 Token 在报告周期内的统计
+type TokenReport struct {
+	TokenID   string `json:"token_id"`
+	TokenName string `json:"token_name"`
+	ReportSummary
+}
+
+// ReportFailure 报告周期内的一次失败
+type ReportFailure struct {
+	Time      time.Time  `json:"time"`
+	TokenID   string     `json:"token_id"`
+	TokenName string     `json:"token_name"`
+	ResetType string     `json:"reset_type"`
+	Message   string     `json:"message"`
+	Code      string     `json:"code,omitempty"`
+	Args      []i18n.Arg `json:"args,omitempty"`
+}
+
+// Localize 按语言渲染失败原因，没有消息码时返回原文
+func (f ReportFailure) Localize(locale i18n.Locale) string {
+	if f.Code == "" {
+		return f.Message
+	}
+	return i18n.Message{Code: f.Code, Args: f.Args}.Localize(locale)
+}
+
+// ReportInfo 已生成报告的概要
+type ReportInfo struct {
+	Name        string        `json:"name"`
+	Period      string        `json:"period"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Totals      ReportSummary `json:"totals"`
+}
+
+// HTTPClientConfig 访问 88code API 的网络配置
+type HTTPClientConfig struct {
+	ProxyURL        string            `json:"proxy_url"`         // 代理地址（http://、https://、socks5://），留空时使用 HTTP_PROXY/HTTPS_PROXY 环境变量
+	CAFile          string            `json:"ca_file"`           // 额外信任的 CA 证书（PEM），与系统证书一起使用
+	ClientCertFile  string            `json:"client_cert_file"`  // 客户端证书（PEM），需与私钥同时设置
+	ClientKeyFile   string            `json:"client_key_file"`   // 客户端私钥（PEM）
+	TimeoutSeconds  int               `json:"timeout_seconds"`   // 单次请求超时（秒）
+	UserAgent       string            `json:"user_agent"`        // 自定义 User-Agent，留空使用默认值
+	Headers         map[string]string `json:"headers,omitempty"` // 每个请求附加的请求头
+	CacheTTLSeconds int               `json:"cache_ttl_seconds"` // 订阅列表缓存有效期（秒），0 表示不缓存，只合并并发请求
+}
+
+// WebServerConfig Web 管理服务的监听与安全设置，启动时从命令行参数和环境变量读取
+type WebServerConfig struct {
+	BindAddress           string   `json:"bind_address"`            // 监听地址，留空时监听全部网卡，如 127.0.0.1 只允许本机访问
+	Port                  int      `json:"port"`                    // 监听端口
+	AllowedOrigins        []string `json:"allowed_origins"`         // 允许跨域访问的来源，为空时只允许同源访问，"*" 允许任意来源
+	TLSCertFile           string   `json:"tls_cert_file"`           // 证书文件（PEM），与私钥同时设置时启用 HTTPS，文件更新后自动重新加载
+	TLSKeyFile            string   `json:"tls_key_file"`            // 私钥文件（PEM）
+	HTTPRedirectPort      int      `json:"http_redirect_port"`      // 启用 HTTPS 时额外监听的 HTTP 端口，请求重定向到 HTTPS，0 表示不监听
+	HSTSMaxAge            int      `json:"hsts_max_age"`            // 启用 HTTPS 时 Strict-Transport-Security 的 max-age（秒），0 表示不发送
+	ContentSecurityPolicy string   `json:"content_security_policy"` // 内置界面的 Content-Security-Policy，留空使用默认策略
+	BasePath              string   `json:"base_path"`               // URL 前缀（如 /88reset），反向代理在子路径下转发且不去掉前缀时设置
+	TrustedProxies        []string `json:"trusted_proxies"`         // 可信反向代理的 IP 或 CIDR，只有来自这些地址的 X-Forwarded-For/Proto 才会被采用
+	RateLimitPerIP        int      `json:"rate_limit_per_ip"`       // 每个客户端 IP 每分钟最多请求管理 API 的次数，0 表示不限制
+	RateLimitPerToken     int      `json:"rate_limit_per_token"`    // 每个令牌（管理员令牌或服务令牌）每分钟最多请求次数，0 表示不限制
+	MaxBodyBytes          int      `json:"max_body_bytes"`          // 请求体大小上限（字节），0 表示不限制
+	MaxUploadBytes        int      `json:"max_upload_bytes"`        // 导入 Token 和恢复备份的请求体大小上限（字节），0 表示不限制
+	ActionCooldown        int      `json:"action_cooldown"`         // 手动重置全部和批量刷新两次执行的最小间隔（秒），0 表示不限制
+}
+
+// TLSEnabled 是否启用 HTTPS
+func (c WebServerConfig) TLSEnabled() bool {
+	return c.TLSCertFile != "" || c.TLSKeyFile != ""
+}
+
+// ResetVerificationConfig 重置后核实结果的轮询配置
+type ResetVerificationConfig struct {
+	InitialDelaySeconds int `json:"initial_delay_seconds"` // 重置后首次查询前的等待时间（秒）
+	MaxIntervalSeconds  int `json:"max_interval_seconds"`  // 指数退避的轮询间隔上限（秒）
+	TimeoutSeconds      int `json:"timeout_seconds"`       // 核实截止时间（秒），超时未确认则不再重复重置
+}
+
+// CreditWatchConfig 低额度监控配置
+type CreditWatchConfig struct {
+	Enabled           bool    `json:"enabled"`
+	IntervalMinutes   int     `json:"interval_minutes"`   // 轮询间隔（分钟）
+	ThresholdPercent  float64 `json:"threshold_percent"`  // 全局提醒阈值，额度百分比低于该值时提醒
+	HysteresisPercent float64 `json:"hysteresis_percent"` // 回升到 阈值+该值 以上才解除提醒，避免反复提醒
+	AutoReset         bool    `json:"auto_reset"`         // 提醒时满足条件则立即重置
+}
+
+// ExpiryAlertConfig 订阅到期提醒配置
+type ExpiryAlertConfig struct {
+	Enabled  bool  `json:"enabled"`
+	LeadDays []int `json:"lead_days"` // 提前多少天提醒，例如 [7, 3, 1]
+}
+
+// NotificationConfig 通知渠道配置
+type NotificationConfig struct {
+	WebhookURL string `json:"webhook_url"` // 以 JSON POST 推送通知，留空表示不启用
+}
+
+// SubscriptionRefreshConfig 后台订阅刷新配置
+type SubscriptionRefreshConfig struct {
+	Enabled         bool `json:"enabled"`
+	IntervalMinutes int  `json:"interval_minutes"` // 刷新间隔（分钟）
+	JitterSeconds   int  `json:"jitter_seconds"`   // 每轮随机延迟上限（秒），避免固定时刻集中请求
+	Concurrency     int  `json:"concurrency"`      // 同时刷新的 Token 数量上限
 }
 
 // TokenSubscriptionInfo Token的订阅详情
 type TokenSubscriptionInfo struct {
-	ID                 int     `json:"id"`
-	SubscriptionName   string  `json:"subscription_name"`
-	PlanType           string  `json:"plan_type"`
-	CurrentCredits     float64 `json:"current_credits"`
-	CreditLimit        float64 `json:"credit_limit"`
-	CreditPercent      float64 `json:"credit_percent"`
-	ResetTimes         int     `json:"reset_times"`
-	Status             string  `json:"status"`
-	RemainingDays      int     `json:"remaining_days"`
-	EmployeeName       string  `json:"employee_name"`
-	EmployeeEmail      string  `json:"employee_email"`
-	StartDate          string  `json:"start_date"`
-	EndDate            string  `json:"end_date"`
-	LastCreditReset    *string `json:"last_credit_reset"`
+	ID               int     `json:"id"`
+	SubscriptionName string  `json:"subscription_name"`
+	PlanType         string  `json:"plan_type"`
+	CurrentCredits   float64 `json:"current_credits"`
+	CreditLimit      float64 `json:"credit_limit"`
+	CreditPercent    float64 `json:"credit_percent"`
+	ResetTimes       int     `json:"reset_times"`
+	Status           string  `json:"status"`
+	RemainingDays    int     `json:"remaining_days"`
+	EmployeeID       int     `json:"employee_id,omitempty"`
+	EmployeeName     string  `json:"employee_name"`
+	EmployeeEmail    string  `json:"employee_email"`
+	StartDate        string  `json:"start_date"`
+	EndDate          string  `json:"end_date"`
+	LastCreditReset  *string `json:"last_credit_reset"`
 }
 
 // TokenResetRecord Token的重置记录
 type TokenResetRecord struct {
-	ResetAt       time.Time `json:"reset_at"`
-	ResetType     string    `json:"reset_type"` // "first" or "second"
-	Success       bool      `json:"success"`
-	BeforeCredits float64   `json:"before_credits"`
-	AfterCredits  float64   `json:"after_credits"`
-	Message       string    `json:"message"`
+	ResetAt        time.Time      `json:"reset_at"`
+	ResetType      string         `json:"reset_type"` // "first", "second" or "low_credit"
+	Success        bool           `json:"success"`
+	BeforeCredits  float64        `json:"before_credits"`
+	AfterCredits   float64        `json:"after_credits"`
+	Message        string         `json:"message"`                    // 默认语言的结果说明
+	Code           string         `json:"code,omitempty"`             // 结果说明的消息码
+	Args           []i18n.Arg     `json:"args,omitempty"`             // 结果说明的参数，用于按其他语言重新渲染
+	RunID          string         `json:"run_id,omitempty"`           // 所属执行批次 ID
+	NextEligibleAt *time.Time     `json:"next_eligible_at,omitempty"` // 因 5 小时规则跳过时，最早可重置的时间
+	Decisions      []RuleDecision `json:"decisions,omitempty"`        // 本次重置各规则的判定结果
+	Verification   string         `json:"verification,omitempty"`     // 重置结果核实状态：applied / pending / not_applied
+
+	// 重置前的订阅状态，待核实的记录在下次刷新订阅时据此重新核实
+	BeforeResets          int     `json:"before_resets,omitempty"`
+	BeforeLastCreditReset *string `json:"before_last_credit_reset,omitempty"`
+}
+
+// Pending 重置请求已发出但尚未确认是否生效，既不算成功也不算失败
+func (r *TokenResetRecord) Pending() bool {
+	return !r.Success && r.Verification == "pending"
+}
+
+// SetMessage 设置带消息码的结果说明，Message 保存默认语言的渲染结果
+func (r *TokenResetRecord) SetMessage(msg i18n.Message) {
+	r.Message = msg.String()
+	r.Code = msg.Code
+	r.Args = msg.Args
+}
+
+// CodedMessage 带消息码的结果说明，旧记录没有消息码时原样保存文本
+func (r TokenResetRecord) CodedMessage() i18n.Message {
+	if r.Code == "" {
+		return i18n.M(i18n.TextCode, r.Message)
+	}
+	return i18n.Message{Code: r.Code, Args: r.Args}
+}
+
+// Localize 按语言渲染结果说明
+func (r TokenResetRecord) Localize(locale i18n.Locale) string {
+	return r.CodedMessage().Localize(locale)
+}
+
+// RuleDecision 单条重置规则的判定结果
+type RuleDecision struct {
+	Rule           string     `json:"rule"`
+	Verdict        string     `json:"verdict"` // allow / skip / deny
+	Code           string     `json:"code,omitempty"`
+	Message        string     `json:"message,omitempty"` // 默认语言的说明文字
+	Args           []i18n.Arg `json:"args,omitempty"`    // 说明文字的参数，用于按其他语言重新渲染
+	NextEligibleAt *time.Time `json:"next_eligible_at,omitempty"`
+}
+
+// RuleMessagePrefix 规则判定说明文字的消息码前缀，消息码为 rule.<Code>
+const RuleMessagePrefix = "rule."
+
+// CodedMessage 带消息码的判定说明
+func (d RuleDecision) CodedMessage() i18n.Message {
+	return i18n.Message{Code: RuleMessagePrefix + d.Code, Args: d.Args}
+}
+
+// Localize 按语言渲染判定说明，旧数据缺少消息码或参数时返回原文
+func (d RuleDecision) Localize(locale i18n.Locale) string {
+	if d.Code == "" || len(d.Args) != i18n.ArgCount(RuleMessagePrefix+d.Code) {
+		return d.Message
+	}
+	return d.CodedMessage().Localize(locale)
 }
 
 // Token API Token 信息
@@ -194,6 +495,49 @@ type Token struct {
 	Subscription          *TokenSubscriptionInfo `json:"subscription,omitempty"`
 	SubscriptionUpdatedAt *time.Time             `json:"subscription_updated_at,omitempty"`
 	LastReset             *TokenResetRecord      `json:"last_reset,omitempty"`
+	KeyInvalid            bool                   `json:"key_invalid,omitempty"`        // API Key 鉴权失败（无效或已吊销）
+	LastRefreshError      string                 `json:"last_refresh_error,omitempty"` // 最近一次刷新订阅失败的原因
+	Health                *TokenHealth           `json:"health,omitempty"`
+	ExpiryAlert           *ExpiryAlertState      `json:"expiry_alert,omitempty"`
+	LowCreditThreshold    *float64               `json:"low_credit_threshold,omitempty"` // 单独的低额度提醒阈值，为空时使用全局配置
+	CreditAlert           *CreditAlertState      `json:"credit_alert,omitempty"`
+	BaseURL               string                 `json:"base_url,omitempty"`  // 单独的上游地址（兼容 88code 的自建后台），为空时使用全局地址
+	ProxyURL              string                 `json:"proxy_url,omitempty"` // 单独的代理地址，为空时使用全局代理设置
+}
+
+// CreditAlertState 低额度提醒状态
+type CreditAlertState struct {
+	Active    bool      `json:"active"`
+	Percent   float64   `json:"percent"`   // 触发/解除时的额度百分比
+	Threshold float64   `json:"threshold"` // 触发时使用的阈值
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// ExpiryAlertState 已发送的到期提醒，订阅到期日变化（续费）后重新计算
+type ExpiryAlertState struct {
+	EndDate      string `json:"end_date"`
+	NotifiedDays []int  `json:"notified_days"` // 已提醒过的提前天数，0 表示已到期提醒
+}
+
+// Token 健康状态
+const (
+	HealthHealthy                = "healthy"                  // 最近一次操作成功
+	HealthDegraded               = "degraded"                 // 出现非鉴权类错误（网络、限流等）
+	HealthAuthFailed             = "auth_failed"              // API Key 鉴权失败
+	HealthSubscriptionExpired    = "subscription_expired"     // 订阅已到期
+	HealthNoEligibleSubscription = "no_eligible_subscription" // 没有可重置的订阅
+)
+
+// TokenHealth Token 健康状态，由最近连续的操作结果推导
+type TokenHealth struct {
+	State                   string     `json:"state"`
+	Reason                  string     `json:"reason,omitempty"`
+	ConsecutiveFailures     int        `json:"consecutive_failures"`
+	ConsecutiveAuthFailures int        `json:"consecutive_auth_failures"`
+	LastSuccessAt           *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt           *time.Time `json:"last_failure_at,omitempty"`
+	ChangedAt               time.Time  `json:"changed_at"`
+	AutoDisabledAt          *time.Time `json:"auto_disabled_at,omitempty"` // 因连续鉴权失败被自动禁用的时间
 }
 
 // TokenStorage Token 存储结构
@@ -203,12 +547,49 @@ type TokenStorage struct {
 
 // SystemLog 系统日志
 type SystemLog struct {
-	Timestamp time.Time `json:"timestamp"`
-	Type      string    `json:"type"`    // "info", "success", "warning", "error"
-	Message   string    `json:"message"`
+	Timestamp time.Time  `json:"timestamp"`
+	Type      string     `json:"type"`               // "info", "success", "warning", "error", "audit"
+	Message   string     `json:"message"`            // 默认语言的日志内容
+	Code      string     `json:"code,omitempty"`     // 消息码，旧日志为空
+	Args      []i18n.Arg `json:"args,omitempty"`     // 消息参数，用于按其他语言重新渲染
+	TokenID   string     `json:"token_id,omitempty"` // 关联的 Token ID
+	RunID     string     `json:"run_id,omitempty"`   // 关联的执行批次 ID
+}
+
+// SetMessage 设置带消息码的日志内容，Message 保存默认语言的渲染结果
+func (l *SystemLog) SetMessage(msg i18n.Message) {
+	l.Message = msg.String()
+	l.Code = msg.Code
+	l.Args = msg.Args
+}
+
+// Localize 按语言渲染日志内容，没有消息码时返回原文
+func (l SystemLog) Localize(locale i18n.Locale) string {
+	if l.Code == "" {
+		return l.Message
+	}
+	return i18n.Message{Code: l.Code, Args: l.Args}.Localize(locale)
 }
 
 // SystemLogs 系统日志列表
 type SystemLogs struct {
 	Logs []SystemLog `json:"logs"`
 }
+
+// ServiceToken 供外部自动化使用的服务令牌，只保存哈希
+type ServiceToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`         // 令牌明文的前几位，便于识别
+	Hash       string     `json:"hash,omitempty"` // 令牌明文的 SHA-256，接口响应中不返回
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"` // 为空时永不过期
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// ServiceTokenStorage 服务令牌存储结构
+type ServiceTokenStorage struct {
+	Tokens []ServiceToken `json:"tokens"`
+}
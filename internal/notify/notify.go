@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// Notification 通知内容
+type Notification struct {
+	Event     string    `json:"event"` // 事件类型，例如 subscription_expiring
+	Level     string    `json:"level"` // info, warning, error
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	TokenID   string    `json:"token_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Name() string
+	Notify(n Notification) error
+}
+
+// Dispatcher 将通知分发到所有已配置的渠道，配置可热更新
+type Dispatcher struct {
+	mu       sync.RWMutex
+	channels []Notifier
+}
+
+// NewDispatcher 创建通知分发器
+func NewDispatcher(cfg models.NotificationConfig) *Dispatcher {
+	d := &Dispatcher{}
+	d.Configure(cfg)
+	return d
+}
+
+// Configure 按配置重建通知渠道
+func (d *Dispatcher) Configure(cfg models.NotificationConfig) {
+	channels := make([]Notifier, 0, 1)
+	if cfg.WebhookURL != "" {
+		channels = append(channels, NewWebhook(cfg.WebhookURL))
+	}
+
+	d.mu.Lock()
+	d.channels = channels
+	d.mu.Unlock()
+}
+
+// Notify 向所有渠道发送通知，未配置渠道时直接返回
+func (d *Dispatcher) Notify(n Notification) error {
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+
+	d.mu.RLock()
+	channels := d.channels
+	d.mu.RUnlock()
+
+	var errs []error
+	for _, ch := range channels {
+		if err := ch.Notify(n); err != nil {
+			logger.Warn("发送通知失败 (%s): %v", ch.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", ch.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Webhook 以 JSON POST 推送通知
+type Webhook struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhook 创建 Webhook 通知渠道
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		URL:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name 渠道名称
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+// Notify 发送通知
+func (w *Webhook) Notify(n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("序列化通知失败: %w", err)
+	}
+
+	resp, err := w.client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("请求失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// journalFile 重置事件日志文件名（位于报告目录下）
+const journalFile = "resets.jsonl"
+
+// Journal 追加保存每次重置尝试的结果（reports/resets.jsonl，每行一个事件），供报告汇总
+//
+// 系统日志有条数上限，不适合作为周报的数据来源，因此重置结果单独保存。
+type Journal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewJournal 创建重置事件日志，dir 为报告目录
+func NewJournal(dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建报告目录失败: %w", err)
+	}
+	return &Journal{path: filepath.Join(dir, journalFile)}, nil
+}
+
+// Record 追加一条重置事件
+func (j *Journal) Record(event models.ResetEvent) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("写入重置事件失败: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Query 返回 [from, to) 时间范围内的重置事件（按写入顺序）
+func (j *Journal) Query(from, to time.Time) ([]models.ResetEvent, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	events, err := j.readUnlocked()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.ResetEvent, 0, len(events))
+	for _, e := range events {
+		if e.Time.Before(from) || !e.Time.Before(to) {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// Prune 删除 before 之前的重置事件，返回删除的条数
+func (j *Journal) Prune(before time.Time) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	events, err := j.readUnlocked()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := events[:0]
+	for _, e := range events {
+		if !e.Time.Before(before) {
+			kept = append(kept, e)
+		}
+	}
+	removed := len(events) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	var buf bytes.Buffer
+	for _, e := range kept {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return 0, err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return 0, fmt.Errorf("写入重置事件失败: %w", err)
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return 0, fmt.Errorf("替换重置事件文件失败: %w", err)
+	}
+	return removed, nil
+}
+
+// readUnlocked 读取全部事件，跳过无法解析的行（例如写入中断留下的半行）
+func (j *Journal) readUnlocked() ([]models.ResetEvent, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取重置事件失败: %w", err)
+	}
+	defer f.Close()
+
+	var events []models.ResetEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e models.ResetEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			logger.Warn("跳过无法解析的重置事件: %v", err)
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
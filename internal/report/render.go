@@ -0,0 +1,274 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+)
+
+// 报告格式
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats 全部支持的格式
+var Formats = []string{FormatJSON, FormatMarkdown, FormatHTML}
+
+// formatExt 各格式保存到报告目录时使用的扩展名
+var formatExt = map[string]string{
+	FormatJSON:     ".json",
+	FormatMarkdown: ".md",
+	FormatHTML:     ".html",
+}
+
+// ContentTypes 各格式对应的 Content-Type
+var ContentTypes = map[string]string{
+	FormatJSON:     "application/json",
+	FormatMarkdown: "text/markdown; charset=utf-8",
+	FormatHTML:     "text/html; charset=utf-8",
+}
+
+// Render 按格式渲染报告，文字按 locale 渲染，时间按 loc 时区显示
+func Render(rep *models.ResetReport, format string, locale i18n.Locale, loc *time.Location) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(rep, "", "  ")
+	case FormatMarkdown:
+		return renderMarkdown(rep, locale, loc), nil
+	case FormatHTML:
+		return renderHTML(rep, locale, loc)
+	default:
+		return nil, i18n.Errorf("report.unknown_format", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Title 报告标题，例如“重置日报 daily-2025-01-02”
+func Title(rep *models.ResetReport, locale i18n.Locale) string {
+	return i18n.T(locale, "report.title."+rep.Period, rep.Name)
+}
+
+// ReasonLabel 跳过原因的显示名称，未知原因原样返回
+func ReasonLabel(reason string, locale i18n.Locale) string {
+	code := "report.reason." + reason
+	if i18n.ArgCount(code) < 0 {
+		return reason
+	}
+	return i18n.T(locale, code)
+}
+
+// formatReasons 按次数从多到少列出跳过原因，例如“额度充足 ×2，今日已执行 ×1”
+func formatReasons(reasons map[string]int, locale i18n.Locale) string {
+	keys := make([]string, 0, len(reasons))
+	for k := range reasons {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if reasons[keys[i]] != reasons[keys[j]] {
+			return reasons[keys[i]] > reasons[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s ×%d", ReasonLabel(k, locale), reasons[k])
+	}
+	return strings.Join(parts, i18n.T(locale, "report.list_separator"))
+}
+
+// summaryRow 统计表的一行
+type summaryRow struct {
+	Name    string
+	Summary models.ReportSummary
+	Reasons string
+}
+
+// view 渲染报告所需的文字，Markdown 和 HTML 共用
+type view struct {
+	Lang        string
+	Title       string
+	Range       string
+	GeneratedAt string
+	Empty       string
+	Sections    map[string]string
+	Columns     []string
+	Totals      summaryRow
+	Tokens      []summaryRow
+	Failures    []string // 按语言渲染的失败记录
+}
+
+func newView(rep *models.ResetReport, locale i18n.Locale, loc *time.Location) view {
+	const layout = "2006-01-02 15:04"
+	t := func(code string, args ...interface{}) string { return i18n.T(locale, code, args...) }
+
+	v := view{
+		Lang:        string(locale),
+		Title:       Title(rep, locale),
+		Range:       t("report.range", rep.From.In(loc).Format(layout), rep.To.In(loc).Format(layout), loc.String()),
+		GeneratedAt: t("report.generated_at", rep.GeneratedAt.In(loc).Format(layout)),
+		Empty:       t("report.empty"),
+		Sections: map[string]string{
+			"summary":  t("report.section.summary"),
+			"tokens":   t("report.section.tokens"),
+			"failures": t("report.section.failures"),
+		},
+		Columns: []string{
+			t("report.column.token"),
+			t("report.column.attempts"),
+			t("report.column.succeeded"),
+			t("report.column.skipped"),
+			t("report.column.failed"),
+			t("report.column.pending"),
+			t("report.column.credits_restored"),
+			t("report.column.reset_times_used"),
+			t("report.column.skip_reasons"),
+		},
+		Totals: summaryRow{
+			Name:    t("report.total"),
+			Summary: rep.Totals,
+			Reasons: formatReasons(rep.Totals.SkipReasons, locale),
+		},
+	}
+
+	for _, tr := range rep.Tokens {
+		name := tr.TokenName
+		if name == "" {
+			name = tr.TokenID
+		}
+		v.Tokens = append(v.Tokens, summaryRow{
+			Name:    name,
+			Summary: tr.ReportSummary,
+			Reasons: formatReasons(tr.SkipReasons, locale),
+		})
+	}
+
+	for _, f := range rep.Failures {
+		name := f.TokenName
+		if name == "" {
+			name = f.TokenID
+		}
+		v.Failures = append(v.Failures, t("report.failure",
+			f.Time.In(loc).Format(layout), name, i18n.Label("reset.type."+f.ResetType), f.Localize(locale)))
+	}
+	return v
+}
+
+// Cells 统计行的各列内容
+func (r summaryRow) Cells() []string {
+	s := r.Summary
+	return []string{
+		r.Name,
+		fmt.Sprint(s.Attempts),
+		fmt.Sprint(s.Succeeded),
+		fmt.Sprint(s.Skipped),
+		fmt.Sprint(s.Failed),
+		fmt.Sprint(s.Pending),
+		fmt.Sprintf("%.2f", s.CreditsRestored),
+		fmt.Sprint(s.ResetTimesUsed),
+		r.Reasons,
+	}
+}
+
+// markdownCell 转义表格单元格中的竖线和换行
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = markdownCell(c)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+func renderMarkdown(rep *models.ResetReport, locale i18n.Locale, loc *time.Location) []byte {
+	v := newView(rep, locale, loc)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", v.Title)
+	fmt.Fprintf(&b, "%s\n\n%s\n\n", v.Range, v.GeneratedAt)
+
+	if rep.Totals.Attempts == 0 {
+		fmt.Fprintf(&b, "%s\n", v.Empty)
+		return []byte(b.String())
+	}
+
+	separator := "|" + strings.Repeat(" --- |", len(v.Columns)) + "\n"
+
+	fmt.Fprintf(&b, "## %s\n\n", v.Sections["summary"])
+	b.WriteString(markdownRow(v.Columns))
+	b.WriteString(separator)
+	b.WriteString(markdownRow(v.Totals.Cells()))
+
+	fmt.Fprintf(&b, "\n## %s\n\n", v.Sections["tokens"])
+	b.WriteString(markdownRow(v.Columns))
+	b.WriteString(separator)
+	for _, row := range v.Tokens {
+		b.WriteString(markdownRow(row.Cells()))
+	}
+
+	if len(v.Failures) > 0 {
+		fmt.Fprintf(&b, "\n## %s\n\n", v.Sections["failures"])
+		for _, f := range v.Failures {
+			fmt.Fprintf(&b, "- %s\n", markdownCell(f))
+		}
+	}
+	return []byte(b.String())
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; margin: 2rem; color: #1f2937; }
+table { border-collapse: collapse; margin-bottom: 1.5rem; }
+th, td { border: 1px solid #e5e7eb; padding: .4rem .8rem; text-align: right; }
+th:first-child, td:first-child, th:last-child, td:last-child { text-align: left; }
+th { background: #f3f4f6; }
+tr.total td { font-weight: 600; }
+.meta { color: #6b7280; }
+.failed { color: #b91c1c; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">{{.Range}}<br>{{.GeneratedAt}}</p>
+{{if not .Tokens}}<p>{{.Empty}}</p>{{else}}
+<h2>{{index .Sections "summary"}}</h2>
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+<tr class="total">{{range .Totals.Cells}}<td>{{.}}</td>{{end}}</tr>
+</table>
+<h2>{{index .Sections "tokens"}}</h2>
+<table>
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{range .Tokens}}<tr>{{range .Cells}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{if .Failures}}<h2>{{index .Sections "failures"}}</h2>
+<ul class="failed">
+{{range .Failures}}<li>{{.}}</li>
+{{end}}</ul>{{end}}{{end}}
+</body>
+</html>
+`))
+
+func renderHTML(rep *models.ResetReport, locale i18n.Locale, loc *time.Location) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, newView(rep, locale, loc)); err != nil {
+		return nil, fmt.Errorf("渲染 HTML 报告失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// ReasonOther 没有记录跳过原因的事件归入此类
+const ReasonOther = "other"
+
+// PeriodRange 报告周期对应的时间范围：日报为生成前 24 小时，周报为生成前 7 天
+func PeriodRange(period string, at time.Time) (time.Time, time.Time) {
+	if period == models.ReportWeekly {
+		return at.AddDate(0, 0, -7), at
+	}
+	return at.AddDate(0, 0, -1), at
+}
+
+// Name 报告名称，例如 daily-2025-01-02，日期为生成时间在 loc 时区的日期
+func Name(period string, at time.Time, loc *time.Location) string {
+	return period + "-" + at.In(loc).Format("2006-01-02")
+}
+
+// Build 汇总 [from, to) 内的重置事件，按 Token 统计成功、跳过原因、失败、恢复的积分和消耗的 resetTimes
+func Build(period string, from, to time.Time, events []models.ResetEvent) *models.ResetReport {
+	rep := &models.ResetReport{
+		Period:      period,
+		From:        from,
+		To:          to,
+		GeneratedAt: time.Now(),
+		Tokens:      []models.TokenReport{},
+	}
+
+	byToken := make(map[string]*models.TokenReport)
+	var order []string
+	for _, e := range events {
+		if e.Time.Before(from) || !e.Time.Before(to) {
+			continue
+		}
+
+		tr, ok := byToken[e.TokenID]
+		if !ok {
+			tr = &models.TokenReport{TokenID: e.TokenID}
+			byToken[e.TokenID] = tr
+			order = append(order, e.TokenID)
+		}
+		// 使用周期内最新的名称
+		if e.TokenName != "" {
+			tr.TokenName = e.TokenName
+		}
+
+		addEvent(&tr.ReportSummary, e)
+		addEvent(&rep.Totals, e)
+
+		if e.Outcome == models.ResetOutcomeFailed {
+			rep.Failures = append(rep.Failures, models.ReportFailure{
+				Time:      e.Time,
+				TokenID:   e.TokenID,
+				TokenName: e.TokenName,
+				ResetType: e.ResetType,
+				Message:   e.Message,
+				Code:      e.Code,
+				Args:      e.Args,
+			})
+		}
+	}
+
+	for _, id := range order {
+		rep.Tokens = append(rep.Tokens, *byToken[id])
+	}
+	sort.SliceStable(rep.Tokens, func(i, j int) bool {
+		return rep.Tokens[i].TokenName < rep.Tokens[j].TokenName
+	})
+	return rep
+}
+
+// addEvent 将一条事件计入统计
+func addEvent(s *models.ReportSummary, e models.ResetEvent) {
+	s.Attempts++
+	switch e.Outcome {
+	case models.ResetOutcomeSuccess:
+		s.Succeeded++
+		if restored := e.AfterCredits - e.BeforeCredits; restored > 0 {
+			s.CreditsRestored += restored
+		}
+		if used := e.BeforeResets - e.AfterResets; used > 0 {
+			s.ResetTimesUsed += used
+		}
+	case models.ResetOutcomeSkipped:
+		s.Skipped++
+		reason := e.SkipReason
+		if reason == "" {
+			reason = ReasonOther
+		}
+		if s.SkipReasons == nil {
+			s.SkipReasons = make(map[string]int)
+		}
+		s.SkipReasons[reason]++
+	case models.ResetOutcomePending:
+		s.Pending++
+	default:
+		s.Failed++
+	}
+}
+
+// Info 报告概要
+func Info(rep *models.ResetReport) models.ReportInfo {
+	return models.ReportInfo{
+		Name:        rep.Name,
+		Period:      rep.Period,
+		From:        rep.From,
+		To:          rep.To,
+		GeneratedAt: rep.GeneratedAt,
+		Totals:      rep.Totals,
+	}
+}
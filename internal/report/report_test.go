@@ -0,0 +1,135 @@
+package report
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+)
+
+func TestBuild_AggregatesPerToken(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	events := []models.ResetEvent{
+		{Time: base, TokenID: "a", TokenName: "alpha", ResetType: "first", Outcome: models.ResetOutcomeSuccess,
+			BeforeCredits: 10, AfterCredits: 100, BeforeResets: 2, AfterResets: 1},
+		{Time: base.Add(time.Hour), TokenID: "a", TokenName: "alpha", Outcome: models.ResetOutcomeSkipped, SkipReason: "credit_threshold"},
+		{Time: base.Add(2 * time.Hour), TokenID: "b", TokenName: "beta", Outcome: models.ResetOutcomeSkipped},
+		{Time: base.Add(3 * time.Hour), TokenID: "b", TokenName: "beta", ResetType: "second", Outcome: models.ResetOutcomeFailed, Message: "boom"},
+		{Time: base.Add(4 * time.Hour), TokenID: "b", TokenName: "beta", ResetType: "second", Outcome: models.ResetOutcomePending},
+		// 超出时间范围
+		{Time: base.Add(48 * time.Hour), TokenID: "a", Outcome: models.ResetOutcomeSuccess},
+	}
+
+	rep := Build(models.ReportDaily, base, base.Add(24*time.Hour), events)
+
+	totals := rep.Totals
+	if totals.Attempts != 5 || totals.Succeeded != 1 || totals.Skipped != 2 || totals.Failed != 1 || totals.Pending != 1 {
+		t.Fatalf("totals = %+v", totals)
+	}
+	if totals.CreditsRestored != 90 || totals.ResetTimesUsed != 1 {
+		t.Fatalf("credits restored = %v, resetTimes used = %d", totals.CreditsRestored, totals.ResetTimesUsed)
+	}
+	if totals.SkipReasons["credit_threshold"] != 1 || totals.SkipReasons[ReasonOther] != 1 {
+		t.Fatalf("skip reasons = %v", totals.SkipReasons)
+	}
+
+	if len(rep.Tokens) != 2 || rep.Tokens[0].TokenName != "alpha" || rep.Tokens[1].TokenName != "beta" {
+		t.Fatalf("tokens = %+v", rep.Tokens)
+	}
+	if rep.Tokens[0].Attempts != 2 || rep.Tokens[1].Failed != 1 {
+		t.Fatalf("per-token counts = %+v", rep.Tokens)
+	}
+	if len(rep.Failures) != 1 || rep.Failures[0].TokenID != "b" || rep.Failures[0].Message != "boom" {
+		t.Fatalf("failures = %+v", rep.Failures)
+	}
+}
+
+func TestJournal_QueryPrune(t *testing.T) {
+	j, err := NewJournal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewJournal() error = %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		if err := j.Record(models.ResetEvent{Time: base.Add(time.Duration(i) * time.Hour), TokenID: "t1"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	events, err := j.Query(base.Add(time.Hour), base.Add(3*time.Hour))
+	if err != nil || len(events) != 2 {
+		t.Fatalf("Query() = %+v, %v", events, err)
+	}
+
+	removed, err := j.Prune(base.Add(2 * time.Hour))
+	if err != nil || removed != 2 {
+		t.Fatalf("Prune() = %d, %v", removed, err)
+	}
+	events, _ = j.Query(time.Time{}, base.Add(24*time.Hour))
+	if len(events) != 2 || !events[0].Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("after Prune() = %+v", events)
+	}
+}
+
+func TestRender_Markdown(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	rep := Build(models.ReportDaily, base, base.Add(24*time.Hour), []models.ResetEvent{
+		{Time: base, TokenID: "a", TokenName: "a|b", Outcome: models.ResetOutcomeSkipped, SkipReason: "credit_threshold"},
+		{Time: base, TokenID: "a", TokenName: "a|b", ResetType: "first", Outcome: models.ResetOutcomeFailed, Message: "boom"},
+	})
+	rep.Name = "daily-2025-01-02"
+
+	data, err := Render(rep, FormatMarkdown, i18n.EN, time.UTC)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	md := string(data)
+	for _, want := range []string{"# Daily reset report daily-2025-01-02", "a\\|b", "Credits above threshold ×1", "boom"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if _, err := Render(rep, "pdf", i18n.EN, time.UTC); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
+
+func TestService_GenerateListGet(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	at := time.Date(2025, 1, 2, 23, 59, 0, 0, time.UTC)
+	svc.Journal().Record(models.ResetEvent{Time: at.Add(-time.Hour), TokenID: "a", Outcome: models.ResetOutcomeSuccess})
+	svc.Journal().Record(models.ResetEvent{Time: at.AddDate(0, 0, -3), TokenID: "a", Outcome: models.ResetOutcomeSuccess})
+
+	daily, err := svc.Generate(models.ReportDaily, at, time.UTC)
+	if err != nil || daily.Name != "daily-2025-01-02" || daily.Totals.Attempts != 1 {
+		t.Fatalf("Generate(daily) = %+v, %v", daily, err)
+	}
+	weekly, err := svc.Generate(models.ReportWeekly, at, time.UTC)
+	if err != nil || weekly.Totals.Attempts != 2 {
+		t.Fatalf("Generate(weekly) = %+v, %v", weekly, err)
+	}
+
+	infos, err := svc.List(models.ReportDaily)
+	if err != nil || len(infos) != 1 || infos[0].Name != "daily-2025-01-02" {
+		t.Fatalf("List(daily) = %+v, %v", infos, err)
+	}
+	if infos, _ := svc.List(""); len(infos) != 2 {
+		t.Fatalf("List() = %+v", infos)
+	}
+
+	if _, err := svc.Get("../resets"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() invalid name error = %v", err)
+	}
+	if _, err := svc.Get("daily-2024-12-31"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() missing report error = %v", err)
+	}
+}
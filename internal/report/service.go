@@ -0,0 +1,180 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// ErrNotFound 报告不存在
+var ErrNotFound = i18n.Errorf("report.not_found")
+
+// namePattern 报告名称：<period>-YYYY-MM-DD
+var namePattern = regexp.MustCompile(`^(daily|weekly)-\d{4}-\d{2}-\d{2}$`)
+
+// Service 生成并保存重置报告（data/reports/<name>.json / .md / .html）
+type Service struct {
+	dir     string
+	journal *Journal
+}
+
+// NewService 创建报告服务
+func NewService(dataDir string) (*Service, error) {
+	dir := filepath.Join(dataDir, "reports")
+	journal, err := NewJournal(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{dir: dir, journal: journal}, nil
+}
+
+// Journal 重置事件日志，由 Token 管理器写入
+func (s *Service) Journal() *Journal {
+	return s.journal
+}
+
+// Generate 生成截至 at 的报告并保存全部格式（Markdown / HTML 按默认语言渲染），同名报告会被覆盖
+func (s *Service) Generate(period string, at time.Time, loc *time.Location) (*models.ResetReport, error) {
+	if period != models.ReportDaily && period != models.ReportWeekly {
+		return nil, i18n.Errorf("report.unknown_period", period)
+	}
+
+	from, to := PeriodRange(period, at)
+	events, err := s.journal.Query(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	rep := Build(period, from, to, events)
+	rep.Name = Name(period, at, loc)
+
+	for _, format := range Formats {
+		data, err := Render(rep, format, i18n.Default(), loc)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFileAtomic(filepath.Join(s.dir, rep.Name+formatExt[format]), data); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("已生成报告 %s: 成功 %d, 跳过 %d, 失败 %d, 待核实 %d", rep.Name, rep.Totals.Succeeded, rep.Totals.Skipped, rep.Totals.Failed, rep.Totals.Pending)
+	return rep, nil
+}
+
+// Get 读取已生成的报告
+func (s *Service) Get(name string) (*models.ResetReport, error) {
+	if !namePattern.MatchString(name) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, name+formatExt[FormatJSON]))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取报告失败: %w", err)
+	}
+
+	var rep models.ResetReport
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("解析报告失败: %w", err)
+	}
+	return &rep, nil
+}
+
+// List 列出已生成的报告（按生成时间从新到旧），period 为空时列出全部
+func (s *Service) List(period string) ([]models.ReportInfo, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]models.ReportInfo, 0, len(names))
+	for _, name := range names {
+		if period != "" && !strings.HasPrefix(name, period+"-") {
+			continue
+		}
+		rep, err := s.Get(name)
+		if err != nil {
+			logger.Warn("跳过无法读取的报告 %s: %v", name, err)
+			continue
+		}
+		infos = append(infos, Info(rep))
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].GeneratedAt.After(infos[j].GeneratedAt)
+	})
+	return infos, nil
+}
+
+// Prune 删除 before 之前生成的报告和重置事件
+func (s *Service) Prune(before time.Time) error {
+	names, err := s.names()
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, name := range names {
+		rep, err := s.Get(name)
+		if err != nil || !rep.GeneratedAt.Before(before) {
+			continue
+		}
+		for _, ext := range formatExt {
+			if err := os.Remove(filepath.Join(s.dir, name+ext)); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("删除报告失败: %w", err)
+			}
+		}
+		removed++
+	}
+
+	events, err := s.journal.Prune(before)
+	if err != nil {
+		return err
+	}
+	if removed > 0 || events > 0 {
+		logger.Info("已清理 %d 份过期报告和 %d 条重置事件", removed, events)
+	}
+	return nil
+}
+
+// names 报告目录中全部报告的名称
+func (s *Service) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("读取报告目录失败: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), formatExt[FormatJSON])
+		if entry.IsDir() || name == entry.Name() || !namePattern.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// writeFileAtomic 先写临时文件再替换，避免读取到写了一半的报告
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("写入报告失败: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("保存报告失败: %w", err)
+	}
+	return nil
+}
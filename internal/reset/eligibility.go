@@ -0,0 +1,46 @@
+package reset
+
+import (
+	"strings"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// lastCreditResetLayouts 服务端 lastCreditReset 可能使用的时间格式（无时区时按配置的时区解析）
+var lastCreditResetLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+// ParseLastCreditReset 解析服务端返回的 lastCreditReset，为空或无法解析时返回 false
+//
+// 不带时区的时间按 loc（配置的 timezone）解析，loc 为空时使用本地时区。
+func ParseLastCreditReset(value *string, loc *time.Location) (time.Time, bool) {
+	if loc == nil {
+		loc = time.Local
+	}
+	if value == nil {
+		return time.Time{}, false
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range lastCreditResetLayouts {
+		if t, err := time.ParseInLocation(layout, trimmed, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NextEligibleAt 根据 lastCreditReset 计算订阅最早可再次重置的时间，无重置记录时返回 false
+func NextEligibleAt(sub models.Subscription, loc *time.Location) (time.Time, bool) {
+	last, ok := ParseLastCreditReset(sub.LastCreditReset, loc)
+	if !ok {
+		return time.Time{}, false
+	}
+	return last.Add(MinResetInterval), true
+}
@@ -0,0 +1,78 @@
+package reset
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestShouldSkipByResetInterval(t *testing.T) {
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, time.Local)
+	format := func(t time.Time) *string {
+		s := t.Format("2006-01-02T15:04:05")
+		return &s
+	}
+
+	t.Run("recent reset is skipped with next eligible time", func(t *testing.T) {
+		sub := models.Subscription{LastCreditReset: format(now.Add(-2 * time.Hour))}
+		d := ResetIntervalRule{}.Evaluate(Context{Subscription: sub, Now: now})
+		if d.Verdict != VerdictSkip || d.NextEligibleAt == nil {
+			t.Fatalf("expected skip with next eligible time, got %+v", d)
+		}
+		if want := now.Add(3 * time.Hour); !d.NextEligibleAt.Equal(want) {
+			t.Fatalf("expected next eligible at %v, got %v", want, *d.NextEligibleAt)
+		}
+		if !strings.Contains(d.Message, d.NextEligibleAt.Format("2006-01-02 15:04:05")) {
+			t.Fatalf("expected reason to mention next eligible time, got %q", d.Message)
+		}
+	})
+
+	t.Run("old reset is allowed", func(t *testing.T) {
+		sub := models.Subscription{LastCreditReset: format(now.Add(-6 * time.Hour))}
+		if d := (ResetIntervalRule{}).Evaluate(Context{Subscription: sub, Now: now}); d.Verdict == VerdictSkip {
+			t.Fatalf("expected no skip, got %q", d.Message)
+		}
+	})
+
+	t.Run("missing or invalid value is allowed", func(t *testing.T) {
+		invalid := "not-a-time"
+		for _, value := range []*string{nil, &invalid} {
+			if d := (ResetIntervalRule{}).Evaluate(Context{Subscription: models.Subscription{LastCreditReset: value}, Now: now}); d.Verdict == VerdictSkip {
+				t.Fatalf("expected no skip, got %q", d.Message)
+			}
+		}
+	})
+}
+
+func TestParseLastCreditReset_UsesConfiguredZone(t *testing.T) {
+	// 选一个与本机时区不同的时区，保证测试在任何 TZ 下都有意义
+	zone := time.FixedZone("UTC+8", 8*3600)
+	if _, offset := time.Now().Zone(); offset == 8*3600 {
+		zone = time.FixedZone("UTC-5", -5*3600)
+	}
+
+	value := "2025-01-02 10:00:00"
+	got, ok := ParseLastCreditReset(&value, zone)
+	if !ok {
+		t.Fatalf("ParseLastCreditReset() failed")
+	}
+	if want := time.Date(2025, 1, 2, 10, 0, 0, 0, zone); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// 带时区的时间不受配置影响
+	explicit := "2025-01-02T10:00:00Z"
+	got, _ = ParseLastCreditReset(&explicit, zone)
+	if want := time.Date(2025, 1, 2, 10, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	// 距上次重置 2 小时（按配置的时区），仍在 5 小时内
+	now := time.Date(2025, 1, 2, 12, 0, 0, 0, zone)
+	d := ResetIntervalRule{Location: zone}.Evaluate(Context{Subscription: models.Subscription{LastCreditReset: &value}, Now: now})
+	if d.Verdict != VerdictSkip || !d.NextEligibleAt.Equal(now.Add(3*time.Hour)) {
+		t.Fatalf("expected skip until %v, got %+v", now.Add(3*time.Hour), d)
+	}
+}
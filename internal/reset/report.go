@@ -1,6 +1,11 @@
 package reset
 
 import (
+	"fmt"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
 	"code88reset/pkg/logger"
 )
 
@@ -26,3 +31,45 @@ func LogResults(results []Result) {
 			res.BeforeResets, res.AfterResets, res.BeforeCredits, res.AfterCredits)
 	}
 }
+
+// Event 将一次重置结果转换为重置事件，供日报/周报汇总
+//
+// 没有 Token 的调度器按账号邮箱区分，邮箱为空时使用订阅 ID；跳过时以第一条未放行的规则作为跳过原因。
+func Event(res Result, resetType, runID string) models.ResetEvent {
+	sub := res.Subscription
+	event := models.ResetEvent{
+		Time:          time.Now(),
+		TokenID:       sub.EmployeeEmail,
+		TokenName:     sub.EmployeeEmail,
+		ResetType:     resetType,
+		RunID:         runID,
+		Outcome:       models.ResetOutcomeSuccess,
+		BeforeCredits: res.BeforeCredits,
+		AfterCredits:  res.AfterCredits,
+		BeforeResets:  res.BeforeResets,
+		AfterResets:   res.AfterResets,
+	}
+	if event.TokenID == "" {
+		event.TokenID = fmt.Sprintf("subscription-%d", sub.ID)
+		event.TokenName = sub.SubscriptionName
+	}
+
+	switch {
+	case res.Skipped:
+		event.Outcome = models.ResetOutcomeSkipped
+		for _, d := range res.Decisions {
+			if d.Verdict != VerdictAllow {
+				event.SkipReason = d.Rule
+				break
+			}
+		}
+		event.SetMessage(res.SkipMessage)
+	case res.Err != nil && res.Verification == VerificationPending:
+		event.Outcome = models.ResetOutcomePending
+		event.SetMessage(i18n.MessageOf(res.Err))
+	case res.Err != nil:
+		event.Outcome = models.ResetOutcomeFailed
+		event.SetMessage(i18n.MessageOf(res.Err))
+	}
+	return event
+}
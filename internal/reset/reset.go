@@ -1,21 +1,27 @@
 package reset
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"code88reset/internal/api"
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/pkg/logger"
 )
 
+// MinResetInterval 服务端要求同一订阅两次重置之间至少间隔 5 小时（否则返回错误码 30001）
+const MinResetInterval = 5 * time.Hour
+
 // Result captures outcome of a reset operation for a single subscription.
 type Result struct {
 	Subscription        models.Subscription
 	ResetResponse       *models.ResetResponse
 	Skipped             bool
-	SkipReason          string
+	SkipReason          string       // 跳过原因（默认语言）
+	SkipMessage         i18n.Message // 带消息码的跳过原因
 	Err                 error
 	BeforeCredits       float64
 	AfterCredits        float64
@@ -23,6 +29,11 @@ type Result struct {
 	AfterResets         int
 	UpdatedSubscription *models.Subscription
 	Attempts            int
+	NextEligibleAt      *time.Time // 因 5 小时规则跳过时，最早可重置的时间
+	Verdict             string     // 规则引擎的最终判定（allow/skip/deny）
+	Decisions           []Decision // 各规则的判定结果
+	Verification        string     // 重置结果核实状态（applied/pending/not_applied）
+	VerifyPolls         int        // 核实阶段查询订阅的次数
 }
 
 // Filter defines user-selected plan names; empty means all MONTHLY subscriptions.
@@ -37,7 +48,12 @@ type Options struct {
 	UseMaxThreshold    bool
 	CreditThresholdMax float64
 	CreditThresholdMin float64
-	SleepBetween       time.Duration
+	SleepBetween       time.Duration  // 重置后首次核实前的等待时间，之后按指数退避轮询
+	VerifyTimeout      time.Duration  // 核实截止时间，默认 DefaultVerifyTimeout
+	VerifyMaxInterval  time.Duration  // 核实轮询间隔上限，默认 DefaultVerifyMaxInterval
+	DisabledRules      []string       // 停用的内置规则
+	Location           *time.Location // 解析服务端不带时区的时间（lastCreditReset）使用的时区，为空时使用本地时区
+	ExtraRules         []Rule         // 追加的自定义规则
 }
 
 // Runner performs reset for all eligible subscriptions under given api client.
@@ -45,6 +61,8 @@ type Runner struct {
 	client *api.Client
 	filter Filter
 	opts   Options
+	engine *Engine
+	sleep  func(time.Duration)
 }
 
 func NewRunner(client *api.Client, filter Filter, opts Options) *Runner {
@@ -54,7 +72,20 @@ func NewRunner(client *api.Client, filter Filter, opts Options) *Runner {
 	if opts.ResetType == "" {
 		opts.ResetType = "first"
 	}
-	return &Runner{client: client, filter: filter, opts: opts}
+	if opts.VerifyTimeout <= 0 {
+		opts.VerifyTimeout = DefaultVerifyTimeout
+	}
+	if opts.VerifyMaxInterval <= 0 {
+		opts.VerifyMaxInterval = DefaultVerifyMaxInterval
+	}
+	rules := append(DefaultRules(opts), opts.ExtraRules...)
+	return &Runner{
+		client: client,
+		filter: filter,
+		opts:   opts,
+		engine: NewEngine(rules...).Without(opts.DisabledRules...),
+		sleep:  time.Sleep,
+	}
 }
 
 // Execute fetches subscriptions, filters them, and resets each eligible one.
@@ -107,7 +138,7 @@ func FilterSubscriptions(subs []models.Subscription, filter Filter) []models.Sub
 			}
 		}
 
-		if isPAYGO(sub) {
+		if sub.IsPAYGO() {
 			continue
 		}
 
@@ -142,20 +173,19 @@ func (r *Runner) processSubscription(sub models.Subscription, fetcher *subscript
 	result.BeforeCredits = sub.CurrentCredits
 	result.BeforeResets = sub.ResetTimes
 
-	if skip, reason := r.shouldSkipByThreshold(sub); skip {
+	eval := r.engine.Evaluate(Context{ResetType: r.opts.ResetType, Subscription: sub, Now: time.Now()})
+	result.Verdict = eval.Verdict
+	result.Decisions = eval.Decisions
+	if !eval.Allowed() {
 		result.Skipped = true
-		result.SkipReason = reason
-		return result
-	}
-
-	if skip, reason := r.shouldSkipByResetTimes(sub); skip {
-		result.Skipped = true
-		result.SkipReason = reason
+		result.SkipReason = eval.Decisive.Message
+		result.SkipMessage = eval.Decisive.CodedMessage()
+		result.NextEligibleAt = eval.Decisive.NextEligibleAt
 		return result
 	}
 
 	current := sub
-	minRequired := r.minRequiredResetTimes()
+	minRequired := MinRequiredResetTimes(r.opts.ResetType)
 
 	refreshAndGet := func() (*models.Subscription, error) {
 		if fetcher != nil {
@@ -168,54 +198,73 @@ func (r *Runner) processSubscription(sub models.Subscription, fetcher *subscript
 		return r.fetchSubscription(current.ID)
 	}
 
+	// 只有确认第一次重置未生效时才会再次调用 ResetCredits，避免重复消耗 resetTimes
 	for attempts := 1; attempts <= 2; attempts++ {
 		result.Attempts = attempts
 		logger.Info("执行重置: subscriptionID=%d (attempt=%d), 当前积分=%.4f, resetTimes=%d",
 			current.ID, attempts, current.CurrentCredits, current.ResetTimes)
 
 		resp, err := r.client.ResetCredits(current.ID)
-		if err != nil {
+		var apiErr *api.APIError
+		if errors.As(err, &apiErr) || errors.Is(err, api.ErrPAYGOProtected) {
+			// 服务端明确拒绝或请求未发出，重置未生效
 			result.Err = err
 			return result
 		}
+		if err != nil {
+			// 网络错误等情况下请求可能已被服务端处理，先核实再决定
+			logger.Warn("订阅 %d 重置请求结果未知: %v，开始核实订阅状态", current.ID, err)
+		}
 		result.ResetResponse = resp
 
-		if r.opts.SleepBetween > 0 {
-			time.Sleep(r.opts.SleepBetween)
+		v := r.verify(current, refreshAndGet)
+		result.Verification = v.State
+		result.VerifyPolls += v.Polls
+		if v.Updated != nil {
+			result.AfterCredits = v.Updated.CurrentCredits
+			result.AfterResets = v.Updated.ResetTimes
+			result.UpdatedSubscription = v.Updated
 		}
 
-		updated, fetchErr := refreshAndGet()
-		if fetchErr != nil {
-			result.Err = fmt.Errorf("验证订阅 %d 状态失败: %w", current.ID, fetchErr)
+		switch v.State {
+		case VerificationApplied:
+			logger.Info("订阅 %d 重置已确认生效 (查询 %d 次)", current.ID, v.Polls)
+			if result.ResetResponse == nil {
+				// 请求出错但核实已生效，没有服务端响应可用
+				result.ResetResponse = &models.ResetResponse{
+					Success: true,
+					Message: i18n.T(i18n.Default(), "reset.confirmed_by_verification"),
+				}
+			}
 			return result
-		}
-
-		result.AfterCredits = updated.CurrentCredits
-		result.AfterResets = updated.ResetTimes
-		result.UpdatedSubscription = updated
-
-		creditsIncreased := result.AfterCredits > result.BeforeCredits
-		resetsReduced := result.AfterResets < result.BeforeResets
-
-		if creditsIncreased && resetsReduced {
+		case VerificationPending:
+			switch {
+			case v.Updated == nil:
+				result.Err = i18n.Errorf("reset.verify_failed", current.ID, v.Err)
+			case err != nil:
+				result.Err = i18n.Errorf("reset.request_unconfirmed", r.opts.VerifyTimeout, err)
+			default:
+				result.Err = i18n.Errorf("reset.pending", r.opts.VerifyTimeout)
+			}
 			return result
 		}
 
-		stillEligible := result.AfterResets >= minRequired
-		if !stillEligible {
-			result.Err = fmt.Errorf("重置后 resetTimes=%d 未减少且不足以继续重置", result.AfterResets)
+		// 已确认未生效
+		updated := v.Updated
+		if updated.ResetTimes < minRequired {
+			result.Err = i18n.Errorf("reset.not_applied_no_times", updated.ResetTimes)
 			return result
 		}
 
 		if attempts == 1 {
-			logger.Warn("订阅 %d 第一次重置后未确认成功，准备重试", current.ID)
+			logger.Warn("订阅 %d 第一次重置确认未生效，准备重试", current.ID)
 			result.BeforeCredits = updated.CurrentCredits
 			result.BeforeResets = updated.ResetTimes
 			current = *updated
 			continue
 		}
 
-		result.Err = fmt.Errorf("多次重置后仍未确认成功 (resetTimes=%d, credits=%.4f)", result.AfterResets, result.AfterCredits)
+		result.Err = i18n.Errorf("reset.not_applied", result.AfterResets, fmt.Sprintf("%.4f", result.AfterCredits))
 		return result
 	}
 
@@ -248,9 +297,9 @@ func (f *subscriptionFetcher) current(id int) (models.Subscription, bool) {
 
 func (f *subscriptionFetcher) refreshAndGet(id int) (*models.Subscription, error) {
 	if f == nil {
-		return nil, fmt.Errorf("subscription fetcher unavailable")
+		return nil, errors.New("subscription fetcher unavailable")
 	}
-	subs, err := f.client.GetSubscriptions()
+	subs, err := f.client.RefreshSubscriptions()
 	if err != nil {
 		return nil, err
 	}
@@ -261,80 +310,18 @@ func (f *subscriptionFetcher) refreshAndGet(id int) (*models.Subscription, error
 	f.cache = cache
 	updated, ok := f.cache[id]
 	if !ok {
-		return nil, fmt.Errorf("未找到订阅 ID=%d", id)
+		return nil, i18n.Errorf("api.subscription_not_found", id)
 	}
 	return &updated, nil
 }
 
 func (r *Runner) fetchSubscription(id int) (*models.Subscription, error) {
-	subs, err := r.client.GetSubscriptions()
-	if err != nil {
-		return nil, err
-	}
-	for _, sub := range subs {
-		if sub.ID == id {
-			return &sub, nil
-		}
-	}
-	return nil, fmt.Errorf("未找到订阅 ID=%d", id)
+	return r.client.GetSubscription(id, true)
 }
 
+// shouldSkipByThreshold 单独执行额度阈值规则
 func (r *Runner) shouldSkipByThreshold(sub models.Subscription) (bool, string) {
-	if sub.SubscriptionPlan.CreditLimit <= 0 {
-		return false, ""
-	}
-
-	percent := (sub.CurrentCredits / sub.SubscriptionPlan.CreditLimit) * 100
-
-	// 第二次重置：也使用用户配置的阈值（默认100%）
-	if strings.EqualFold(r.opts.ResetType, "second") {
-		// 使用上限模式的阈值（如果设置）
-		if r.opts.UseMaxThreshold && r.opts.CreditThresholdMax > 0 {
-			if percent >= r.opts.CreditThresholdMax {
-				return true, fmt.Sprintf("额度充足(%.2f%% >= %.1f%%)", percent, r.opts.CreditThresholdMax)
-			}
-		}
-		return false, ""
-	}
-
-	// 第一次重置：使用用户配置的阈值（默认70%）
-	if r.opts.UseMaxThreshold && r.opts.CreditThresholdMax > 0 {
-		if percent > r.opts.CreditThresholdMax {
-			return true, fmt.Sprintf("额度充足(%.2f%% > %.1f%%)", percent, r.opts.CreditThresholdMax)
-		}
-	}
-
-	if !r.opts.UseMaxThreshold && r.opts.CreditThresholdMin > 0 {
-		if percent >= r.opts.CreditThresholdMin {
-			return true, fmt.Sprintf("额度充足(%.2f%% >= %.1f%%)", percent, r.opts.CreditThresholdMin)
-		}
-	}
-
-	return false, ""
-}
-
-func (r *Runner) shouldSkipByResetTimes(sub models.Subscription) (bool, string) {
-	minRequired := r.minRequiredResetTimes()
-	if sub.ResetTimes < minRequired {
-		return true, fmt.Sprintf("resetTimes不足(需要>=%d)", minRequired)
-	}
-	return false, ""
-}
-
-func (r *Runner) minRequiredResetTimes() int {
-	if strings.EqualFold(r.opts.ResetType, "second") {
-		return 1
-	}
-	return 2
-}
-
-func isPAYGO(sub models.Subscription) bool {
-	if strings.EqualFold(sub.SubscriptionName, "PAYGO") {
-		return true
-	}
-	if strings.EqualFold(sub.SubscriptionPlan.SubscriptionName, "PAYGO") {
-		return true
-	}
-	planType := strings.ToUpper(strings.TrimSpace(sub.SubscriptionPlan.PlanType))
-	return planType == "PAYGO" || planType == "PAY_PER_USE"
+	rule := CreditThresholdRule{UseMaxThreshold: r.opts.UseMaxThreshold, Max: r.opts.CreditThresholdMax, Min: r.opts.CreditThresholdMin}
+	d := rule.Evaluate(Context{ResetType: r.opts.ResetType, Subscription: sub})
+	return d.Verdict == VerdictSkip, d.Message
 }
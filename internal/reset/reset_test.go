@@ -32,7 +32,8 @@ func TestShouldSkipByThreshold_FirstResetUsesMaxThreshold(t *testing.T) {
 	}
 }
 
-func TestShouldSkipByThreshold_SecondResetIgnoresThreshold(t *testing.T) {
+// 第二次重置同样使用额度上限阈值，额度达到阈值（含等于）时跳过
+func TestShouldSkipByThreshold_SecondResetUsesMaxThreshold(t *testing.T) {
 	r := &Runner{
 		opts: Options{
 			ResetType:          "second",
@@ -42,17 +43,22 @@ func TestShouldSkipByThreshold_SecondResetIgnoresThreshold(t *testing.T) {
 	}
 
 	sub := models.Subscription{
-		CurrentCredits: 90,
+		CurrentCredits: 80,
 		SubscriptionPlan: models.SubscriptionPlan{
 			CreditLimit: 100,
 		},
 	}
 
 	skip, reason := r.shouldSkipByThreshold(sub)
-	if skip {
-		t.Fatalf("expected skip=false for second reset, got true with reason %q", reason)
+	if !skip {
+		t.Fatalf("expected skip=true for second reset at threshold, got false")
 	}
-	if reason != "" {
-		t.Fatalf("expected empty reason for second reset, got %q", reason)
+	if !strings.Contains(reason, ">=") {
+		t.Fatalf("expected skip reason to use >=, got %q", reason)
+	}
+
+	sub.CurrentCredits = 50
+	if skip, reason := r.shouldSkipByThreshold(sub); skip {
+		t.Fatalf("expected skip=false for second reset below threshold, got true with reason %q", reason)
 	}
 }
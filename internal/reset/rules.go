@@ -0,0 +1,325 @@
+package reset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+)
+
+// 规则判定结果
+const (
+	VerdictAllow = "allow" // 允许重置
+	VerdictSkip  = "skip"  // 本次不重置（条件未满足，属正常情况）
+	VerdictDeny  = "deny"  // 禁止重置（保护性规则）
+)
+
+// 内置规则名称，可在调度项的 disabled_rules 中引用
+const (
+	RulePAYGO             = "paygo"
+	RuleCreditThreshold   = "credit_threshold"
+	RuleResetTimes        = "reset_times"
+	RuleResetInterval     = "reset_interval"
+	RuleAlreadyResetToday = "already_reset_today"
+)
+
+// ResetTypeLowCredit 低额度提醒触发的立即重置：与第一次重置一样需为定时的第二次重置保留一次 resetTimes，
+// 额度阈值使用低额度提醒的阈值
+const ResetTypeLowCredit = "low_credit"
+
+// mandatoryRules 不允许停用的规则
+var mandatoryRules = map[string]bool{RulePAYGO: true}
+
+// RuleInfo 内置规则说明
+type RuleInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Mandatory   bool   `json:"mandatory"` // 不允许停用
+}
+
+// BuiltinRules 列出全部内置规则，说明按默认语言渲染
+func BuiltinRules() []RuleInfo {
+	return LocalizedRules(i18n.Default())
+}
+
+// LocalizedRules 列出全部内置规则，说明按指定语言渲染（消息码为 reset.rule_description.<Name>）
+func LocalizedRules(locale i18n.Locale) []RuleInfo {
+	rules := []RuleInfo{
+		{Name: RulePAYGO, Mandatory: true},
+		{Name: RuleResetTimes},
+		{Name: RuleCreditThreshold},
+		{Name: RuleResetInterval},
+		{Name: RuleAlreadyResetToday},
+	}
+	for i := range rules {
+		rules[i].Description = i18n.T(locale, "reset.rule_description."+rules[i].Name)
+	}
+	return rules
+}
+
+// Decision 单条规则的判定结果
+type Decision = models.RuleDecision
+
+// Context 规则判定所需的上下文
+type Context struct {
+	ResetType         string
+	Subscription      models.Subscription
+	AlreadyResetToday bool // 该调度项今天是否已执行过（仅调度级规则使用）
+	Now               time.Time
+}
+
+// Rule 重置规则
+type Rule interface {
+	Name() string
+	Evaluate(ctx Context) Decision
+}
+
+// Evaluation 一组规则的综合判定
+type Evaluation struct {
+	Verdict   string     `json:"verdict"`
+	Decisive  *Decision  `json:"decisive,omitempty"` // 决定最终结果的规则，允许时为空
+	Decisions []Decision `json:"decisions"`
+}
+
+// Allowed 是否允许重置
+func (e Evaluation) Allowed() bool {
+	return e.Verdict == VerdictAllow
+}
+
+// Engine 按顺序执行规则并汇总判定
+type Engine struct {
+	rules []Rule
+}
+
+// NewEngine 创建规则引擎
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules}
+}
+
+// Without 返回去掉指定规则后的引擎，强制规则（PAYGO 保护）不会被去掉
+func (e *Engine) Without(names ...string) *Engine {
+	if len(names) == 0 {
+		return e
+	}
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		disabled[strings.TrimSpace(name)] = true
+	}
+
+	rules := make([]Rule, 0, len(e.rules))
+	for _, rule := range e.rules {
+		if disabled[rule.Name()] && !mandatoryRules[rule.Name()] {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return &Engine{rules: rules}
+}
+
+// Evaluate 执行全部规则并记录每条规则的判定；任一规则拒绝则拒绝，否则任一规则跳过则跳过
+func (e *Engine) Evaluate(ctx Context) Evaluation {
+	if ctx.Now.IsZero() {
+		ctx.Now = time.Now()
+	}
+
+	eval := Evaluation{Verdict: VerdictAllow, Decisions: make([]Decision, 0, len(e.rules))}
+	for _, rule := range e.rules {
+		d := rule.Evaluate(ctx)
+		d.Rule = rule.Name()
+		if d.Verdict == "" {
+			d.Verdict = VerdictAllow
+		}
+		eval.Decisions = append(eval.Decisions, d)
+	}
+
+	for _, verdict := range []string{VerdictDeny, VerdictSkip} {
+		for i := range eval.Decisions {
+			if eval.Decisions[i].Verdict == verdict {
+				eval.Verdict = verdict
+				eval.Decisive = &eval.Decisions[i]
+				return eval
+			}
+		}
+	}
+	return eval
+}
+
+// DefaultRules 订阅级的内置规则
+func DefaultRules(opts Options) []Rule {
+	return []Rule{
+		PAYGORule{},
+		ResetTimesRule{},
+		CreditThresholdRule{UseMaxThreshold: opts.UseMaxThreshold, Max: opts.CreditThresholdMax, Min: opts.CreditThresholdMin},
+		ResetIntervalRule{Location: opts.Location},
+	}
+}
+
+// EvaluateRun 评估调度级规则（今天是否已执行过该调度项）
+func EvaluateRun(resetType string, status *models.ExecutionStatus, disabledRules []string) Evaluation {
+	done := false
+	if status != nil {
+		if strings.EqualFold(resetType, "second") {
+			done = status.SecondResetToday
+		} else {
+			done = status.FirstResetToday
+		}
+	}
+	return NewEngine(AlreadyResetTodayRule{}).Without(disabledRules...).Evaluate(Context{
+		ResetType:         resetType,
+		AlreadyResetToday: done,
+	})
+}
+
+// ValidateDisabledRules 校验调度项中停用的规则名称
+func ValidateDisabledRules(names []string) error {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if mandatoryRules[name] {
+			return i18n.Errorf("reset.rule_mandatory", name)
+		}
+		known := false
+		for _, builtin := range BuiltinRules() {
+			if builtin.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return i18n.Errorf("reset.rule_unknown", name)
+		}
+	}
+	return nil
+}
+
+// PAYGORule 永远不重置按量付费订阅
+type PAYGORule struct{}
+
+func (PAYGORule) Name() string { return RulePAYGO }
+
+func (PAYGORule) Evaluate(ctx Context) Decision {
+	if ctx.Subscription.IsPAYGO() {
+		return newDecision(VerdictDeny, "paygo_protected")
+	}
+	return Decision{}
+}
+
+// ResetTimesRule 剩余重置次数不足时跳过：第一次重置和低额度立即重置需保留一次给第二次，要求 >= 2
+type ResetTimesRule struct{}
+
+func (ResetTimesRule) Name() string { return RuleResetTimes }
+
+func (ResetTimesRule) Evaluate(ctx Context) Decision {
+	minRequired := MinRequiredResetTimes(ctx.ResetType)
+	if ctx.Subscription.ResetTimes < minRequired {
+		return newDecision(VerdictSkip, "reset_times_insufficient", minRequired)
+	}
+	return Decision{}
+}
+
+// CreditThresholdRule 额度比例达到阈值时跳过
+type CreditThresholdRule struct {
+	UseMaxThreshold bool
+	Max             float64
+	Min             float64
+}
+
+func (CreditThresholdRule) Name() string { return RuleCreditThreshold }
+
+func (r CreditThresholdRule) Evaluate(ctx Context) Decision {
+	sub := ctx.Subscription
+	if sub.SubscriptionPlan.CreditLimit <= 0 {
+		return Decision{}
+	}
+
+	percent := (sub.CurrentCredits / sub.SubscriptionPlan.CreditLimit) * 100
+	skip := func(op string, threshold float64) Decision {
+		return newDecision(VerdictSkip, "credit_sufficient",
+			fmt.Sprintf("%.2f", percent), op, fmt.Sprintf("%.1f", threshold))
+	}
+
+	// 第二次重置：也使用用户配置的阈值（默认100%）；低额度立即重置：额度已回到提醒阈值以上时跳过
+	if strings.EqualFold(ctx.ResetType, "second") || strings.EqualFold(ctx.ResetType, ResetTypeLowCredit) {
+		if r.UseMaxThreshold && r.Max > 0 && percent >= r.Max {
+			return skip(">=", r.Max)
+		}
+		return Decision{}
+	}
+
+	// 第一次重置：使用用户配置的阈值（默认70%）
+	if r.UseMaxThreshold && r.Max > 0 && percent > r.Max {
+		return skip(">", r.Max)
+	}
+	if !r.UseMaxThreshold && r.Min > 0 && percent >= r.Min {
+		return skip(">=", r.Min)
+	}
+	return Decision{}
+}
+
+// ResetIntervalRule 距服务端记录的上次重置不足 Interval（默认 MinResetInterval）时跳过，避免返回 30001
+type ResetIntervalRule struct {
+	Interval time.Duration
+	Location *time.Location // 解析 lastCreditReset 使用的时区，为空时使用本地时区
+}
+
+func (ResetIntervalRule) Name() string { return RuleResetInterval }
+
+func (r ResetIntervalRule) Evaluate(ctx Context) Decision {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = MinResetInterval
+	}
+
+	last, ok := ParseLastCreditReset(ctx.Subscription.LastCreditReset, r.Location)
+	if !ok {
+		return Decision{}
+	}
+	next := last.Add(interval)
+	if !ctx.Now.Before(next) {
+		return Decision{}
+	}
+
+	remaining := next.Sub(ctx.Now).Round(time.Minute)
+	d := newDecision(VerdictSkip, "reset_interval",
+		fmt.Sprintf("%.0f", interval.Hours()), remaining, next.Format("2006-01-02 15:04:05"))
+	d.NextEligibleAt = &next
+	return d
+}
+
+// AlreadyResetTodayRule 同一调度项每天只执行一次
+type AlreadyResetTodayRule struct{}
+
+func (AlreadyResetTodayRule) Name() string { return RuleAlreadyResetToday }
+
+func (AlreadyResetTodayRule) Evaluate(ctx Context) Decision {
+	if !ctx.AlreadyResetToday {
+		return Decision{}
+	}
+	return newDecision(VerdictSkip, "already_reset_today", ResetTypeLabel(ctx.ResetType))
+}
+
+// newDecision 创建带消息码的判定结果，Message 为默认语言的文案
+func newDecision(verdict, code string, args ...interface{}) Decision {
+	msg := i18n.M(models.RuleMessagePrefix+code, args...)
+	return Decision{Verdict: verdict, Code: code, Message: msg.String(), Args: msg.Args}
+}
+
+// ResetTypeLabel 重置类型的显示文案（第一次/第二次/低额度）
+func ResetTypeLabel(resetType string) i18n.Label {
+	switch {
+	case strings.EqualFold(resetType, "second"):
+		return "reset.type.second"
+	case strings.EqualFold(resetType, ResetTypeLowCredit):
+		return "reset.type.low_credit"
+	}
+	return "reset.type.first"
+}
+
+// MinRequiredResetTimes 该类型的重置要求的最少剩余 resetTimes：只有第二次重置可以用掉最后一次
+func MinRequiredResetTimes(resetType string) int {
+	if strings.EqualFold(resetType, "second") {
+		return 1
+	}
+	return 2
+}
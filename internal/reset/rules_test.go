@@ -0,0 +1,77 @@
+package reset
+
+import (
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestEngine_RecordsAllDecisionsAndDenyWins(t *testing.T) {
+	sub := models.Subscription{
+		SubscriptionName: "PAYGO",
+		CurrentCredits:   90,
+		ResetTimes:       0,
+		SubscriptionPlan: models.SubscriptionPlan{CreditLimit: 100},
+	}
+	engine := NewEngine(DefaultRules(Options{UseMaxThreshold: true, CreditThresholdMax: 80})...)
+
+	eval := engine.Evaluate(Context{ResetType: "first", Subscription: sub})
+	if eval.Verdict != VerdictDeny || eval.Decisive == nil || eval.Decisive.Rule != RulePAYGO {
+		t.Fatalf("expected PAYGO deny to win, got %+v", eval)
+	}
+	if len(eval.Decisions) != 4 {
+		t.Fatalf("expected every rule to be recorded, got %+v", eval.Decisions)
+	}
+
+	// PAYGO 保护不可停用，其余规则可以
+	eval = engine.Without(RulePAYGO, RuleResetTimes, RuleCreditThreshold).Evaluate(Context{ResetType: "first", Subscription: sub})
+	if eval.Verdict != VerdictDeny || len(eval.Decisions) != 2 {
+		t.Fatalf("expected PAYGO rule to survive Without, got %+v", eval)
+	}
+
+	sub.SubscriptionName = "PLUS"
+	eval = engine.Without(RuleCreditThreshold).Evaluate(Context{ResetType: "first", Subscription: sub})
+	if eval.Verdict != VerdictSkip || eval.Decisive.Code != "reset_times_insufficient" {
+		t.Fatalf("expected reset times skip, got %+v", eval)
+	}
+}
+
+func TestLowCreditReset_KeepsReserveAndChecksThreshold(t *testing.T) {
+	engine := NewEngine(DefaultRules(Options{UseMaxThreshold: true, CreditThresholdMax: 20})...)
+	sub := models.Subscription{
+		SubscriptionName: "PLUS",
+		CurrentCredits:   10,
+		ResetTimes:       1,
+		SubscriptionPlan: models.SubscriptionPlan{CreditLimit: 100},
+	}
+
+	eval := engine.Evaluate(Context{ResetType: ResetTypeLowCredit, Subscription: sub, Now: time.Now()})
+	if eval.Verdict != VerdictSkip || eval.Decisive.Rule != RuleResetTimes {
+		t.Fatalf("expected the last resetTimes to be reserved, got %+v", eval)
+	}
+
+	sub.ResetTimes = 2
+	if eval := engine.Evaluate(Context{ResetType: ResetTypeLowCredit, Subscription: sub, Now: time.Now()}); !eval.Allowed() {
+		t.Fatalf("expected low-credit reset to be allowed, got %+v", eval)
+	}
+
+	// 额度已回到提醒阈值以上
+	sub.CurrentCredits = 30
+	eval = engine.Evaluate(Context{ResetType: ResetTypeLowCredit, Subscription: sub, Now: time.Now()})
+	if eval.Verdict != VerdictSkip || eval.Decisive.Rule != RuleCreditThreshold {
+		t.Fatalf("expected credit threshold skip, got %+v", eval)
+	}
+}
+
+func TestValidateDisabledRules(t *testing.T) {
+	if err := ValidateDisabledRules([]string{RuleCreditThreshold, RuleAlreadyResetToday}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ValidateDisabledRules([]string{RulePAYGO}); err == nil {
+		t.Fatal("expected error when disabling PAYGO rule")
+	}
+	if err := ValidateDisabledRules([]string{"unknown"}); err == nil {
+		t.Fatal("expected error for unknown rule")
+	}
+}
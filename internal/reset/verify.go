@@ -0,0 +1,113 @@
+package reset
+
+import (
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// 重置结果核实状态
+const (
+	VerificationApplied    = "applied"     // 已确认生效
+	VerificationPending    = "pending"     // 截止前未观察到变化，可能尚未同步，不再重复重置
+	VerificationNotApplied = "not_applied" // 服务端记录的上次重置时间未变化，确认未生效
+)
+
+// 核实轮询默认参数
+const (
+	DefaultVerifyTimeout     = 30 * time.Second
+	DefaultVerifyMaxInterval = 10 * time.Second
+)
+
+// verification 一次重置的核实结果
+type verification struct {
+	State   string
+	Updated *models.Subscription // 最近一次成功获取的订阅
+	Polls   int
+	Err     error // 最近一次获取订阅失败的原因
+}
+
+// verify 按退避间隔轮询订阅状态，直到确认重置生效或超过截止时间
+func (r *Runner) verify(before models.Subscription, fetch func() (*models.Subscription, error)) verification {
+	deadline := time.Now().Add(r.opts.VerifyTimeout)
+	delay := r.opts.SleepBetween
+
+	var v verification
+	for {
+		r.sleep(delay)
+		v.Polls++
+
+		updated, err := fetch()
+		if err != nil {
+			v.Err = err
+		} else {
+			v.Updated, v.Err = updated, nil
+			if resetApplied(before, *updated, r.opts.Location) {
+				v.State = VerificationApplied
+				return v
+			}
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			break
+		}
+		delay *= 2
+		if delay <= 0 {
+			delay = time.Second
+		}
+		if delay > r.opts.VerifyMaxInterval {
+			delay = r.opts.VerifyMaxInterval
+		}
+		if delay > remaining {
+			delay = remaining
+		}
+	}
+
+	if v.Updated != nil && resetNotApplied(before, *v.Updated, r.opts.Location) {
+		v.State = VerificationNotApplied
+	} else {
+		v.State = VerificationPending
+	}
+	return v
+}
+
+// VerificationState 对比重置前后的订阅判断重置是否生效，用于事后重新核实待核实的重置
+func VerificationState(before, after models.Subscription, loc *time.Location) string {
+	switch {
+	case resetApplied(before, after, loc):
+		return VerificationApplied
+	case resetNotApplied(before, after, loc):
+		return VerificationNotApplied
+	default:
+		return VerificationPending
+	}
+}
+
+// resetApplied resetTimes 减少或服务端记录的上次重置时间更新，即认为重置已生效
+func resetApplied(before, after models.Subscription, loc *time.Location) bool {
+	if after.ResetTimes < before.ResetTimes {
+		return true
+	}
+	afterReset, ok := ParseLastCreditReset(after.LastCreditReset, loc)
+	if !ok {
+		return false
+	}
+	beforeReset, ok := ParseLastCreditReset(before.LastCreditReset, loc)
+	return !ok || afterReset.After(beforeReset)
+}
+
+// resetNotApplied 服务端有上次重置时间记录且未变化、resetTimes 也未减少，确认重置未生效
+//
+// 服务端没有返回上次重置时间时无法区分“未生效”和“尚未同步”，按未确认处理。
+func resetNotApplied(before, after models.Subscription, loc *time.Location) bool {
+	if after.ResetTimes < before.ResetTimes {
+		return false
+	}
+	beforeReset, ok := ParseLastCreditReset(before.LastCreditReset, loc)
+	if !ok {
+		return false
+	}
+	afterReset, ok := ParseLastCreditReset(after.LastCreditReset, loc)
+	return ok && afterReset.Equal(beforeReset)
+}
@@ -0,0 +1,162 @@
+package reset
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/models"
+)
+
+// fakeResetServer 模拟 88code：重置后需要 visibleAfter 次查询才能看到变化，applies=false 时忽略重置，
+// dropReset=true 时服务端处理重置后直接断开连接
+type fakeResetServer struct {
+	mu           sync.Mutex
+	sub          models.Subscription
+	applies      bool
+	visibleAfter int
+	dropReset    bool
+	resetCalls   int
+	pendingGets  int
+}
+
+func (f *fakeResetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if strings.Contains(r.URL.Path, "reset-credits") {
+		f.resetCalls++
+		f.pendingGets = f.visibleAfter
+		if f.dropReset {
+			if conn, _, err := w.(http.Hijacker).Hijack(); err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte(`{"code":0,"ok":true,"msg":"ok"}`))
+		return
+	}
+
+	if f.applies && f.resetCalls > 0 && f.pendingGets == 0 && f.sub.ResetTimes == 2 {
+		f.sub.ResetTimes--
+		f.sub.CurrentCredits = f.sub.SubscriptionPlan.CreditLimit
+	}
+	if f.pendingGets > 0 {
+		f.pendingGets--
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": 0, "ok": true, "data": []models.Subscription{f.sub}})
+}
+
+func newVerifyRunner(t *testing.T, f *fakeResetServer) *Runner {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	return NewRunner(api.NewClient(srv.URL, "key", nil), Filter{RequireMonthly: true}, Options{
+		ResetType:         "second",
+		SleepBetween:      time.Millisecond,
+		VerifyMaxInterval: 5 * time.Millisecond,
+		VerifyTimeout:     100 * time.Millisecond,
+	})
+}
+
+func verifySubscription(lastReset *string) models.Subscription {
+	return models.Subscription{
+		ID:               1,
+		SubscriptionName: "PRO",
+		CurrentCredits:   10,
+		ResetTimes:       2,
+		LastCreditReset:  lastReset,
+		SubscriptionPlan: models.SubscriptionPlan{PlanType: "MONTHLY", CreditLimit: 100},
+	}
+}
+
+func TestExecute_WaitsForDelayedVisibility(t *testing.T) {
+	f := &fakeResetServer{sub: verifySubscription(nil), applies: true, visibleAfter: 3}
+	results, err := newVerifyRunner(t, f).Execute()
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Execute() = %v, %v", results, err)
+	}
+
+	res := results[0]
+	if res.Err != nil || res.Verification != VerificationApplied || res.AfterResets != 1 {
+		t.Fatalf("expected applied result, got %+v", res)
+	}
+	if f.resetCalls != 1 || res.VerifyPolls < 2 {
+		t.Fatalf("expected one reset call and several polls, got calls=%d polls=%d", f.resetCalls, res.VerifyPolls)
+	}
+}
+
+func TestExecute_PendingDoesNotResetAgain(t *testing.T) {
+	f := &fakeResetServer{sub: verifySubscription(nil), applies: false}
+	results, _ := newVerifyRunner(t, f).Execute()
+
+	res := results[0]
+	if res.Verification != VerificationPending || res.Err == nil {
+		t.Fatalf("expected pending result, got %+v", res)
+	}
+	if f.resetCalls != 1 {
+		t.Fatalf("expected no second reset call, got %d", f.resetCalls)
+	}
+}
+
+func TestExecute_RetriesWhenClearlyNotApplied(t *testing.T) {
+	last := time.Now().Add(-10 * time.Hour).Format("2006-01-02T15:04:05")
+	f := &fakeResetServer{sub: verifySubscription(&last), applies: false}
+	results, _ := newVerifyRunner(t, f).Execute()
+
+	res := results[0]
+	if res.Verification != VerificationNotApplied || res.Err == nil || res.Attempts != 2 {
+		t.Fatalf("expected not-applied result after retry, got %+v", res)
+	}
+	if f.resetCalls != 2 {
+		t.Fatalf("expected exactly one retry, got %d reset calls", f.resetCalls)
+	}
+}
+
+func TestExecute_NetworkErrorVerifiedApplied(t *testing.T) {
+	f := &fakeResetServer{sub: verifySubscription(nil), applies: true, dropReset: true}
+	results, err := newVerifyRunner(t, f).Execute()
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Execute() = %v, %v", results, err)
+	}
+
+	res := results[0]
+	if res.Err != nil || res.Verification != VerificationApplied || res.ResetResponse == nil || res.ResetResponse.Message == "" {
+		t.Fatalf("expected applied result with a response message, got %+v", res)
+	}
+	if f.resetCalls != 1 {
+		t.Fatalf("expected one reset call, got %d", f.resetCalls)
+	}
+	LogResults(results)
+}
+
+func TestEvent_Outcomes(t *testing.T) {
+	sub := models.Subscription{ID: 3, EmployeeEmail: "a@example.com"}
+	cases := []struct {
+		res  Result
+		want string
+	}{
+		{Result{Subscription: sub}, models.ResetOutcomeSuccess},
+		{Result{Subscription: sub, Skipped: true, Decisions: []Decision{{Rule: RulePAYGO, Verdict: VerdictAllow}, {Rule: RuleCreditThreshold, Verdict: VerdictSkip}}}, models.ResetOutcomeSkipped},
+		{Result{Subscription: sub, Err: errors.New("boom"), Verification: VerificationPending}, models.ResetOutcomePending},
+		{Result{Subscription: sub, Err: errors.New("boom"), Verification: VerificationNotApplied}, models.ResetOutcomeFailed},
+	}
+	for _, c := range cases {
+		event := Event(c.res, "second", "")
+		if event.Outcome != c.want || event.TokenID != "a@example.com" {
+			t.Errorf("Event() = %+v, want outcome %s", event, c.want)
+		}
+	}
+
+	skipped := Event(cases[1].res, "second", "")
+	if skipped.SkipReason != RuleCreditThreshold {
+		t.Errorf("skip reason = %q", skipped.SkipReason)
+	}
+}
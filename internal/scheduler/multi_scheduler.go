@@ -26,6 +26,8 @@ type MultiScheduler struct {
 	loop               *loopController
 	accountUpdater     accountUpdater
 	logAgg             *logAggregator
+	clientOpts         *api.ClientOptions // API 网络选项，为空时使用默认值
+	journal            ResetJournal       // 重置事件日志，为空时不记录
 }
 
 // NewMultiSchedulerWithAccounts 创建新的多账号调度器（使用指定的账号列表）
@@ -64,6 +66,16 @@ func NewMultiSchedulerWithConfig(storage *storage.Storage, baseURL string, activ
 	}, nil
 }
 
+// SetClientOptions 设置创建 API 客户端时使用的网络选项
+func (s *MultiScheduler) SetClientOptions(opts *api.ClientOptions) {
+	s.clientOpts = opts
+}
+
+// SetResetJournal 设置重置事件日志，为空时不记录
+func (s *MultiScheduler) SetResetJournal(journal ResetJournal) {
+	s.journal = journal
+}
+
 // Start 启动多账号调度器
 func (s *MultiScheduler) Start() {
 	logger.Info("========================================")
@@ -197,14 +209,10 @@ func (s *MultiScheduler) executeResetForAccount(acc models.AccountConfig, resetT
 	}
 
 	// 检查今天是否已经执行过此次重置
-	if resetType == "first" && status.FirstResetToday {
-		logger.Info("账号 %s 今天已执行过第一次重置，跳过", employeeEmail)
+	if eval := reset.EvaluateRun(resetType, status, nil); !eval.Allowed() {
+		logger.Info("账号 %s %s，跳过", employeeEmail, eval.Decisive.Message)
 		return true // 返回 true 因为已经完成
 	}
-	if resetType == "second" && status.SecondResetToday {
-		logger.Info("账号 %s 今天已执行过第二次重置，跳过", employeeEmail)
-		return true
-	}
 
 	// 检查时间间隔
 	var lastResetTime *time.Time
@@ -221,7 +229,7 @@ func (s *MultiScheduler) executeResetForAccount(acc models.AccountConfig, resetT
 	}
 
 	// 创建客户端
-	client := api.NewClient(s.baseURL, acc.APIKey, s.targetPlans)
+	client := api.NewClientWithOptions(s.baseURL, acc.APIKey, s.targetPlans, s.clientOpts)
 	client.Storage = s.storage
 
 	runner := reset.NewRunner(
@@ -232,6 +240,7 @@ func (s *MultiScheduler) executeResetForAccount(acc models.AccountConfig, resetT
 			UseMaxThreshold:    s.useMaxThreshold,
 			CreditThresholdMax: s.creditThresholdMax,
 			CreditThresholdMin: s.creditThresholdMin,
+			Location:           s.location,
 		},
 	)
 
@@ -249,6 +258,7 @@ func (s *MultiScheduler) executeResetForAccount(acc models.AccountConfig, resetT
 	}
 
 	reset.LogResults(results)
+	recordResetEvents(s.journal, results, resetType)
 
 	anySuccess := false
 	anyError := false
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"code88reset/internal/api"
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/internal/reset"
 	"code88reset/internal/storage"
@@ -23,7 +24,7 @@ const (
 	SecondResetMinute = 55
 
 	// 最小间隔时间（5小时）
-	MinResetInterval = 5 * time.Hour
+	MinResetInterval = reset.MinResetInterval
 )
 
 // Scheduler 调度器
@@ -38,6 +39,24 @@ type Scheduler struct {
 	loop               *loopController
 	accountUpdater     accountUpdater
 	logAgg             *logAggregator
+	journal            ResetJournal // 重置事件日志，为空时不记录
+}
+
+// ResetJournal 保存每次重置尝试的结果，供日报/周报汇总
+type ResetJournal interface {
+	Record(event models.ResetEvent) error
+}
+
+// recordResetEvents 将重置结果写入重置事件日志，写入失败只记录警告
+func recordResetEvents(journal ResetJournal, results []reset.Result, resetType string) {
+	if journal == nil {
+		return
+	}
+	for _, res := range results {
+		if err := journal.Record(reset.Event(res, resetType, "")); err != nil {
+			logger.Warn("记录重置事件失败: %v", err)
+		}
+	}
 }
 
 // NewScheduler 创建新的调度器
@@ -72,6 +91,11 @@ func NewSchedulerWithConfig(apiClient *api.Client, storage *storage.Storage, tim
 	}, nil
 }
 
+// SetResetJournal 设置重置事件日志，为空时不记录
+func (s *Scheduler) SetResetJournal(journal ResetJournal) {
+	s.journal = journal
+}
+
 // Start 启动调度器
 func (s *Scheduler) Start() {
 	logger.Info("========================================")
@@ -139,13 +163,13 @@ func (s *Scheduler) checkAndExecute() {
 // executeReset 执行重置逻辑
 func (s *Scheduler) executeReset(resetType string) {
 	s.logAgg.Flush()
-	resetTypeText := map[string]string{"first": "第一次", "second": "第二次"}[resetType]
+	resetTypeLabel := reset.ResetTypeLabel(resetType)
 	logger.Info("========================================")
-	logger.Info("触发%s重置任务", resetTypeText)
+	logger.Info("%s", i18n.T(i18n.Default(), "scheduler.triggered", resetTypeLabel))
 	logger.Info("========================================")
 
 	// 记录到系统日志
-	s.storage.AddSystemLog("info", fmt.Sprintf("触发%s重置任务", resetTypeText))
+	s.storage.AddSystemMessage("info", i18n.M("scheduler.triggered", resetTypeLabel))
 
 	// 尝试获取锁
 	operation := fmt.Sprintf("%s_reset", resetType)
@@ -163,14 +187,9 @@ func (s *Scheduler) executeReset(resetType string) {
 	}
 
 	// 检查今天是否已经执行过此次重置
-	if resetType == "first" && status.FirstResetToday {
-		logger.Info("今天已执行过第一次重置，跳过")
-		s.storage.AddSystemLog("info", "今天已执行过第一次重置，跳过")
-		return
-	}
-	if resetType == "second" && status.SecondResetToday {
-		logger.Info("今天已执行过第二次重置，跳过")
-		s.storage.AddSystemLog("info", "今天已执行过第二次重置，跳过")
+	if eval := reset.EvaluateRun(resetType, status, nil); !eval.Allowed() {
+		logger.Info("%s，跳过", eval.Decisive.Message)
+		s.storage.AddSystemMessage("info", i18n.M("scheduler.skipped", eval.Decisive))
 		return
 	}
 
@@ -192,25 +211,27 @@ func (s *Scheduler) executeReset(resetType string) {
 			UseMaxThreshold:    s.useMaxThreshold,
 			CreditThresholdMax: s.creditThresholdMax,
 			CreditThresholdMin: s.creditThresholdMin,
+			Location:           s.location,
 		},
 	)
 
 	results, err := runner.Execute()
 	if err != nil {
 		logger.Error("执行重置失败: %v", err)
-		s.storage.AddSystemLog("error", fmt.Sprintf("执行%s重置失败: %v", resetTypeText, err))
+		s.storage.AddSystemMessage("error", i18n.M("scheduler.run_failed", resetTypeLabel, err))
 		s.recordFailure(status, err.Error(), resetType)
 		return
 	}
 
 	if len(results) == 0 {
 		logger.Warn("未找到需要处理的订阅")
-		s.storage.AddSystemLog("warning", fmt.Sprintf("%s重置: 未找到需要处理的订阅", resetTypeText))
+		s.storage.AddSystemMessage("warning", i18n.M("scheduler.no_subscriptions", resetTypeLabel))
 		s.recordSkip(status, resetType, "无匹配订阅")
 		return
 	}
 
 	reset.LogResults(results)
+	recordResetEvents(s.journal, results, resetType)
 
 	anySuccess := false
 	anyError := false
@@ -220,18 +241,18 @@ func (s *Scheduler) executeReset(resetType string) {
 		if res.Err != nil {
 			anyError = true
 			lastMessage = fmt.Sprintf("[%s] %v", res.Subscription.SubscriptionName, res.Err)
-			s.storage.AddSystemLog("error", fmt.Sprintf("%s重置失败: %s", resetTypeText, lastMessage))
+			s.storage.AddSystemMessage("error", i18n.M("scheduler.reset_failed", resetTypeLabel, res.Subscription.SubscriptionName, res.Err))
 			continue
 		}
 		if res.Skipped {
 			lastMessage = fmt.Sprintf("[%s] 跳过: %s", res.Subscription.SubscriptionName, res.SkipReason)
-			s.storage.AddSystemLog("info", fmt.Sprintf("%s重置跳过: %s", resetTypeText, lastMessage))
+			s.storage.AddSystemMessage("info", i18n.M("scheduler.reset_skipped", resetTypeLabel, res.Subscription.SubscriptionName, res.SkipMessage))
 			continue
 		}
 
 		anySuccess = true
 		lastMessage = fmt.Sprintf("[%s] %s", res.Subscription.SubscriptionName, res.ResetResponse.Message)
-		s.storage.AddSystemLog("success", fmt.Sprintf("%s重置成功: %s", resetTypeText, lastMessage))
+		s.storage.AddSystemMessage("success", i18n.M("scheduler.reset_succeeded", resetTypeLabel, res.Subscription.SubscriptionName, res.ResetResponse.Message))
 		status.ResetTimesBeforeReset = res.BeforeResets
 		status.CreditsBeforeReset = res.BeforeCredits
 		status.ResetTimesAfterReset = res.AfterResets
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// SnapshotFiles 读取数据目录下的全部 JSON 数据文件（不含 API 响应日志、锁文件和临时文件）
+//
+// 返回值以相对于数据目录的路径（使用 / 分隔）为键。
+func (s *Storage) SnapshotFiles() (map[string]json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files := make(map[string]json.RawMessage)
+	err := filepath.Walk(s.dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(s.dataDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel == ResponseLogDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !isSnapshotFile(rel) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("读取 %s 失败: %w", rel, err)
+		}
+		if !json.Valid(data) {
+			logger.Warn("跳过无效的 JSON 数据文件: %s", rel)
+			return nil
+		}
+
+		files[rel] = json.RawMessage(data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取数据目录失败: %w", err)
+	}
+
+	return files, nil
+}
+
+// RestoreFiles 将快照文件写回数据目录（原子写入），不会删除快照中不存在的文件
+func (s *Storage) RestoreFiles(files map[string]json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if !isSnapshotFile(name) {
+			return fmt.Errorf("快照包含不允许的文件: %s", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		target := filepath.Join(s.dataDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("创建目录失败: %w", err)
+		}
+
+		var data interface{}
+		if err := json.Unmarshal(files[name], &data); err != nil {
+			return fmt.Errorf("快照文件 %s 不是有效的 JSON: %w", name, err)
+		}
+		if err := s.saveJSON(target, data); err != nil {
+			return fmt.Errorf("写入 %s 失败: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// ImportSystemLogs 合并导入系统日志（按时间、类型、内容和 Token 去重），返回新增数量
+func (s *Storage) ImportSystemLogs(entries []models.SystemLog) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs, err := s.loadSystemLogsUnsafe()
+	if err != nil {
+		logs = &models.SystemLogs{Logs: []models.SystemLog{}}
+	}
+
+	key := func(l models.SystemLog) string {
+		return l.Timestamp.UTC().Format("2006-01-02T15:04:05.000000000") + "|" + l.Type + "|" + l.TokenID + "|" + l.Message
+	}
+	seen := make(map[string]struct{}, len(logs.Logs))
+	for _, l := range logs.Logs {
+		seen[key(l)] = struct{}{}
+	}
+
+	added := 0
+	for _, entry := range entries {
+		k := key(entry)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		logs.Logs = append(logs.Logs, entry)
+		added++
+	}
+
+	// 保持时间倒序并应用保留上限
+	sort.SliceStable(logs.Logs, func(i, j int) bool {
+		return logs.Logs[i].Timestamp.After(logs.Logs[j].Timestamp)
+	})
+	if len(logs.Logs) > s.maxSystemLogs && s.maxSystemLogs > 0 {
+		logs.Logs = logs.Logs[:s.maxSystemLogs]
+	}
+
+	filePath := filepath.Join(s.dataDir, SystemLogsFile)
+	if err := s.saveJSON(filePath, logs); err != nil {
+		return 0, err
+	}
+	return added, nil
+}
+
+// isSnapshotFile 判断相对路径是否为可备份的数据文件
+func isSnapshotFile(rel string) bool {
+	if rel == "" || strings.HasPrefix(rel, "/") || strings.Contains(rel, "..") || strings.Contains(rel, "\\") {
+		return false
+	}
+	if strings.HasPrefix(rel, ResponseLogDir+"/") || rel == LockFile {
+		return false
+	}
+	return strings.HasSuffix(rel, ".json")
+}
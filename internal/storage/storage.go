@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/pkg/logger"
 )
@@ -16,17 +17,18 @@ const (
 	AccountFile          = "account.json"
 	StatusFile           = "status.json"
 	LockFile             = "reset.lock"
-	ResponseLogDir       = "responses"          // API响应体保存目录
-	MultiAccountFile     = "accounts.json"      // 多账号配置文件
-	AccountsDir          = "accounts"           // 多账号数据目录
-	SystemLogsFile       = "system_logs.json"   // 系统日志文件
-	MaxSystemLogs        = 500                  // 最大系统日志数量
+	ResponseLogDir       = "responses"        // API响应体保存目录
+	MultiAccountFile     = "accounts.json"    // 多账号配置文件
+	AccountsDir          = "accounts"         // 多账号数据目录
+	SystemLogsFile       = "system_logs.json" // 系统日志文件
+	DefaultMaxSystemLogs = 500                // 默认系统日志保留数量
 )
 
 // Storage 存储管理器
 type Storage struct {
-	dataDir string
-	mu      sync.RWMutex
+	dataDir       string
+	mu            sync.RWMutex
+	maxSystemLogs int // 系统日志保留数量
 }
 
 // NewStorage 创建新的存储管理器
@@ -37,7 +39,8 @@ func NewStorage(dataDir string) (*Storage, error) {
 	}
 
 	return &Storage{
-		dataDir: dataDir,
+		dataDir:       dataDir,
+		maxSystemLogs: DefaultMaxSystemLogs,
 	}, nil
 }
 
@@ -423,8 +426,34 @@ func (s *Storage) SaveAPIResponse(endpoint, method string, requestBody, response
 	return nil
 }
 
+// SetSystemLogRetention 设置系统日志保留数量，<=0 时恢复默认值
+func (s *Storage) SetSystemLogRetention(max int) {
+	if max <= 0 {
+		max = DefaultMaxSystemLogs
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxSystemLogs = max
+}
+
 // AddSystemLog 添加系统日志
 func (s *Storage) AddSystemLog(logType, message string) error {
+	return s.AddSystemLogEntry(models.SystemLog{
+		Type:    logType,
+		Message: message,
+	})
+}
+
+// AddSystemMessage 添加带消息码的系统日志，可按界面语言重新渲染
+func (s *Storage) AddSystemMessage(logType string, msg i18n.Message) error {
+	entry := models.SystemLog{Type: logType}
+	entry.SetMessage(msg)
+	return s.AddSystemLogEntry(entry)
+}
+
+// AddSystemLogEntry 添加带结构化字段（Token ID、批次 ID 等）的系统日志
+func (s *Storage) AddSystemLogEntry(entry models.SystemLog) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -433,19 +462,20 @@ func (s *Storage) AddSystemLog(logType, message string) error {
 		logs = &models.SystemLogs{Logs: []models.SystemLog{}}
 	}
 
-	// 添加新日志
-	newLog := models.SystemLog{
-		Timestamp: time.Now(),
-		Type:      logType,
-		Message:   message,
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
 	}
 
 	// 添加到列表开头
-	logs.Logs = append([]models.SystemLog{newLog}, logs.Logs...)
+	logs.Logs = append([]models.SystemLog{entry}, logs.Logs...)
 
 	// 限制日志数量
-	if len(logs.Logs) > MaxSystemLogs {
-		logs.Logs = logs.Logs[:MaxSystemLogs]
+	max := s.maxSystemLogs
+	if max <= 0 {
+		max = DefaultMaxSystemLogs
+	}
+	if len(logs.Logs) > max {
+		logs.Logs = logs.Logs[:max]
 	}
 
 	// 保存
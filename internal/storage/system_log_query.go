@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// SystemLogQuery 系统日志查询条件，零值字段表示不过滤
+type SystemLogQuery struct {
+	Types   []string   // 日志类型（info/success/warning/error）
+	TokenID string     // 关联的 Token ID
+	RunID   string     // 关联的执行批次 ID
+	Since   *time.Time // 起始时间（含）
+	Until   *time.Time // 结束时间（不含）
+	Search  string     // 全文搜索（不区分大小写）
+	Offset  int        // 分页偏移
+	Limit   int        // 分页大小，0 表示不限制
+}
+
+// SystemLogPage 系统日志分页结果
+type SystemLogPage struct {
+	Logs   []models.SystemLog `json:"logs"`
+	Total  int                `json:"total"`
+	Offset int                `json:"offset"`
+	Limit  int                `json:"limit"`
+}
+
+// IsEmpty 判断查询是否不含任何过滤条件（忽略分页参数）
+func (q SystemLogQuery) IsEmpty() bool {
+	return len(q.Types) == 0 && q.TokenID == "" && q.RunID == "" &&
+		q.Since == nil && q.Until == nil && strings.TrimSpace(q.Search) == ""
+}
+
+// Matches 判断日志是否满足查询条件
+func (q SystemLogQuery) Matches(log models.SystemLog) bool {
+	if len(q.Types) > 0 {
+		matched := false
+		for _, t := range q.Types {
+			if strings.EqualFold(t, log.Type) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if q.TokenID != "" && log.TokenID != q.TokenID {
+		return false
+	}
+	if q.RunID != "" && log.RunID != q.RunID {
+		return false
+	}
+	if q.Since != nil && log.Timestamp.Before(*q.Since) {
+		return false
+	}
+	if q.Until != nil && !log.Timestamp.Before(*q.Until) {
+		return false
+	}
+
+	if search := strings.ToLower(strings.TrimSpace(q.Search)); search != "" {
+		haystack := strings.ToLower(log.Message + " " + log.TokenID + " " + log.RunID)
+		if !strings.Contains(haystack, search) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// QuerySystemLogs 按条件分页查询系统日志（按时间倒序）
+func (s *Storage) QuerySystemLogs(q SystemLogQuery) (*SystemLogPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	logs, err := s.loadSystemLogsUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]models.SystemLog, 0, len(logs.Logs))
+	for _, log := range logs.Logs {
+		if q.Matches(log) {
+			matched = append(matched, log)
+		}
+	}
+
+	page := &SystemLogPage{
+		Total:  len(matched),
+		Offset: q.Offset,
+		Limit:  q.Limit,
+	}
+
+	start := q.Offset
+	if start < 0 {
+		start = 0
+	}
+	if start > len(matched) {
+		start = len(matched)
+	}
+	end := len(matched)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+	page.Logs = matched[start:end]
+
+	return page, nil
+}
+
+// DeleteSystemLogs 删除满足条件的系统日志，返回删除数量
+func (s *Storage) DeleteSystemLogs(q SystemLogQuery) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs, err := s.loadSystemLogsUnsafe()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]models.SystemLog, 0, len(logs.Logs))
+	for _, log := range logs.Logs {
+		if !q.Matches(log) {
+			kept = append(kept, log)
+		}
+	}
+
+	removed := len(logs.Logs) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+
+	logs.Logs = kept
+	filePath := filepath.Join(s.dataDir, SystemLogsFile)
+	if err := s.saveJSON(filePath, logs); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func newTestStorage(t *testing.T) *Storage {
+	t.Helper()
+
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	return store
+}
+
+func TestQuerySystemLogs_FiltersAndPaginates(t *testing.T) {
+	store := newTestStorage(t)
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []models.SystemLog{
+		{Timestamp: base, Type: "info", Message: "开始重置", RunID: "run-1"},
+		{Timestamp: base.Add(time.Minute), Type: "error", Message: "重置失败 quota", TokenID: "tok-a", RunID: "run-1"},
+		{Timestamp: base.Add(2 * time.Minute), Type: "success", Message: "重置成功", TokenID: "tok-b", RunID: "run-1"},
+		{Timestamp: base.Add(time.Hour), Type: "error", Message: "连接超时", TokenID: "tok-a", RunID: "run-2"},
+	}
+	for _, e := range entries {
+		if err := store.AddSystemLogEntry(e); err != nil {
+			t.Fatalf("AddSystemLogEntry() error = %v", err)
+		}
+	}
+
+	page, err := store.QuerySystemLogs(SystemLogQuery{TokenID: "tok-a"})
+	if err != nil {
+		t.Fatalf("QuerySystemLogs() error = %v", err)
+	}
+	if page.Total != 2 {
+		t.Fatalf("expected 2 logs for tok-a, got %d", page.Total)
+	}
+	if page.Logs[0].RunID != "run-2" {
+		t.Fatalf("expected newest log first, got run %q", page.Logs[0].RunID)
+	}
+
+	until := base.Add(30 * time.Minute)
+	page, err = store.QuerySystemLogs(SystemLogQuery{Types: []string{"ERROR"}, Until: &until})
+	if err != nil {
+		t.Fatalf("QuerySystemLogs() error = %v", err)
+	}
+	if page.Total != 1 || page.Logs[0].Message != "重置失败 quota" {
+		t.Fatalf("unexpected type/time filter result: %+v", page.Logs)
+	}
+
+	page, err = store.QuerySystemLogs(SystemLogQuery{Search: "QUOTA"})
+	if err != nil {
+		t.Fatalf("QuerySystemLogs() error = %v", err)
+	}
+	if page.Total != 1 {
+		t.Fatalf("expected search to match 1 log, got %d", page.Total)
+	}
+
+	page, err = store.QuerySystemLogs(SystemLogQuery{RunID: "run-1", Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatalf("QuerySystemLogs() error = %v", err)
+	}
+	if page.Total != 3 || len(page.Logs) != 1 || page.Logs[0].TokenID != "tok-a" {
+		t.Fatalf("unexpected page: total=%d logs=%+v", page.Total, page.Logs)
+	}
+}
+
+func TestAddSystemLogEntry_RespectsRetention(t *testing.T) {
+	store := newTestStorage(t)
+	store.SetSystemLogRetention(3)
+
+	for i := 0; i < 5; i++ {
+		if err := store.AddSystemLog("info", "log"); err != nil {
+			t.Fatalf("AddSystemLog() error = %v", err)
+		}
+	}
+
+	logs, err := store.LoadSystemLogs()
+	if err != nil {
+		t.Fatalf("LoadSystemLogs() error = %v", err)
+	}
+	if len(logs.Logs) != 3 {
+		t.Fatalf("expected retention cap of 3, got %d", len(logs.Logs))
+	}
+}
+
+func TestDeleteSystemLogs_RemovesOnlyMatching(t *testing.T) {
+	store := newTestStorage(t)
+	store.AddSystemLogEntry(models.SystemLog{Type: "info", Message: "keep"})
+	store.AddSystemLogEntry(models.SystemLog{Type: "error", Message: "drop", TokenID: "tok-a"})
+
+	removed, err := store.DeleteSystemLogs(SystemLogQuery{TokenID: "tok-a"})
+	if err != nil {
+		t.Fatalf("DeleteSystemLogs() error = %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 removed, got %d", removed)
+	}
+
+	logs, _ := store.LoadSystemLogs()
+	if len(logs.Logs) != 1 || logs.Logs[0].Message != "keep" {
+		t.Fatalf("unexpected remaining logs: %+v", logs.Logs)
+	}
+}
@@ -0,0 +1,186 @@
+package token
+
+import (
+	"fmt"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/reset"
+	"code88reset/pkg/logger"
+)
+
+// CreditAlert 一次低额度提醒（或解除）
+type CreditAlert struct {
+	TokenID            string  `json:"token_id"`
+	Name               string  `json:"name"`
+	Percent            float64 `json:"percent"`
+	Threshold          float64 `json:"threshold"`
+	Recovered          bool    `json:"recovered"`                      // true 表示额度已回升、提醒解除
+	ResetEligible      bool    `json:"reset_eligible"`                 // 当前是否可以立即重置
+	ResetBlockedReason string  `json:"reset_blocked_reason,omitempty"` // 不能立即重置的原因（默认语言）
+
+	blocked i18n.Message // 带消息码的不能立即重置的原因
+}
+
+// Message 提醒文案（默认语言）
+func (a CreditAlert) Message() string {
+	return a.CodedMessage().String()
+}
+
+// CodedMessage 带消息码的提醒文案
+func (a CreditAlert) CodedMessage() i18n.Message {
+	percent := fmt.Sprintf("%.2f", a.Percent)
+	if a.Recovered {
+		return i18n.M("token.credit_recovered", a.Name, percent)
+	}
+	threshold := fmt.Sprintf("%.1f", a.Threshold)
+	if !a.ResetEligible && a.ResetBlockedReason != "" {
+		blocked := a.blocked
+		if blocked.Code == "" {
+			blocked = i18n.M(i18n.TextCode, a.ResetBlockedReason)
+		}
+		return i18n.M("token.credit_low_blocked", a.Name, percent, threshold, blocked)
+	}
+	return i18n.M("token.credit_low", a.Name, percent, threshold)
+}
+
+// CheckCredits 根据已缓存的订阅信息检查启用 Token 的剩余额度，返回本次新触发或解除的提醒
+//
+// 额度低于阈值时提醒一次，回升到 阈值+回升幅度 以上才解除，解除后再次低于阈值才会重新提醒。
+func (m *Manager) CheckCredits(cfg models.CreditWatchConfig) []CreditAlert {
+	now := time.Now()
+	alerts := make([]CreditAlert, 0)
+
+	for _, t := range m.ListEnabledTokens() {
+		sub := t.Subscription
+		if sub == nil || sub.CreditLimit <= 0 {
+			continue
+		}
+		if expired, _ := subscriptionExpired(sub, now); expired {
+			continue
+		}
+
+		threshold := cfg.ThresholdPercent
+		if t.LowCreditThreshold != nil {
+			threshold = *t.LowCreditThreshold
+		}
+		if threshold <= 0 {
+			continue
+		}
+
+		active := t.CreditAlert != nil && t.CreditAlert.Active
+		percent := sub.CreditPercent
+		alert := CreditAlert{TokenID: t.ID, Name: t.Name, Percent: percent, Threshold: threshold}
+
+		switch {
+		case !active && percent < threshold:
+			alert.ResetEligible, alert.blocked = canResetNow(t, now, m.location.Load())
+			if !alert.ResetEligible {
+				alert.ResetBlockedReason = alert.blocked.String()
+			}
+		case active && percent >= threshold+cfg.HysteresisPercent:
+			alert.Recovered = true
+		default:
+			continue
+		}
+
+		state := &models.CreditAlertState{
+			Active:    !alert.Recovered,
+			Percent:   percent,
+			Threshold: threshold,
+			ChangedAt: now,
+		}
+		if _, err := m.storage.Mutate(t.ID, func(t *models.Token) { t.CreditAlert = state }); err != nil {
+			logger.Warn("保存低额度提醒状态失败: %v", err)
+			continue
+		}
+
+		logType := "warning"
+		if alert.Recovered {
+			logType = "info"
+		}
+		logger.Info("%s", alert.Message())
+		m.addSystemLog(logType, alert.CodedMessage(), t.ID, "")
+		alerts = append(alerts, alert)
+	}
+
+	return alerts
+}
+
+// TokenSettings Token 的单独设置
+type TokenSettings struct {
+	LowCreditThreshold *float64 // 为 nil 时不修改，<= 0 表示恢复使用全局阈值
+	BaseURL            *string  // 为 nil 时不修改，空字符串表示恢复使用全局地址
+	ProxyURL           *string  // 为 nil 时不修改，空字符串表示恢复使用全局代理
+}
+
+// UpdateTokenSettings 更新 Token 的单独设置（低额度提醒阈值、上游地址、代理）
+//
+// 先在锁外校验，再在存储锁内只修改这几个字段，不会覆盖同时进行的重置、刷新写入的状态。
+func (m *Manager) UpdateTokenSettings(tokenID string, settings TokenSettings) (*models.Token, error) {
+	var threshold *float64
+	if settings.LowCreditThreshold != nil {
+		if *settings.LowCreditThreshold > 100 {
+			return nil, i18n.Errorf("token.invalid_credit_threshold")
+		}
+		if *settings.LowCreditThreshold > 0 {
+			value := *settings.LowCreditThreshold
+			threshold = &value
+		}
+	}
+
+	var baseURL, proxyURL string
+	if settings.BaseURL != nil {
+		baseURL = *settings.BaseURL
+	}
+	if settings.ProxyURL != nil {
+		proxyURL = *settings.ProxyURL
+	}
+	baseURL, proxyURL, err := normalizeUpstream(baseURL, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := m.storage.Mutate(tokenID, func(t *models.Token) {
+		if settings.LowCreditThreshold != nil {
+			t.LowCreditThreshold = threshold
+		}
+		if settings.BaseURL != nil {
+			t.BaseURL = baseURL
+		}
+		if settings.ProxyURL != nil {
+			t.ProxyURL = proxyURL
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	api.DefaultCache.InvalidateAPIKey(token.APIKey)
+	return token, nil
+}
+
+// canResetNow 判断 Token 当前是否可以立即重置：需为定时的第二次重置保留一次 resetTimes，且距上次重置超过 reset.MinResetInterval
+func canResetNow(t *models.Token, now time.Time, loc *time.Location) (bool, i18n.Message) {
+	sub := t.Subscription
+	if sub == nil || sub.ResetTimes < 1 {
+		return false, i18n.M("token.reset_times_exhausted")
+	}
+	if sub.ResetTimes < reset.MinRequiredResetTimes(reset.ResetTypeLowCredit) {
+		return false, i18n.M("token.reset_times_reserved")
+	}
+
+	last, _ := reset.ParseLastCreditReset(sub.LastCreditReset, loc)
+	if t.LastReset != nil && t.LastReset.Success && t.LastReset.ResetAt.After(last) {
+		last = t.LastReset.ResetAt
+	}
+
+	if !last.IsZero() {
+		if next := last.Add(reset.MinResetInterval); now.Before(next) {
+			return false, i18n.M("token.reset_interval_pending",
+				reset.MinResetInterval, next.Format("2006-01-02 15:04:05"))
+		}
+	}
+	return true, i18n.Message{}
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func lowCreditToken(id string, percent float64, resetTimes int) models.Token {
+	tok := tokenWithEmail(id, "key-"+id, id+"@example.com")
+	tok.Subscription.CreditLimit = 100
+	tok.Subscription.CreditPercent = percent
+	tok.Subscription.ResetTimes = resetTimes
+	return tok
+}
+
+func TestCheckCredits_Hysteresis(t *testing.T) {
+	mgr := newTestManager(t, lowCreditToken("low", 10, 2))
+	cfg := models.CreditWatchConfig{Enabled: true, IntervalMinutes: 10, ThresholdPercent: 20, HysteresisPercent: 10}
+
+	alerts := mgr.CheckCredits(cfg)
+	if len(alerts) != 1 || alerts[0].Recovered || !alerts[0].ResetEligible {
+		t.Fatalf("expected one eligible low-credit alert, got %+v", alerts)
+	}
+	if again := mgr.CheckCredits(cfg); len(again) != 0 {
+		t.Fatalf("expected no repeated alert, got %+v", again)
+	}
+
+	setPercent := func(percent float64) {
+		tok, _ := mgr.GetToken("low")
+		tok.Subscription.CreditPercent = percent
+		if err := mgr.storage.Update(tok); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+
+	// 回升但未超过 阈值+回升幅度，不解除
+	setPercent(25)
+	if alerts := mgr.CheckCredits(cfg); len(alerts) != 0 {
+		t.Fatalf("expected alert to stay active, got %+v", alerts)
+	}
+
+	setPercent(35)
+	if alerts := mgr.CheckCredits(cfg); len(alerts) != 1 || !alerts[0].Recovered {
+		t.Fatalf("expected recovery, got %+v", alerts)
+	}
+
+	setPercent(15)
+	if alerts := mgr.CheckCredits(cfg); len(alerts) != 1 || alerts[0].Recovered {
+		t.Fatalf("expected a new alert after recovery, got %+v", alerts)
+	}
+}
+
+func TestCheckCredits_PerTokenThresholdAndResetWindow(t *testing.T) {
+	recent := time.Now().Add(-2 * time.Hour).Format("2006-01-02 15:04:05")
+	tok := lowCreditToken("custom", 25, 2)
+	tok.Subscription.LastCreditReset = &recent
+	mgr := newTestManager(t, tok)
+
+	threshold := 30.0
+	if _, err := mgr.UpdateTokenSettings("custom", TokenSettings{LowCreditThreshold: &threshold}); err != nil {
+		t.Fatalf("UpdateTokenSettings() error = %v", err)
+	}
+
+	alerts := mgr.CheckCredits(models.CreditWatchConfig{Enabled: true, ThresholdPercent: 20, HysteresisPercent: 10})
+	if len(alerts) != 1 || alerts[0].Threshold != 30 {
+		t.Fatalf("expected alert with per-token threshold, got %+v", alerts)
+	}
+	if alerts[0].ResetEligible || alerts[0].ResetBlockedReason == "" {
+		t.Fatalf("expected reset to be blocked by the 5-hour rule, got %+v", alerts[0])
+	}
+}
+
+func TestCheckCredits_ReservesResetForSecondReset(t *testing.T) {
+	mgr := newTestManager(t, lowCreditToken("last", 10, 1))
+
+	alerts := mgr.CheckCredits(models.CreditWatchConfig{Enabled: true, ThresholdPercent: 20, HysteresisPercent: 10})
+	if len(alerts) != 1 || alerts[0].ResetEligible {
+		t.Fatalf("expected the last resetTimes to be kept for the second reset, got %+v", alerts)
+	}
+}
@@ -0,0 +1,240 @@
+package token
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// DuplicateError 添加的 Token 与已有 Token 属于同一 API Key 或同一 88code 员工
+type DuplicateError struct {
+	Existing   *models.Token
+	Reason     string // 默认语言的重复原因
+	ReasonCode string // api_key / employee_id / employee_email
+}
+
+func (e *DuplicateError) Error() string {
+	return e.Localize(i18n.Default())
+}
+
+// Localize 按语言渲染错误
+func (e *DuplicateError) Localize(locale i18n.Locale) string {
+	return e.CodedMessage().Localize(locale)
+}
+
+// CodedMessage 带消息码的错误消息
+func (e *DuplicateError) CodedMessage() i18n.Message {
+	return i18n.M("token.duplicate", duplicateReasonLabel(e.ReasonCode), e.Existing.Name)
+}
+
+// DuplicateGroup 一组属于同一账号的 Token
+type DuplicateGroup struct {
+	Reasons []string        `json:"reasons"`
+	Tokens  []*models.Token `json:"tokens"`
+}
+
+// FindDuplicates 找出所有重复的 Token（API Key、员工 ID 或员工邮箱相同）
+func (m *Manager) FindDuplicates() []DuplicateGroup {
+	tokens := m.storage.List()
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].AddedAt.Before(tokens[j].AddedAt)
+	})
+
+	// 并查集：任意一种关系相同即归为同一组
+	parent := make([]int, len(tokens))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := make(map[int]map[string]struct{})
+	for i := range tokens {
+		for j := i + 1; j < len(tokens); j++ {
+			reason := duplicateReason(tokens[i], tokens[j])
+			if reason == "" {
+				continue
+			}
+			ri, rj := find(i), find(j)
+			if ri != rj {
+				parent[rj] = ri
+				if set, ok := reasons[rj]; ok {
+					if reasons[ri] == nil {
+						reasons[ri] = make(map[string]struct{})
+					}
+					for r := range set {
+						reasons[ri][r] = struct{}{}
+					}
+					delete(reasons, rj)
+				}
+			}
+			root := find(i)
+			if reasons[root] == nil {
+				reasons[root] = make(map[string]struct{})
+			}
+			reasons[root][reason] = struct{}{}
+		}
+	}
+
+	members := make(map[int][]*models.Token)
+	order := make([]int, 0)
+	for i, t := range tokens {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			order = append(order, root)
+		}
+		members[root] = append(members[root], t)
+	}
+
+	groups := make([]DuplicateGroup, 0)
+	for _, root := range order {
+		if len(members[root]) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Tokens: members[root]}
+		for r := range reasons[root] {
+			group.Reasons = append(group.Reasons, r)
+		}
+		sort.Strings(group.Reasons)
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// MergeDuplicates 将 removeIDs 指定的重复 Token 合并到 keepID 并删除
+//
+// removeIDs 为空时合并与 keepID 同组的全部重复 Token。保留 keepID 的 API Key 与名称，
+// 订阅信息和重置记录取较新的一份，任一 Token 启用则合并结果保持启用。
+func (m *Manager) MergeDuplicates(keepID string, removeIDs []string) (*models.Token, error) {
+	m.addMu.Lock()
+	defer m.addMu.Unlock()
+
+	keep, err := m.storage.Get(keepID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(removeIDs) == 0 {
+		for _, group := range m.FindDuplicates() {
+			for _, t := range group.Tokens {
+				if t.ID == keepID {
+					for _, other := range group.Tokens {
+						if other.ID != keepID {
+							removeIDs = append(removeIDs, other.ID)
+						}
+					}
+				}
+			}
+		}
+		if len(removeIDs) == 0 {
+			return nil, i18n.Errorf("token.no_duplicates", keep.Name)
+		}
+	}
+
+	removed := make([]*models.Token, 0, len(removeIDs))
+	for _, id := range removeIDs {
+		if id == keepID {
+			continue
+		}
+		other, err := m.storage.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if duplicateReason(keep, other) == "" {
+			return nil, i18n.Errorf("token.merge_mismatch", other.Name, keep.Name)
+		}
+		removed = append(removed, other)
+	}
+
+	// 在存储锁内合并，保留期间其他写入对 keep 的修改
+	keep, err = m.storage.Mutate(keepID, func(t *models.Token) {
+		for _, other := range removed {
+			mergeTokenState(t, other)
+			t.Enabled = t.Enabled || other.Enabled
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range removed {
+		if err := m.storage.Delete(other.ID); err != nil {
+			return nil, err
+		}
+		api.DefaultCache.InvalidateAPIKey(other.APIKey)
+	}
+
+	logger.Info("已合并 %d 个重复 Token 到 %s", len(removed), keep.Name)
+	m.addSystemLog("info", i18n.M("token.log.merged", len(removed), keep.Name), keep.ID, "")
+	return keep, nil
+}
+
+// checkDuplicate 检查候选 Token 是否与已有 Token 重复
+func (m *Manager) checkDuplicate(candidate *models.Token) error {
+	for _, existing := range m.storage.List() {
+		if reason := duplicateReason(existing, candidate); reason != "" {
+			return &DuplicateError{
+				Existing:   existing,
+				Reason:     i18n.T(i18n.Default(), string(duplicateReasonLabel(reason))),
+				ReasonCode: reason,
+			}
+		}
+	}
+	return nil
+}
+
+// duplicateReason 判断两个 Token 是否属于同一账号，返回匹配依据
+func duplicateReason(a, b *models.Token) string {
+	if a.APIKey != "" && a.APIKey == b.APIKey {
+		return "api_key"
+	}
+	if a.Subscription == nil || b.Subscription == nil {
+		return ""
+	}
+	if a.Subscription.EmployeeID != 0 && a.Subscription.EmployeeID == b.Subscription.EmployeeID {
+		return "employee_id"
+	}
+	if email := tokenEmail(a); email != "" && strings.EqualFold(email, tokenEmail(b)) {
+		return "employee_email"
+	}
+	return ""
+}
+
+// duplicateReasonLabel 重复原因的文案，消息码为 token.duplicate_reason.<reason>
+func duplicateReasonLabel(reason string) i18n.Label {
+	return i18n.Label("token.duplicate_reason." + reason)
+}
+
+// mergeTokenState 将 src 中较新的订阅信息和重置记录合并到 dst
+func mergeTokenState(dst, src *models.Token) {
+	if !src.AddedAt.IsZero() && (dst.AddedAt.IsZero() || src.AddedAt.Before(dst.AddedAt)) {
+		dst.AddedAt = src.AddedAt
+	}
+
+	if src.Subscription != nil && (dst.Subscription == nil || isNewer(src.SubscriptionUpdatedAt, dst.SubscriptionUpdatedAt)) {
+		dst.Subscription = src.Subscription
+		dst.SubscriptionUpdatedAt = src.SubscriptionUpdatedAt
+	}
+
+	if src.LastReset != nil && (dst.LastReset == nil || src.LastReset.ResetAt.After(dst.LastReset.ResetAt)) {
+		dst.LastReset = src.LastReset
+	}
+}
+
+// isNewer 判断 candidate 时间是否晚于 current（nil 视为最早）
+func isNewer(candidate, current *time.Time) bool {
+	if candidate == nil {
+		return false
+	}
+	return current == nil || candidate.After(*current)
+}
@@ -0,0 +1,87 @@
+package token
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestFindDuplicates_GroupsByKeyIDAndEmail(t *testing.T) {
+	a := tokenWithEmail("a", "key-a", "x@example.com")
+	b := tokenWithEmail("b", "key-b", "X@Example.com")
+	c := tokenWithEmail("c", "key-c", "other@example.com")
+	c.Subscription.EmployeeID = 42
+	d := tokenWithEmail("d", "key-d", "")
+	d.Subscription.EmployeeID = 42
+	e := tokenWithEmail("e", "key-e", "solo@example.com")
+
+	mgr := newTestManager(t, a, b, c, d, e)
+	groups := mgr.FindDuplicates()
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d: %+v", len(groups), groups)
+	}
+	for _, g := range groups {
+		if len(g.Tokens) != 2 {
+			t.Fatalf("expected 2 tokens per group, got %+v", g)
+		}
+	}
+}
+
+func TestCheckDuplicate(t *testing.T) {
+	existing := tokenWithEmail("a", "key-a", "x@example.com")
+	mgr := newTestManager(t, existing)
+
+	var dup *DuplicateError
+	if err := mgr.checkDuplicate(&models.Token{APIKey: "key-a"}); !errors.As(err, &dup) || dup.Existing.ID != "a" {
+		t.Fatalf("expected duplicate by api key, got %v", err)
+	}
+
+	candidate := tokenWithEmail("", "key-new", "x@example.com")
+	if err := mgr.checkDuplicate(&candidate); !errors.As(err, &dup) {
+		t.Fatalf("expected duplicate by email, got %v", err)
+	}
+
+	fresh := tokenWithEmail("", "key-new", "y@example.com")
+	if err := mgr.checkDuplicate(&fresh); err != nil {
+		t.Fatalf("expected no duplicate, got %v", err)
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	older := time.Now().Add(-2 * time.Hour)
+	newer := time.Now().Add(-time.Hour)
+
+	keep := tokenWithEmail("keep", "key-keep", "x@example.com")
+	keep.Enabled = false
+	keep.AddedAt = newer
+	keep.SubscriptionUpdatedAt = &older
+
+	dup := tokenWithEmail("dup", "key-dup", "x@example.com")
+	dup.AddedAt = older
+	dup.SubscriptionUpdatedAt = &newer
+	dup.Subscription.CurrentCredits = 7
+
+	other := tokenWithEmail("other", "key-other", "y@example.com")
+
+	mgr := newTestManager(t, keep, dup, other)
+
+	if _, err := mgr.MergeDuplicates("keep", []string{"other"}); err == nil {
+		t.Fatal("expected error when merging unrelated tokens")
+	}
+
+	merged, err := mgr.MergeDuplicates("keep", nil)
+	if err != nil {
+		t.Fatalf("MergeDuplicates() error = %v", err)
+	}
+	if merged.APIKey != "key-keep" || !merged.Enabled || merged.Subscription.CurrentCredits != 7 || !merged.AddedAt.Equal(older) {
+		t.Fatalf("unexpected merged token: %+v", merged)
+	}
+	if _, err := mgr.GetToken("dup"); err == nil {
+		t.Fatal("expected duplicate token to be removed")
+	}
+	if _, err := mgr.GetToken("other"); err != nil {
+		t.Fatalf("unrelated token should remain: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package token
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// ErrSubscriptionExpired 订阅已到期，不再执行重置
+var ErrSubscriptionExpired = i18n.Errorf("token.subscription_expired")
+
+// ExpiringToken 即将到期（或已到期）的 Token
+type ExpiringToken struct {
+	TokenID       string `json:"token_id"`
+	Name          string `json:"name"`
+	EmployeeEmail string `json:"employee_email,omitempty"`
+	EndDate       string `json:"end_date"`
+	RemainingDays int    `json:"remaining_days"`
+	Expired       bool   `json:"expired"`
+}
+
+// ExpiryAlert 一次到期提醒
+type ExpiryAlert struct {
+	ExpiringToken
+	LeadDays int `json:"lead_days"` // 触发提醒的提前天数，已到期时为 0
+}
+
+// Message 提醒文案（默认语言）
+func (a ExpiryAlert) Message() string {
+	return a.CodedMessage().String()
+}
+
+// CodedMessage 带消息码的提醒文案
+func (a ExpiryAlert) CodedMessage() i18n.Message {
+	if a.Expired {
+		return i18n.M("token.subscription_expired_alert", a.Name, a.EndDate)
+	}
+	return i18n.M("token.subscription_expiring", a.Name, a.RemainingDays, a.EndDate)
+}
+
+// ExpiringWindowDays 状态页展示即将到期订阅的天数：取提醒天数中的最大值，至少 7 天
+func ExpiringWindowDays(cfg models.ExpiryAlertConfig) int {
+	withinDays := 7
+	for _, d := range cfg.LeadDays {
+		if d > withinDays {
+			withinDays = d
+		}
+	}
+	return withinDays
+}
+
+// ExpiringTokens 列出 withinDays 天内到期及已到期的启用 Token，按剩余天数升序
+func (m *Manager) ExpiringTokens(withinDays int) []ExpiringToken {
+	now := time.Now()
+	result := make([]ExpiringToken, 0)
+	for _, t := range m.ListEnabledTokens() {
+		info, ok := expiryInfo(t, now)
+		if ok && info.RemainingDays <= withinDays {
+			result = append(result, info)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RemainingDays < result[j].RemainingDays
+	})
+	return result
+}
+
+// CheckExpiry 检查启用 Token 的订阅到期情况，返回本次新触发的提醒
+//
+// 每个提前天数只提醒一次；剩余天数同时低于多个提前天数时只发送最近的一次。
+// 订阅到期日变化（续费）后重新开始计算。
+func (m *Manager) CheckExpiry(leadDays []int) []ExpiryAlert {
+	now := time.Now()
+	alerts := make([]ExpiryAlert, 0)
+
+	for _, t := range m.ListEnabledTokens() {
+		info, ok := expiryInfo(t, now)
+		if !ok {
+			continue
+		}
+
+		// 在副本上修改，存储中的 Token 与列表副本共享该指针
+		state := &models.ExpiryAlertState{EndDate: info.EndDate}
+		if t.ExpiryAlert != nil && t.ExpiryAlert.EndDate == info.EndDate {
+			state.NotifiedDays = append([]int(nil), t.ExpiryAlert.NotifiedDays...)
+		}
+
+		// 找出已跨过的提前天数，取最小的作为本次提醒
+		lead := -1
+		var crossed []int
+		if info.Expired {
+			lead = 0
+			crossed = append(crossed, 0)
+		}
+		for _, d := range leadDays {
+			if info.RemainingDays <= d {
+				crossed = append(crossed, d)
+				if lead < 0 || d < lead {
+					lead = d
+				}
+			}
+		}
+		if lead < 0 || containsInt(state.NotifiedDays, lead) {
+			continue
+		}
+
+		for _, d := range crossed {
+			if !containsInt(state.NotifiedDays, d) {
+				state.NotifiedDays = append(state.NotifiedDays, d)
+			}
+		}
+		_, err := m.storage.Mutate(t.ID, func(t *models.Token) {
+			t.ExpiryAlert = state
+			if info.Expired {
+				m.markHealthy(t)
+			}
+		})
+		if err != nil {
+			logger.Warn("保存到期提醒状态失败: %v", err)
+			continue
+		}
+
+		alert := ExpiryAlert{ExpiringToken: info, LeadDays: lead}
+		logger.Warn("%s", alert.Message())
+		m.addSystemLog("warning", alert.CodedMessage(), t.ID, "")
+		alerts = append(alerts, alert)
+	}
+
+	return alerts
+}
+
+// ListResettableTokens 获取启用的 Token，并按订阅是否已到期分为两组
+func (m *Manager) ListResettableTokens() (active, expired []*models.Token) {
+	now := time.Now()
+	for _, t := range m.ListEnabledTokens() {
+		if ok, _ := subscriptionExpired(t.Subscription, now); ok {
+			expired = append(expired, t)
+		} else {
+			active = append(active, t)
+		}
+	}
+	return active, expired
+}
+
+// expiryInfo 计算 Token 订阅的到期信息，无法确定到期时间时返回 false
+func expiryInfo(t *models.Token, now time.Time) (ExpiringToken, bool) {
+	sub := t.Subscription
+	if sub == nil {
+		return ExpiringToken{}, false
+	}
+
+	info := ExpiringToken{
+		TokenID:       t.ID,
+		Name:          t.Name,
+		EmployeeEmail: sub.EmployeeEmail,
+		EndDate:       sub.EndDate,
+	}
+
+	if expired, _ := subscriptionExpired(sub, now); expired {
+		info.Expired = true
+		return info, true
+	}
+
+	if end, ok := parseSubscriptionTime(sub.EndDate); ok {
+		info.RemainingDays = int(math.Ceil(end.Sub(now).Hours() / 24))
+		return info, true
+	}
+	if sub.RemainingDays > 0 {
+		info.RemainingDays = sub.RemainingDays
+		return info, true
+	}
+	return ExpiringToken{}, false
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
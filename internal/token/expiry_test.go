@@ -0,0 +1,55 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckExpiry_AlertsOncePerLeadDay(t *testing.T) {
+	soon := tokenWithEmail("soon", "key-1", "a@example.com")
+	soon.Subscription.EndDate = time.Now().Add(50 * time.Hour).Format("2006-01-02 15:04:05")
+	later := tokenWithEmail("later", "key-2", "b@example.com")
+	later.Subscription.EndDate = time.Now().Add(30 * 24 * time.Hour).Format("2006-01-02 15:04:05")
+	gone := tokenWithEmail("gone", "key-3", "c@example.com")
+	gone.Subscription.EndDate = time.Now().Add(-time.Hour).Format("2006-01-02 15:04:05")
+
+	mgr := newTestManager(t, soon, later, gone)
+
+	alerts := mgr.CheckExpiry([]int{7, 3, 1})
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 alerts, got %+v", alerts)
+	}
+	for _, a := range alerts {
+		switch a.TokenID {
+		case "soon":
+			if a.LeadDays != 3 || a.RemainingDays != 3 {
+				t.Fatalf("unexpected alert for soon: %+v", a)
+			}
+		case "gone":
+			if !a.Expired || a.LeadDays != 0 {
+				t.Fatalf("unexpected alert for gone: %+v", a)
+			}
+		default:
+			t.Fatalf("unexpected alert: %+v", a)
+		}
+	}
+
+	if again := mgr.CheckExpiry([]int{7, 3, 1}); len(again) != 0 {
+		t.Fatalf("expected no repeated alerts, got %+v", again)
+	}
+
+	active, expired := mgr.ListResettableTokens()
+	if len(active) != 2 || len(expired) != 1 || expired[0].ID != "gone" {
+		t.Fatalf("unexpected split: active=%d expired=%d", len(active), len(expired))
+	}
+
+	// 续费后到期日变化，重新提醒
+	renewed, _ := mgr.GetToken("soon")
+	renewed.Subscription.EndDate = time.Now().Add(20 * time.Hour).Format("2006-01-02 15:04:05")
+	if err := mgr.storage.Update(renewed); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if again := mgr.CheckExpiry([]int{7, 3, 1}); len(again) != 1 || again[0].LeadDays != 1 {
+		t.Fatalf("expected a new alert after end date change, got %+v", again)
+	}
+}
@@ -0,0 +1,165 @@
+package token
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// ErrNoEligibleSubscription API Key 下没有可重置的订阅（需要 MONTHLY 类型且非 PAYGO）
+var ErrNoEligibleSubscription = i18n.Errorf("token.no_eligible_subscription")
+
+// SetAutoDisableAuthFailures 设置连续鉴权失败多少次后自动禁用 Token，0 表示不自动禁用
+func (m *Manager) SetAutoDisableAuthFailures(n int) {
+	if n < 0 {
+		n = 0
+	}
+	m.autoDisableAfter.Store(int32(n))
+}
+
+// classifyFailure 根据错误类型推导失败后的健康状态
+func classifyFailure(err error) string {
+	switch {
+	case api.IsAuthError(err):
+		return models.HealthAuthFailed
+	case errors.Is(err, ErrNoEligibleSubscription):
+		return models.HealthNoEligibleSubscription
+	default:
+		return models.HealthDegraded
+	}
+}
+
+// markHealthy 记录一次成功的操作，订阅已到期时进入 subscription_expired 状态
+func (m *Manager) markHealthy(token *models.Token) {
+	now := time.Now()
+	health := tokenHealth(token)
+	health.ConsecutiveFailures = 0
+	health.ConsecutiveAuthFailures = 0
+	health.LastSuccessAt = &now
+
+	state, reason := models.HealthHealthy, i18n.Message{}
+	if expired, endDate := subscriptionExpired(token.Subscription, now); expired {
+		state, reason = models.HealthSubscriptionExpired, i18n.M("token.subscription_ended", endDate)
+	}
+
+	token.KeyInvalid = false
+	m.transitionHealth(token, health, state, reason, now)
+}
+
+// markFailed 记录一次失败的操作，连续鉴权失败达到阈值时自动禁用 Token
+func (m *Manager) markFailed(token *models.Token, err error) {
+	now := time.Now()
+	state := classifyFailure(err)
+
+	health := tokenHealth(token)
+	health.ConsecutiveFailures++
+	health.LastFailureAt = &now
+	if state == models.HealthAuthFailed {
+		health.ConsecutiveAuthFailures++
+	} else {
+		health.ConsecutiveAuthFailures = 0
+	}
+
+	token.KeyInvalid = state == models.HealthAuthFailed
+	m.transitionHealth(token, health, state, i18n.MessageOf(err), now)
+
+	threshold := int(m.autoDisableAfter.Load())
+	if state == models.HealthAuthFailed && threshold > 0 && token.Enabled && health.ConsecutiveAuthFailures >= threshold {
+		token.Enabled = false
+		health.AutoDisabledAt = &now
+		logger.Warn("Token %s 连续 %d 次鉴权失败，已自动禁用", token.Name, health.ConsecutiveAuthFailures)
+		m.addSystemLog("error", i18n.M("token.auto_disabled",
+			token.Name, health.ConsecutiveAuthFailures, err), token.ID, "")
+	}
+}
+
+// recordFailure 记录失败并保存 Token，update 不为空时同时修改其他字段
+func (m *Manager) recordFailure(tokenID string, err error, update func(token *models.Token)) {
+	_, mutateErr := m.storage.Mutate(tokenID, func(token *models.Token) {
+		if update != nil {
+			update(token)
+		}
+		m.markFailed(token, err)
+	})
+	if mutateErr != nil {
+		logger.Warn("保存 Token 健康状态失败: %v", mutateErr)
+	}
+}
+
+// transitionHealth 更新健康状态，状态变化时记录系统日志，reason 为空消息表示没有原因
+func (m *Manager) transitionHealth(token *models.Token, health *models.TokenHealth, state string, reason i18n.Message, now time.Time) {
+	previous := health.State
+	health.Reason = ""
+	if reason.Code != "" {
+		health.Reason = reason.String()
+	}
+	if previous == state {
+		return
+	}
+
+	health.State = state
+	health.ChangedAt = now
+	if previous == "" {
+		return
+	}
+
+	logType := "warning"
+	if state == models.HealthHealthy {
+		logType = "success"
+	}
+	logger.Info("Token %s 健康状态变化: %s → %s", token.Name, previous, state)
+	message := i18n.M("token.health_changed", token.Name, previous, state)
+	if reason.Code != "" {
+		message = i18n.M("token.health_changed_reason", token.Name, previous, state, reason)
+	}
+	m.addSystemLog(logType, message, token.ID, "")
+}
+
+// tokenHealth 为 Token 换上一份可修改的健康状态副本；存储返回的 Token 副本与缓存共享 Health 指针，
+// 原地修改会与正在读取该 Token 的请求竞争
+func tokenHealth(token *models.Token) *models.TokenHealth {
+	health := &models.TokenHealth{}
+	if token.Health != nil {
+		*health = *token.Health
+	}
+	token.Health = health
+	return health
+}
+
+// subscriptionExpired 判断订阅是否已到期，返回到期日期
+func subscriptionExpired(sub *models.TokenSubscriptionInfo, now time.Time) (bool, string) {
+	if sub == nil {
+		return false, ""
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(sub.Status)) {
+	case "EXPIRED", "已过期", "已到期":
+		return true, sub.EndDate
+	}
+
+	if end, ok := parseSubscriptionTime(sub.EndDate); ok {
+		return !now.Before(end), sub.EndDate
+	}
+	return false, ""
+}
+
+// parseSubscriptionTime 解析 88code 返回的日期时间字符串
+func parseSubscriptionTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	layouts := []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
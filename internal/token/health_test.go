@@ -0,0 +1,92 @@
+package token
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/models"
+)
+
+func TestMarkFailed_AutoDisablesAfterAuthFailures(t *testing.T) {
+	mgr := newTestManager(t)
+	mgr.SetAutoDisableAuthFailures(2)
+
+	token := tokenWithEmail("t1", "key", "a@example.com")
+	authErr := &api.APIError{StatusCode: 401, Message: "unauthorized"}
+
+	mgr.markFailed(&token, authErr)
+	if !token.Enabled || token.Health.State != models.HealthAuthFailed || !token.KeyInvalid {
+		t.Fatalf("unexpected state after first failure: %+v", token.Health)
+	}
+
+	mgr.markFailed(&token, errors.New("timeout"))
+	if token.Health.State != models.HealthDegraded || token.Health.ConsecutiveAuthFailures != 0 {
+		t.Fatalf("expected degraded with auth counter reset, got %+v", token.Health)
+	}
+
+	mgr.markFailed(&token, authErr)
+	mgr.markFailed(&token, authErr)
+	if token.Enabled || token.Health.AutoDisabledAt == nil {
+		t.Fatalf("expected token auto-disabled, got %+v", token.Health)
+	}
+	if token.Health.ConsecutiveFailures != 4 {
+		t.Fatalf("expected 4 consecutive failures, got %d", token.Health.ConsecutiveFailures)
+	}
+
+	mgr.markHealthy(&token)
+	if token.Health.State != models.HealthHealthy || token.Health.ConsecutiveFailures != 0 || token.KeyInvalid {
+		t.Fatalf("expected healthy after success, got %+v", token.Health)
+	}
+}
+
+func TestMarkHealthy_ExpiredSubscription(t *testing.T) {
+	mgr := newTestManager(t)
+	token := tokenWithEmail("t1", "key", "a@example.com")
+	token.Subscription.EndDate = time.Now().Add(-time.Hour).Format("2006-01-02 15:04:05")
+
+	mgr.markHealthy(&token)
+	if token.Health.State != models.HealthSubscriptionExpired {
+		t.Fatalf("expected subscription_expired, got %+v", token.Health)
+	}
+}
+
+// TestRecordFailure_DoesNotShareHealth 记录失败时不修改已返回给调用方的 Token，配合 -race 运行
+func TestRecordFailure_DoesNotShareHealth(t *testing.T) {
+	mgr := newTestManager(t, tokenWithEmail("t1", "key", "a@example.com"))
+	mgr.recordFailure("t1", errors.New("timeout"), nil)
+
+	listed := mgr.ListTokens()
+	before := *listed[0].Health
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			mgr.recordFailure("t1", errors.New("timeout"), nil)
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		if _, err := json.Marshal(mgr.ListTokens()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	if listed[0].Health.ConsecutiveFailures != before.ConsecutiveFailures {
+		t.Fatalf("listed token health changed: %d -> %d", before.ConsecutiveFailures, listed[0].Health.ConsecutiveFailures)
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	if got := classifyFailure(ErrNoEligibleSubscription); got != models.HealthNoEligibleSubscription {
+		t.Fatalf("classifyFailure(no eligible) = %s", got)
+	}
+	if got := classifyFailure(&api.APIError{Code: 403}); got != models.HealthAuthFailed {
+		t.Fatalf("classifyFailure(403) = %s", got)
+	}
+}
@@ -0,0 +1,209 @@
+package token
+
+import (
+	"strings"
+
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// ConflictMode 导入时与已有 Token 冲突的处理方式
+type ConflictMode string
+
+const (
+	ConflictSkip      ConflictMode = "skip"      // 保留已有 Token，跳过导入项
+	ConflictOverwrite ConflictMode = "overwrite" // 用导入项覆盖已有 Token（保留原 ID）
+	ConflictMerge     ConflictMode = "merge"     // 按员工 ID/邮箱合并，保留较新的订阅与重置记录
+)
+
+// ParseConflictMode 解析冲突处理方式，空字符串默认为 skip
+func ParseConflictMode(value string) (ConflictMode, error) {
+	switch ConflictMode(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ConflictSkip:
+		return ConflictSkip, nil
+	case ConflictOverwrite:
+		return ConflictOverwrite, nil
+	case ConflictMerge:
+		return ConflictMerge, nil
+	default:
+		return "", i18n.Errorf("token.unknown_conflict_mode", value)
+	}
+}
+
+// ImportItem 单个 Token 的导入结果
+type ImportItem struct {
+	SourceID string `json:"source_id"`
+	TokenID  string `json:"token_id,omitempty"`
+	Name     string `json:"name"`
+	Action   string `json:"action"` // added, skipped, overwritten, merged, failed
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ImportReport Token 导入汇总
+type ImportReport struct {
+	Added       int          `json:"added"`
+	Skipped     int          `json:"skipped"`
+	Overwritten int          `json:"overwritten"`
+	Merged      int          `json:"merged"`
+	Failed      int          `json:"failed"`
+	Items       []ImportItem `json:"items"`
+	// IDMap 记录导入项 ID 到最终 Token ID 的映射（跳过的项不包含在内）
+	IDMap map[string]string `json:"-"`
+}
+
+// ImportTokens 导入 Token 列表，按 mode 处理与已有 Token 的冲突
+//
+// 冲突判定：ID 或 API Key 相同；merge 模式下员工 ID 或员工邮箱相同也视为同一账号。
+func (m *Manager) ImportTokens(tokens []models.Token, mode ConflictMode) (*ImportReport, error) {
+	if _, err := ParseConflictMode(string(mode)); err != nil {
+		return nil, err
+	}
+
+	// 整个导入期间持有添加锁，避免与并发的添加/合并产生重复 Token
+	m.addMu.Lock()
+	defer m.addMu.Unlock()
+
+	report := &ImportReport{
+		Items: make([]ImportItem, 0, len(tokens)),
+		IDMap: make(map[string]string, len(tokens)),
+	}
+
+	for i := range tokens {
+		imported := tokens[i]
+		item := ImportItem{SourceID: imported.ID, Name: imported.Name}
+
+		if strings.TrimSpace(imported.APIKey) == "" {
+			item.Action = "failed"
+			item.Reason = i18n.T(i18n.Default(), "token.api_key_required")
+			report.Failed++
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		existing, reason := m.findImportConflict(&imported, mode)
+
+		switch {
+		case existing == nil:
+			if imported.ID == "" {
+				imported.ID = uuid.New().String()
+			}
+			if err := m.storage.Add(&imported); err != nil {
+				item.Action = "failed"
+				item.Reason = err.Error()
+				report.Failed++
+				break
+			}
+			item.Action = "added"
+			item.TokenID = imported.ID
+			report.Added++
+
+		case mode == ConflictSkip:
+			item.Action = "skipped"
+			item.TokenID = existing.ID
+			item.Reason = reason
+			report.Skipped++
+
+		case mode == ConflictOverwrite:
+			imported.ID = existing.ID
+			if err := m.storage.Update(&imported); err != nil {
+				item.Action = "failed"
+				item.Reason = err.Error()
+				report.Failed++
+				break
+			}
+			api.DefaultCache.InvalidateAPIKey(existing.APIKey)
+			item.Action = "overwritten"
+			item.TokenID = existing.ID
+			item.Reason = reason
+			report.Overwritten++
+
+		default:
+			merged := mergeTokens(existing, &imported)
+			if err := m.storage.Update(merged); err != nil {
+				item.Action = "failed"
+				item.Reason = err.Error()
+				report.Failed++
+				break
+			}
+			api.DefaultCache.InvalidateAPIKey(existing.APIKey)
+			item.Action = "merged"
+			item.TokenID = existing.ID
+			item.Reason = reason
+			report.Merged++
+		}
+
+		if item.Action != "skipped" && item.Action != "failed" {
+			report.IDMap[item.SourceID] = item.TokenID
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	logger.Info("Token 导入完成 (模式=%s): 新增 %d, 覆盖 %d, 合并 %d, 跳过 %d, 失败 %d",
+		mode, report.Added, report.Overwritten, report.Merged, report.Skipped, report.Failed)
+	return report, nil
+}
+
+// findImportConflict 查找与导入项冲突的已有 Token
+func (m *Manager) findImportConflict(imported *models.Token, mode ConflictMode) (*models.Token, string) {
+	importedEmail := tokenEmail(imported)
+
+	for _, existing := range m.storage.List() {
+		if imported.ID != "" && existing.ID == imported.ID {
+			return existing, importReason("token_id")
+		}
+		if existing.APIKey == imported.APIKey {
+			return existing, importReason("api_key")
+		}
+		if mode != ConflictMerge {
+			continue
+		}
+		if id := tokenEmployeeID(imported); id != 0 && tokenEmployeeID(existing) == id {
+			return existing, importReason("employee_id")
+		}
+		if importedEmail != "" && strings.EqualFold(tokenEmail(existing), importedEmail) {
+			return existing, importReason("employee_email")
+		}
+	}
+
+	return nil, ""
+}
+
+// importReason 导入冲突原因的文案（默认语言）
+func importReason(reason string) string {
+	return i18n.T(i18n.Default(), string(duplicateReasonLabel(reason)))
+}
+
+// mergeTokens 合并两个属于同一账号的 Token，以 base 的 ID 为准
+func mergeTokens(base, incoming *models.Token) *models.Token {
+	merged := *base
+
+	if incoming.APIKey != "" {
+		merged.APIKey = incoming.APIKey
+	}
+	if incoming.Name != "" {
+		merged.Name = incoming.Name
+	}
+	mergeTokenState(&merged, incoming)
+
+	return &merged
+}
+
+// tokenEmail 获取 Token 关联的员工邮箱
+func tokenEmail(t *models.Token) string {
+	if t == nil || t.Subscription == nil {
+		return ""
+	}
+	return strings.TrimSpace(t.Subscription.EmployeeEmail)
+}
+
+// tokenEmployeeID 获取 Token 关联的员工 ID
+func tokenEmployeeID(t *models.Token) int {
+	if t == nil || t.Subscription == nil {
+		return 0
+	}
+	return t.Subscription.EmployeeID
+}
@@ -0,0 +1,102 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func newTestManager(t *testing.T, tokens ...models.Token) *Manager {
+	t.Helper()
+
+	store, err := NewStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStorage() error = %v", err)
+	}
+	for i := range tokens {
+		if err := store.Add(&tokens[i]); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	return NewManager(store, "http://127.0.0.1:0", nil)
+}
+
+func tokenWithEmail(id, apiKey, email string) models.Token {
+	return models.Token{
+		ID:      id,
+		Name:    id,
+		APIKey:  apiKey,
+		Enabled: true,
+		Subscription: &models.TokenSubscriptionInfo{
+			EmployeeEmail: email,
+		},
+	}
+}
+
+func TestImportTokens_ConflictModes(t *testing.T) {
+	existing := tokenWithEmail("t1", "key-1", "a@example.com")
+
+	t.Run("skip keeps existing", func(t *testing.T) {
+		mgr := newTestManager(t, existing)
+		incoming := tokenWithEmail("other", "key-1", "a@example.com")
+		incoming.Name = "renamed"
+
+		report, err := mgr.ImportTokens([]models.Token{incoming, tokenWithEmail("t2", "key-2", "b@example.com")}, ConflictSkip)
+		if err != nil {
+			t.Fatalf("ImportTokens() error = %v", err)
+		}
+		if report.Skipped != 1 || report.Added != 1 {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		got, _ := mgr.GetToken("t1")
+		if got.Name != "t1" {
+			t.Fatalf("expected existing token untouched, got name %q", got.Name)
+		}
+	})
+
+	t.Run("overwrite keeps id", func(t *testing.T) {
+		mgr := newTestManager(t, existing)
+		incoming := tokenWithEmail("t1", "key-1b", "a@example.com")
+		incoming.Name = "overwritten"
+
+		report, err := mgr.ImportTokens([]models.Token{incoming}, ConflictOverwrite)
+		if err != nil {
+			t.Fatalf("ImportTokens() error = %v", err)
+		}
+		if report.Overwritten != 1 || report.IDMap["t1"] != "t1" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		got, _ := mgr.GetToken("t1")
+		if got.Name != "overwritten" || got.APIKey != "key-1b" {
+			t.Fatalf("expected overwritten token, got %+v", got)
+		}
+	})
+
+	t.Run("merge matches by email", func(t *testing.T) {
+		older := time.Now().Add(-time.Hour)
+		base := existing
+		base.SubscriptionUpdatedAt = &older
+		mgr := newTestManager(t, base)
+
+		newer := time.Now()
+		incoming := tokenWithEmail("imported", "key-new", "A@Example.com")
+		incoming.Subscription.CurrentCredits = 42
+		incoming.SubscriptionUpdatedAt = &newer
+
+		report, err := mgr.ImportTokens([]models.Token{incoming}, ConflictMerge)
+		if err != nil {
+			t.Fatalf("ImportTokens() error = %v", err)
+		}
+		if report.Merged != 1 || report.IDMap["imported"] != "t1" {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		got, _ := mgr.GetToken("t1")
+		if got.APIKey != "key-new" || got.Subscription.CurrentCredits != 42 {
+			t.Fatalf("expected merged token with newer subscription, got %+v", got)
+		}
+		if len(mgr.ListTokens()) != 1 {
+			t.Fatalf("expected no duplicate token after merge")
+		}
+	})
+}
@@ -2,9 +2,12 @@ package token
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"code88reset/internal/api"
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/internal/reset"
 	"code88reset/pkg/logger"
@@ -17,14 +20,37 @@ type Manager struct {
 	storage       *Storage
 	baseURL       string
 	systemStorage SystemStorage // 用于记录系统日志
+	addMu         sync.Mutex    // 串行化添加/合并/导入，保证重复检查与写入的原子性
+	refreshMu     sync.Mutex    // 防止批量刷新重叠执行
+
+	autoDisableAfter atomic.Int32                                   // 连续鉴权失败多少次后自动禁用，0 表示不自动禁用
+	verification     atomic.Pointer[models.ResetVerificationConfig] // 重置核实轮询配置，为空时使用默认值
+	clientOpts       atomic.Pointer[api.ClientOptions]              // API 网络选项，为空时使用默认值
+	location         atomic.Pointer[time.Location]                  // 配置的时区，用于解析服务端不带时区的时间，为空时使用本地时区
+	usage            *UsageStore                                    // 用量采样数据，为空时不记录
+	journal          ResetJournal                                   // 重置事件日志，为空时不记录
 }
 
 // SystemStorage 系统存储接口
 type SystemStorage interface {
-	AddSystemLog(logType, message string) error
+	AddSystemMessage(logType string, msg i18n.Message) error
+	AddSystemLogEntry(entry models.SystemLog) error
 	SaveAPIResponse(endpoint, method string, requestBody, responseBody []byte, statusCode int) error
 }
 
+// scopedLogStorage 为 API 客户端写入的系统日志附加 Token ID 和批次 ID
+type scopedLogStorage struct {
+	SystemStorage
+	tokenID string
+	runID   string
+}
+
+func (s scopedLogStorage) AddSystemMessage(logType string, msg i18n.Message) error {
+	entry := models.SystemLog{Type: logType, TokenID: s.tokenID, RunID: s.runID}
+	entry.SetMessage(msg)
+	return s.SystemStorage.AddSystemLogEntry(entry)
+}
+
 // NewManager 创建 Token 管理器
 func NewManager(storage *Storage, baseURL string, systemStorage SystemStorage) *Manager {
 	return &Manager{
@@ -35,54 +61,71 @@ func NewManager(storage *Storage, baseURL string, systemStorage SystemStorage) *
 }
 
 // AddToken 添加新 Token 并自动获取订阅详情
+//
+// 同一 API Key 或同一员工（员工 ID / 邮箱）已存在时返回 *DuplicateError。
 func (m *Manager) AddToken(apiKey, name string) (*models.Token, error) {
+	return m.AddTokenWithRequest(AddRequest{APIKey: apiKey, Name: name})
+}
+
+// AddTokenWithRequest 按指定参数添加 Token，可为 Token 单独指定上游地址和代理
+func (m *Manager) AddTokenWithRequest(req AddRequest) (*models.Token, error) {
+	apiKey, name := req.APIKey, req.Name
+	baseURL, proxyURL, err := normalizeUpstream(req.BaseURL, req.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	m.addMu.Lock()
+	defer m.addMu.Unlock()
+
+	// 先按 API Key 查重，避免无谓的 API 调用
+	if err := m.checkDuplicate(&models.Token{APIKey: apiKey}); err != nil {
+		m.rejectDuplicate(name, err)
+		return nil, err
+	}
+
 	// 验证 API Key
-	client := api.NewClient(m.baseURL, apiKey, nil)
-	if m.systemStorage != nil {
-		client.Storage = m.systemStorage
+	client, err := m.newClient(&models.Token{APIKey: apiKey, BaseURL: baseURL, ProxyURL: proxyURL}, "")
+	if err != nil {
+		return nil, err
 	}
 
 	// 获取订阅详情
 	subs, err := client.GetSubscriptions()
 	if err != nil {
-		return nil, fmt.Errorf("获取订阅失败: %w", err)
+		return nil, i18n.Errorf("token.get_subscriptions_failed", err)
 	}
 
 	if len(subs) == 0 {
-		return nil, fmt.Errorf("该 API Key 没有关联的订阅")
+		return nil, i18n.Errorf("token.no_subscriptions")
 	}
 
 	// 筛选目标订阅（优先选择 MONTHLY 且非 PAYGO 的订阅）
 	targetSub := findTargetSubscription(subs)
 	if targetSub == nil {
-		return nil, fmt.Errorf("未找到合适的订阅（需要 MONTHLY 类型且非 PAYGO）")
+		return nil, i18n.Errorf("token.no_eligible_subscription_hint", ErrNoEligibleSubscription)
 	}
 
 	// 构建 Token 对象
 	now := time.Now()
 	token := &models.Token{
-		ID:      uuid.New().String(),
-		Name:    name,
-		APIKey:  apiKey,
-		Enabled: true,
-		AddedAt: now,
-		Subscription: &models.TokenSubscriptionInfo{
-			ID:               targetSub.ID,
-			SubscriptionName: targetSub.SubscriptionName,
-			PlanType:         targetSub.SubscriptionPlan.PlanType,
-			CurrentCredits:   targetSub.CurrentCredits,
-			CreditLimit:      targetSub.SubscriptionPlan.CreditLimit,
-			CreditPercent:    calculatePercent(targetSub.CurrentCredits, targetSub.SubscriptionPlan.CreditLimit),
-			ResetTimes:       targetSub.ResetTimes,
-			Status:           targetSub.SubscriptionStatus,
-			RemainingDays:    targetSub.RemainingDays,
-			EmployeeName:     targetSub.EmployeeName,
-			EmployeeEmail:    targetSub.EmployeeEmail,
-			StartDate:        targetSub.StartDate,
-			EndDate:          targetSub.EndDate,
-			LastCreditReset:  targetSub.LastCreditReset,
-		},
+		ID:                    uuid.New().String(),
+		Name:                  name,
+		APIKey:                apiKey,
+		Enabled:               true,
+		AddedAt:               now,
+		Subscription:          newSubscriptionInfo(targetSub),
 		SubscriptionUpdatedAt: &now,
+		BaseURL:               baseURL,
+		ProxyURL:              proxyURL,
+	}
+
+	m.markHealthy(token)
+
+	// 按员工信息查重（同一账号的不同 API Key）
+	if err := m.checkDuplicate(token); err != nil {
+		m.rejectDuplicate(name, err)
+		return nil, err
 	}
 
 	// 保存到存储
@@ -94,6 +137,14 @@ func (m *Manager) AddToken(apiKey, name string) (*models.Token, error) {
 	return token, nil
 }
 
+// rejectDuplicate 记录被拒绝的重复添加
+func (m *Manager) rejectDuplicate(name string, err error) {
+	logger.Warn("拒绝添加 Token %s: %v", name, err)
+	if dup, ok := err.(*DuplicateError); ok {
+		m.addSystemLog("warning", i18n.M("token.log.duplicate_rejected", name, dup), dup.Existing.ID, "")
+	}
+}
+
 // RefreshSubscription 刷新 Token 的订阅信息
 func (m *Manager) RefreshSubscription(tokenID string) (*models.Token, error) {
 	token, err := m.storage.Get(tokenID)
@@ -102,159 +153,365 @@ func (m *Manager) RefreshSubscription(tokenID string) (*models.Token, error) {
 	}
 
 	// 获取最新订阅信息
-	client := api.NewClient(m.baseURL, token.APIKey, nil)
-	if m.systemStorage != nil {
-		client.Storage = m.systemStorage
+	client, err := m.newClient(token, "")
+	if err != nil {
+		return nil, err
 	}
-	subs, err := client.GetSubscriptions()
+	subs, err := client.RefreshSubscriptions()
 	if err != nil {
-		return nil, fmt.Errorf("获取订阅失败: %w", err)
+		m.recordFailure(tokenID, err, func(t *models.Token) { t.LastRefreshError = err.Error() })
+		return nil, i18n.Errorf("token.get_subscriptions_failed", err)
 	}
 
 	targetSub := findTargetSubscription(subs)
 	if targetSub == nil {
-		return nil, fmt.Errorf("未找到合适的订阅")
+		m.recordFailure(tokenID, ErrNoEligibleSubscription, func(t *models.Token) {
+			t.LastRefreshError = ErrNoEligibleSubscription.Error()
+		})
+		return nil, ErrNoEligibleSubscription
 	}
 
-	// 更新订阅信息
+	// 更新订阅信息（只修改订阅相关字段，不覆盖刷新期间的启用/禁用、重置记录等修改）
 	now := time.Now()
-	token.Subscription = &models.TokenSubscriptionInfo{
-		ID:               targetSub.ID,
-		SubscriptionName: targetSub.SubscriptionName,
-		PlanType:         targetSub.SubscriptionPlan.PlanType,
-		CurrentCredits:   targetSub.CurrentCredits,
-		CreditLimit:      targetSub.SubscriptionPlan.CreditLimit,
-		CreditPercent:    calculatePercent(targetSub.CurrentCredits, targetSub.SubscriptionPlan.CreditLimit),
-		ResetTimes:       targetSub.ResetTimes,
-		Status:           targetSub.SubscriptionStatus,
-		RemainingDays:    targetSub.RemainingDays,
-		EmployeeName:     targetSub.EmployeeName,
-		EmployeeEmail:    targetSub.EmployeeEmail,
-		StartDate:        targetSub.StartDate,
-		EndDate:          targetSub.EndDate,
-		LastCreditReset:  targetSub.LastCreditReset,
-	}
-	token.SubscriptionUpdatedAt = &now
-
-	if err := m.storage.Update(token); err != nil {
+	var verified bool
+	token, err = m.storage.Mutate(tokenID, func(t *models.Token) {
+		t.Subscription = newSubscriptionInfo(targetSub)
+		t.SubscriptionUpdatedAt = &now
+		t.LastRefreshError = ""
+		m.markHealthy(t)
+		verified = m.reverifyPendingReset(t, targetSub)
+	})
+	if err != nil {
 		return nil, err
 	}
 
+	if verified {
+		if token.LastReset.Success {
+			logger.Info("重置已确认生效: %s", token.Name)
+			m.addSystemLog("success", i18n.M("token.log.reset_succeeded", token.Name, token.LastReset.CodedMessage()), token.ID, token.LastReset.RunID)
+		} else {
+			logger.Warn("重置确认未生效: %s", token.Name)
+			m.addSystemLog("warning", i18n.M("token.log.reset_incomplete", token.Name, token.LastReset.CodedMessage()), token.ID, token.LastReset.RunID)
+		}
+	}
+
 	logger.Info("刷新订阅成功: %s (积分: %.2f/%.2f, resetTimes: %d)",
 		token.Name, token.Subscription.CurrentCredits, token.Subscription.CreditLimit, token.Subscription.ResetTimes)
 	return token, nil
 }
 
+// reverifyPendingReset 用刷新得到的订阅重新核实待核实的重置记录，有了确定结果时返回 true
+func (m *Manager) reverifyPendingReset(t *models.Token, sub *models.Subscription) bool {
+	if t.LastReset == nil || !t.LastReset.Pending() {
+		return false
+	}
+	before := models.Subscription{ResetTimes: t.LastReset.BeforeResets, LastCreditReset: t.LastReset.BeforeLastCreditReset}
+	state := reset.VerificationState(before, *sub, m.location.Load())
+	if state == reset.VerificationPending {
+		return false
+	}
+
+	// 复制后修改，避免影响已返回给调用方的 Token 副本
+	record := *t.LastReset
+	record.Verification = state
+	record.AfterCredits = sub.CurrentCredits
+	if state == reset.VerificationApplied {
+		record.Success = true
+		record.SetMessage(i18n.M("token.reset_succeeded",
+			fmt.Sprintf("%.2f", record.BeforeCredits), fmt.Sprintf("%.2f", record.AfterCredits),
+			record.BeforeResets, sub.ResetTimes))
+	} else {
+		record.SetMessage(i18n.M("reset.not_applied", sub.ResetTimes, fmt.Sprintf("%.4f", sub.CurrentCredits)))
+	}
+	t.LastReset = &record
+	return true
+}
+
 // ResetToken 手动重置指定 Token
 func (m *Manager) ResetToken(tokenID string, resetType string, thresholdPercent float64) (*models.Token, error) {
+	return m.ResetTokenWithRunID(tokenID, resetType, thresholdPercent, "")
+}
+
+// ResetTokenWithRunID 重置指定 Token，并将产生的日志和重置记录关联到批次 runID
+func (m *Manager) ResetTokenWithRunID(tokenID string, resetType string, thresholdPercent float64, runID string) (*models.Token, error) {
+	return m.ResetTokenWithRequest(tokenID, ResetRequest{
+		ResetType:        resetType,
+		ThresholdPercent: thresholdPercent,
+		RunID:            runID,
+	})
+}
+
+// ResetRequest 单个 Token 的重置参数
+type ResetRequest struct {
+	ResetType        string
+	ThresholdPercent float64
+	RunID            string
+	DisabledRules    []string // 该调度项停用的重置规则
+}
+
+// ScheduleResetRequest 按对应调度项（first/second）的阈值和停用规则构造重置参数
+func ScheduleResetRequest(cfg models.DynamicConfig, resetType, runID string) ResetRequest {
+	schedule := cfg.FirstReset
+	if resetType == "second" {
+		schedule = cfg.SecondReset
+	}
+
+	return ResetRequest{
+		ResetType:        resetType,
+		ThresholdPercent: schedule.ThresholdPercent,
+		RunID:            runID,
+		DisabledRules:    schedule.DisabledRules,
+	}
+}
+
+// SetResetVerification 设置重置后核实结果的轮询参数
+func (m *Manager) SetResetVerification(cfg models.ResetVerificationConfig) {
+	m.verification.Store(&cfg)
+}
+
+// SetLocation 设置配置的时区，服务端返回的 lastCreditReset 等不带时区的时间按此时区解析
+func (m *Manager) SetLocation(loc *time.Location) {
+	m.location.Store(loc)
+}
+
+// SetClientOptions 设置创建 API 客户端时使用的网络选项（代理、证书、超时、请求头）
+func (m *Manager) SetClientOptions(opts *api.ClientOptions) {
+	m.clientOpts.Store(opts)
+}
+
+// EndpointStatus 返回上游地址列表的状态，未配置多个地址时只包含主地址
+func (m *Manager) EndpointStatus() api.EndpointStatus {
+	if opts := m.clientOpts.Load(); opts != nil && opts.Endpoints != nil {
+		return opts.Endpoints.Status()
+	}
+	return api.EndpointStatus{
+		Current:   m.baseURL,
+		Endpoints: []api.EndpointInfo{{URL: m.baseURL, Healthy: true}},
+	}
+}
+
+// ResetTokenWithRequest 按指定参数重置 Token
+func (m *Manager) ResetTokenWithRequest(tokenID string, req ResetRequest) (*models.Token, error) {
+	resetType, thresholdPercent, runID := req.ResetType, req.ThresholdPercent, req.RunID
 	token, err := m.storage.Get(tokenID)
 	if err != nil {
 		return nil, err
 	}
 
 	if !token.Enabled {
-		return nil, fmt.Errorf("Token 已禁用")
+		return nil, i18n.Errorf("token.disabled")
 	}
 
 	// 创建 API 客户端
-	client := api.NewClient(m.baseURL, token.APIKey, nil)
-	if m.systemStorage != nil {
-		client.Storage = m.systemStorage
+	client, err := m.newClient(token, runID)
+	if err != nil {
+		return nil, err
 	}
 
 	// 获取最新订阅信息
 	subs, err := client.GetSubscriptions()
 	if err != nil {
-		return nil, fmt.Errorf("获取订阅失败: %w", err)
+		m.recordFailure(tokenID, err, nil)
+		err = i18n.Errorf("token.get_subscriptions_failed", err)
+		m.recordResetError(token, req, err)
+		return nil, err
 	}
 
 	targetSub := findTargetSubscription(subs)
 	if targetSub == nil {
-		return nil, fmt.Errorf("未找到合适的订阅")
+		m.recordFailure(tokenID, ErrNoEligibleSubscription, nil)
+		m.recordResetError(token, req, ErrNoEligibleSubscription)
+		return nil, ErrNoEligibleSubscription
+	}
+
+	// 订阅已到期时不再重置
+	if expired, _ := subscriptionExpired(newSubscriptionInfo(targetSub), time.Now()); expired {
+		now := time.Now()
+		token, err = m.storage.Mutate(tokenID, func(t *models.Token) {
+			t.Subscription = newSubscriptionInfo(targetSub)
+			t.SubscriptionUpdatedAt = &now
+			m.markHealthy(t)
+		})
+		if err != nil {
+			return nil, err
+		}
+		event := models.ResetEvent{Outcome: models.ResetOutcomeSkipped, SkipReason: "subscription_expired"}
+		event.SetMessage(i18n.MessageOf(ErrSubscriptionExpired))
+		m.recordResetEvent(token, req, event)
+		return nil, ErrSubscriptionExpired
 	}
 
 	// 记录重置前状态
 	beforeCredits := targetSub.CurrentCredits
 
 	// 执行重置逻辑
-	runner := reset.NewRunner(client, reset.Filter{
-		TargetPlans:    []string{},
-		RequireMonthly: true,
-	}, reset.Options{
+	opts := reset.Options{
 		ResetType:          resetType,
 		UseMaxThreshold:    true,
 		CreditThresholdMax: thresholdPercent,
 		CreditThresholdMin: 0,
 		SleepBetween:       3 * time.Second,
-	})
+		DisabledRules:      req.DisabledRules,
+		Location:           m.location.Load(),
+	}
+	if verify := m.verification.Load(); verify != nil {
+		opts.SleepBetween = time.Duration(verify.InitialDelaySeconds) * time.Second
+		opts.VerifyMaxInterval = time.Duration(verify.MaxIntervalSeconds) * time.Second
+		opts.VerifyTimeout = time.Duration(verify.TimeoutSeconds) * time.Second
+	}
+	runner := reset.NewRunner(client, reset.Filter{
+		TargetPlans:    []string{},
+		RequireMonthly: true,
+	}, opts)
 
 	results, err := runner.Execute()
 	if err != nil {
-		return nil, fmt.Errorf("执行重置失败: %w", err)
+		m.recordFailure(tokenID, err, nil)
+		err = i18n.Errorf("token.reset_failed", err)
+		m.recordResetError(token, req, err)
+		return nil, err
 	}
 
 	if len(results) == 0 {
-		return nil, fmt.Errorf("没有订阅被重置")
+		err := i18n.Errorf("token.nothing_reset")
+		m.recordResetError(token, req, err)
+		return nil, err
 	}
 
 	result := results[0]
 	now := time.Now()
 
 	// 更新重置记录
-	token.LastReset = &models.TokenResetRecord{
-		ResetAt:       now,
-		ResetType:     resetType,
-		Success:       result.Err == nil && !result.Skipped,
-		BeforeCredits: beforeCredits,
-		AfterCredits:  result.AfterCredits,
-		Message:       formatResetMessage(result),
-	}
-
-	// 更新订阅信息
-	if result.UpdatedSubscription != nil {
-		updatedSub := result.UpdatedSubscription
-		token.Subscription = &models.TokenSubscriptionInfo{
-			ID:               updatedSub.ID,
-			SubscriptionName: updatedSub.SubscriptionName,
-			PlanType:         updatedSub.SubscriptionPlan.PlanType,
-			CurrentCredits:   updatedSub.CurrentCredits,
-			CreditLimit:      updatedSub.SubscriptionPlan.CreditLimit,
-			CreditPercent:    calculatePercent(updatedSub.CurrentCredits, updatedSub.SubscriptionPlan.CreditLimit),
-			ResetTimes:       updatedSub.ResetTimes,
-			Status:           updatedSub.SubscriptionStatus,
-			RemainingDays:    updatedSub.RemainingDays,
-			EmployeeName:     updatedSub.EmployeeName,
-			EmployeeEmail:    updatedSub.EmployeeEmail,
-			StartDate:        updatedSub.StartDate,
-			EndDate:          updatedSub.EndDate,
-			LastCreditReset:  updatedSub.LastCreditReset,
+	record := &models.TokenResetRecord{
+		ResetAt:        now,
+		ResetType:      resetType,
+		Success:        result.Err == nil && !result.Skipped,
+		BeforeCredits:  beforeCredits,
+		AfterCredits:   result.AfterCredits,
+		RunID:          runID,
+		NextEligibleAt: result.NextEligibleAt,
+		Decisions:      result.Decisions,
+		Verification:   result.Verification,
+	}
+	record.SetMessage(formatResetMessage(result))
+	if record.Pending() {
+		record.BeforeResets = result.BeforeResets
+		record.BeforeLastCreditReset = targetSub.LastCreditReset
+	}
+
+	token, err = m.storage.Mutate(tokenID, func(t *models.Token) {
+		t.LastReset = record
+
+		// 更新订阅信息
+		if result.UpdatedSubscription != nil {
+			t.Subscription = newSubscriptionInfo(result.UpdatedSubscription)
+			t.SubscriptionUpdatedAt = &now
 		}
-		token.SubscriptionUpdatedAt = &now
-	}
 
-	if err := m.storage.Update(token); err != nil {
+		switch {
+		case record.Pending():
+			// 请求已发出但尚未确认生效，不计入 Token 健康状态，下次刷新订阅时重新核实
+		case result.Err != nil:
+			m.markFailed(t, result.Err)
+		default:
+			m.markHealthy(t)
+		}
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if token.LastReset.Success {
+	m.recordResetResult(token, req, result)
+
+	switch {
+	case token.LastReset.Success:
+		m.recordResetUsage(token.ID, targetSub, result)
 		logger.Info("重置成功: %s (%.2f → %.2f)", token.Name, beforeCredits, token.LastReset.AfterCredits)
-	} else {
+		m.addSystemLog("success", i18n.M("token.log.reset_succeeded", token.Name, token.LastReset.CodedMessage()), token.ID, runID)
+	case token.LastReset.Pending():
+		logger.Info("重置待核实: %s - %s", token.Name, token.LastReset.Message)
+		m.addSystemLog("info", i18n.M("token.log.reset_pending", token.Name, token.LastReset.CodedMessage()), token.ID, runID)
+	default:
 		logger.Warn("重置失败: %s - %s", token.Name, token.LastReset.Message)
+		m.addSystemLog("warning", i18n.M("token.log.reset_incomplete", token.Name, token.LastReset.CodedMessage()), token.ID, runID)
 	}
 
 	return token, nil
 }
 
-// ToggleToken 切换 Token 启用/禁用状态
-func (m *Manager) ToggleToken(tokenID string) (*models.Token, error) {
-	token, err := m.storage.Get(tokenID)
-	if err != nil {
-		return nil, err
+// ResetJournal 保存每次重置尝试的结果，供日报/周报汇总
+type ResetJournal interface {
+	Record(event models.ResetEvent) error
+}
+
+// SetResetJournal 设置重置事件日志，为空时不记录
+func (m *Manager) SetResetJournal(journal ResetJournal) {
+	m.journal = journal
+}
+
+// recordResetResult 记录重置执行结果，跳过时以第一条未放行的规则作为跳过原因
+func (m *Manager) recordResetResult(token *models.Token, req ResetRequest, result reset.Result) {
+	record := token.LastReset
+	event := models.ResetEvent{
+		Outcome:       models.ResetOutcomeSuccess,
+		BeforeCredits: record.BeforeCredits,
+		AfterCredits:  record.AfterCredits,
+		BeforeResets:  result.BeforeResets,
+		AfterResets:   result.AfterResets,
+	}
+	switch {
+	case result.Skipped:
+		event.Outcome = models.ResetOutcomeSkipped
+		for _, d := range result.Decisions {
+			if d.Verdict != reset.VerdictAllow {
+				event.SkipReason = d.Rule
+				break
+			}
+		}
+	case record.Pending():
+		event.Outcome = models.ResetOutcomePending
+	case !record.Success:
+		event.Outcome = models.ResetOutcomeFailed
 	}
+	event.SetMessage(record.CodedMessage())
+	m.recordResetEvent(token, req, event)
+}
+
+// recordResetError 记录未能执行重置的失败
+func (m *Manager) recordResetError(token *models.Token, req ResetRequest, err error) {
+	event := models.ResetEvent{Outcome: models.ResetOutcomeFailed}
+	event.SetMessage(i18n.MessageOf(err))
+	m.recordResetEvent(token, req, event)
+}
 
-	token.Enabled = !token.Enabled
+// recordResetEvent 补全 Token 和批次信息后写入重置事件日志，写入失败只记录警告
+func (m *Manager) recordResetEvent(token *models.Token, req ResetRequest, event models.ResetEvent) {
+	if m.journal == nil {
+		return
+	}
+	event.Time = time.Now()
+	event.TokenID = token.ID
+	event.TokenName = token.Name
+	event.ResetType = req.ResetType
+	event.RunID = req.RunID
+	if err := m.journal.Record(event); err != nil {
+		logger.Warn("记录重置事件失败: %v", err)
+	}
+}
 
-	if err := m.storage.Update(token); err != nil {
+// ToggleToken 切换 Token 启用/禁用状态
+func (m *Manager) ToggleToken(tokenID string) (*models.Token, error) {
+	token, err := m.storage.Mutate(tokenID, func(t *models.Token) {
+		t.Enabled = !t.Enabled
+
+		// 手动启用时清除自动禁用记录，重新累计鉴权失败次数
+		if t.Enabled && t.Health != nil {
+			health := tokenHealth(t)
+			health.ConsecutiveAuthFailures = 0
+			health.AutoDisabledAt = nil
+		}
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -277,6 +534,12 @@ func (m *Manager) DeleteToken(tokenID string) error {
 	if err := m.storage.Delete(tokenID); err != nil {
 		return err
 	}
+	api.DefaultCache.InvalidateAPIKey(token.APIKey)
+	if m.usage != nil {
+		if err := m.usage.Delete(tokenID); err != nil {
+			logger.Warn("删除 Token 用量数据失败: %v", err)
+		}
+	}
 
 	logger.Info("Token 已删除: %s", token.Name)
 	return nil
@@ -297,6 +560,48 @@ func (m *Manager) ListEnabledTokens() []*models.Token {
 	return m.storage.ListEnabled()
 }
 
+// newClient 按 Token 的上游设置创建 API 客户端，系统日志会关联到该 Token 和批次
+func (m *Manager) newClient(t *models.Token, runID string) (*api.Client, error) {
+	baseURL, opts := m.baseURL, m.clientOpts.Load()
+	if t.BaseURL != "" || t.ProxyURL != "" {
+		if opts == nil {
+			var err error
+			if opts, err = api.NewClientOptions(models.HTTPClientConfig{}); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if opts, err = opts.WithUpstream(t.BaseURL, t.ProxyURL); err != nil {
+			return nil, i18n.Errorf("token.invalid_upstream", err)
+		}
+		if t.BaseURL != "" {
+			baseURL = t.BaseURL
+		}
+	}
+
+	client := api.NewClientWithOptions(baseURL, t.APIKey, nil, opts)
+	if m.systemStorage != nil {
+		client.Storage = scopedLogStorage{
+			SystemStorage: m.systemStorage,
+			tokenID:       t.ID,
+			runID:         runID,
+		}
+	}
+	return client, nil
+}
+
+// addSystemLog 记录关联 Token 的系统日志
+func (m *Manager) addSystemLog(logType string, msg i18n.Message, tokenID, runID string) {
+	if m.systemStorage == nil {
+		return
+	}
+	entry := models.SystemLog{Type: logType, TokenID: tokenID, RunID: runID}
+	entry.SetMessage(msg)
+	if err := m.systemStorage.AddSystemLogEntry(entry); err != nil {
+		logger.Warn("记录系统日志失败: %v", err)
+	}
+}
+
 // findTargetSubscription 查找目标订阅（优先选择 MONTHLY 且非 PAYGO）
 func findTargetSubscription(subs []models.Subscription) *models.Subscription {
 	// 优先选择 MONTHLY 类型
@@ -305,7 +610,7 @@ func findTargetSubscription(subs []models.Subscription) *models.Subscription {
 		planType := sub.SubscriptionPlan.PlanType
 
 		// 排除 PAYGO
-		if isPAYGO(sub) {
+		if sub.IsPAYGO() {
 			continue
 		}
 
@@ -318,7 +623,7 @@ func findTargetSubscription(subs []models.Subscription) *models.Subscription {
 	// 如果没有 MONTHLY，返回第一个非 PAYGO 订阅
 	for i := range subs {
 		sub := &subs[i]
-		if !isPAYGO(sub) {
+		if !sub.IsPAYGO() {
 			return sub
 		}
 	}
@@ -326,16 +631,25 @@ func findTargetSubscription(subs []models.Subscription) *models.Subscription {
 	return nil
 }
 
-// isPAYGO 检查是否为 PAYGO 订阅
-func isPAYGO(sub *models.Subscription) bool {
-	planType := sub.SubscriptionPlan.PlanType
-	subscriptionName := sub.SubscriptionName
-	planSubscriptionName := sub.SubscriptionPlan.SubscriptionName
-
-	return planType == "PAYGO" ||
-		planType == "PAY_PER_USE" ||
-		subscriptionName == "PAYGO" ||
-		planSubscriptionName == "PAYGO"
+// newSubscriptionInfo 将 88code 订阅转换为 Token 保存的订阅详情
+func newSubscriptionInfo(sub *models.Subscription) *models.TokenSubscriptionInfo {
+	return &models.TokenSubscriptionInfo{
+		ID:               sub.ID,
+		SubscriptionName: sub.SubscriptionName,
+		PlanType:         sub.SubscriptionPlan.PlanType,
+		CurrentCredits:   sub.CurrentCredits,
+		CreditLimit:      sub.SubscriptionPlan.CreditLimit,
+		CreditPercent:    calculatePercent(sub.CurrentCredits, sub.SubscriptionPlan.CreditLimit),
+		ResetTimes:       sub.ResetTimes,
+		Status:           sub.SubscriptionStatus,
+		RemainingDays:    sub.RemainingDays,
+		EmployeeID:       sub.EmployeeID,
+		EmployeeName:     sub.EmployeeName,
+		EmployeeEmail:    sub.EmployeeEmail,
+		StartDate:        sub.StartDate,
+		EndDate:          sub.EndDate,
+		LastCreditReset:  sub.LastCreditReset,
+	}
 }
 
 // calculatePercent 计算百分比
@@ -347,13 +661,18 @@ func calculatePercent(current, limit float64) float64 {
 }
 
 // formatResetMessage 格式化重置消息
-func formatResetMessage(result reset.Result) string {
+func formatResetMessage(result reset.Result) i18n.Message {
 	if result.Err != nil {
-		return result.Err.Error()
+		return i18n.MessageOf(result.Err)
 	}
 	if result.Skipped {
-		return fmt.Sprintf("已跳过: %s", result.SkipReason)
+		reason := result.SkipMessage
+		if reason.Code == "" {
+			reason = i18n.M(i18n.TextCode, result.SkipReason)
+		}
+		return i18n.M("token.reset_skipped", reason)
 	}
-	return fmt.Sprintf("重置成功 (%.2f → %.2f, resetTimes: %d → %d)",
-		result.BeforeCredits, result.AfterCredits, result.BeforeResets, result.AfterResets)
+	return i18n.M("token.reset_succeeded",
+		fmt.Sprintf("%.2f", result.BeforeCredits), fmt.Sprintf("%.2f", result.AfterCredits),
+		result.BeforeResets, result.AfterResets)
 }
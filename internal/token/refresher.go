@@ -0,0 +1,186 @@
+package token
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/pkg/logger"
+)
+
+// ErrRefreshInProgress 已有一轮批量刷新正在进行
+var ErrRefreshInProgress = i18n.Errorf("token.refresh_in_progress")
+
+const defaultRefreshConcurrency = 4
+
+// RefreshItem 单个 Token 的刷新结果
+type RefreshItem struct {
+	TokenID    string `json:"token_id"`
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+	KeyInvalid bool   `json:"key_invalid,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// RefreshSummary 批量刷新汇总
+type RefreshSummary struct {
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Total      int           `json:"total"`
+	Succeeded  int           `json:"succeeded"`
+	Failed     int           `json:"failed"`
+	KeyInvalid int           `json:"key_invalid"`
+	Items      []RefreshItem `json:"items"`
+}
+
+// RefreshAll 并发刷新所有启用 Token 的订阅信息，concurrency <= 0 时使用默认值
+func (m *Manager) RefreshAll(concurrency int) (*RefreshSummary, error) {
+	if !m.refreshMu.TryLock() {
+		return nil, ErrRefreshInProgress
+	}
+	defer m.refreshMu.Unlock()
+
+	if concurrency <= 0 {
+		concurrency = defaultRefreshConcurrency
+	}
+
+	tokens := m.ListEnabledTokens()
+	summary := &RefreshSummary{
+		StartedAt: time.Now(),
+		Total:     len(tokens),
+		Items:     make([]RefreshItem, len(tokens)),
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, t := range tokens {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, t *models.Token) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			item := RefreshItem{TokenID: t.ID, Name: t.Name}
+			if _, err := m.RefreshSubscription(t.ID); err != nil {
+				item.Error = err.Error()
+				if updated, getErr := m.storage.Get(t.ID); getErr == nil {
+					item.KeyInvalid = updated.KeyInvalid
+				}
+			} else {
+				item.Success = true
+			}
+			summary.Items[i] = item
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, item := range summary.Items {
+		switch {
+		case item.Success:
+			summary.Succeeded++
+		case item.KeyInvalid:
+			summary.KeyInvalid++
+			summary.Failed++
+		default:
+			summary.Failed++
+		}
+	}
+	summary.FinishedAt = time.Now()
+
+	logger.Info("批量刷新订阅完成: 共 %d, 成功 %d, 失败 %d (Key 失效 %d), 耗时 %s",
+		summary.Total, summary.Succeeded, summary.Failed, summary.KeyInvalid,
+		summary.FinishedAt.Sub(summary.StartedAt).Round(time.Millisecond))
+	if summary.Failed > 0 {
+		m.addSystemLog("warning", i18n.M("token.log.refresh_summary",
+			summary.Total, summary.Succeeded, summary.Failed, summary.KeyInvalid), "", "")
+	}
+	return summary, nil
+}
+
+// Refresher 按配置的间隔在后台刷新所有启用 Token 的订阅信息
+type Refresher struct {
+	manager *Manager
+
+	mu      sync.Mutex
+	cfg     models.SubscriptionRefreshConfig
+	updated chan struct{}
+}
+
+// NewRefresher 创建后台订阅刷新器
+func NewRefresher(manager *Manager, cfg models.SubscriptionRefreshConfig) *Refresher {
+	return &Refresher{
+		manager: manager,
+		cfg:     cfg,
+		updated: make(chan struct{}, 1),
+	}
+}
+
+// UpdateConfig 更新刷新配置，下一轮等待时间按新配置重新计算
+func (r *Refresher) UpdateConfig(cfg models.SubscriptionRefreshConfig) {
+	r.mu.Lock()
+	changed := r.cfg != cfg
+	r.cfg = cfg
+	r.mu.Unlock()
+
+	if changed {
+		select {
+		case r.updated <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run 运行刷新循环，直到 ctx 结束
+func (r *Refresher) Run(ctx context.Context) {
+	logger.Info("启动后台订阅刷新器...")
+
+	for {
+		cfg := r.config()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if cfg.Enabled && cfg.IntervalMinutes > 0 {
+			delay := nextRefreshDelay(cfg)
+			logger.Debug("下一次后台订阅刷新: %s 后", delay.Round(time.Second))
+			timer = time.NewTimer(delay)
+			fire = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			logger.Info("后台订阅刷新器已停止")
+			return
+		case <-r.updated:
+			stopTimer(timer)
+		case <-fire:
+			if _, err := r.manager.RefreshAll(cfg.Concurrency); err != nil {
+				logger.Warn("后台订阅刷新跳过: %v", err)
+			}
+		}
+	}
+}
+
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+func (r *Refresher) config() models.SubscriptionRefreshConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cfg
+}
+
+// nextRefreshDelay 计算下一轮刷新的等待时间（间隔 + 随机延迟）
+func nextRefreshDelay(cfg models.SubscriptionRefreshConfig) time.Duration {
+	delay := time.Duration(cfg.IntervalMinutes) * time.Minute
+	if cfg.JitterSeconds > 0 {
+		delay += time.Duration(rand.Int63n(int64(cfg.JitterSeconds)*int64(time.Second) + 1))
+	}
+	return delay
+}
@@ -0,0 +1,102 @@
+package token
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestRefreshAll_MarksInvalidKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"msg":"unauthorized"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"ok":true,"data":[{"id":1,"employeeId":7,"employeeEmail":"a@example.com","subscriptionPlanName":"PRO","currentCredits":10,"subscriptionPlan":{"planType":"MONTHLY","creditLimit":20}}]}`))
+	}))
+	defer srv.Close()
+
+	mgr := newTestManager(t,
+		tokenWithEmail("good", "valid", "a@example.com"),
+		tokenWithEmail("bad", "revoked", "b@example.com"),
+	)
+	mgr.baseURL = srv.URL
+
+	summary, err := mgr.RefreshAll(2)
+	if err != nil {
+		t.Fatalf("RefreshAll() error = %v", err)
+	}
+	if summary.Total != 2 || summary.Succeeded != 1 || summary.KeyInvalid != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+
+	good, _ := mgr.GetToken("good")
+	if good.SubscriptionUpdatedAt == nil || good.Subscription.CurrentCredits != 10 || good.KeyInvalid {
+		t.Fatalf("expected refreshed token, got %+v", good)
+	}
+	bad, _ := mgr.GetToken("bad")
+	if !bad.KeyInvalid || bad.LastRefreshError == "" {
+		t.Fatalf("expected invalid key to be marked, got %+v", bad)
+	}
+}
+
+func TestRefreshSubscription_KeepsConcurrentToggle(t *testing.T) {
+	var mgr *Manager
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 刷新请求进行中时禁用 Token
+		if _, err := mgr.ToggleToken("good"); err != nil {
+			t.Errorf("ToggleToken() error = %v", err)
+		}
+		w.Write([]byte(`{"code":0,"ok":true,"data":[{"id":1,"employeeId":7,"employeeEmail":"a@example.com","subscriptionPlanName":"PRO","currentCredits":10,"subscriptionPlan":{"planType":"MONTHLY","creditLimit":20}}]}`))
+	}))
+	defer srv.Close()
+
+	mgr = newTestManager(t, tokenWithEmail("good", "valid", "a@example.com"))
+	mgr.baseURL = srv.URL
+
+	if _, err := mgr.RefreshSubscription("good"); err != nil {
+		t.Fatalf("RefreshSubscription() error = %v", err)
+	}
+
+	got, _ := mgr.GetToken("good")
+	if got.Enabled {
+		t.Fatalf("refresh overwrote the concurrent disable")
+	}
+	if got.Subscription == nil || got.Subscription.CurrentCredits != 10 {
+		t.Fatalf("expected refreshed subscription, got %+v", got.Subscription)
+	}
+}
+
+func TestRefreshSubscription_ReverifiesPendingReset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"ok":true,"data":[{"id":1,"employeeId":7,"employeeEmail":"a@example.com","subscriptionPlanName":"PRO","currentCredits":20,"resetTimes":1,"subscriptionPlan":{"planType":"MONTHLY","creditLimit":20}}]}`))
+	}))
+	defer srv.Close()
+
+	tok := tokenWithEmail("good", "valid", "a@example.com")
+	tok.LastReset = &models.TokenResetRecord{ResetType: "first", BeforeCredits: 5, BeforeResets: 2, Verification: "pending"}
+	mgr := newTestManager(t, tok)
+	mgr.baseURL = srv.URL
+
+	got, err := mgr.RefreshSubscription("good")
+	if err != nil {
+		t.Fatalf("RefreshSubscription() error = %v", err)
+	}
+	if r := got.LastReset; !r.Success || r.Verification != "applied" || r.AfterCredits != 20 {
+		t.Fatalf("expected pending reset to be confirmed, got %+v", r)
+	}
+}
+
+func TestNextRefreshDelay(t *testing.T) {
+	cfg := models.SubscriptionRefreshConfig{IntervalMinutes: 10, JitterSeconds: 30}
+	for i := 0; i < 100; i++ {
+		d := nextRefreshDelay(cfg)
+		if d < 10*time.Minute || d > 10*time.Minute+30*time.Second {
+			t.Fatalf("delay out of range: %s", d)
+		}
+	}
+}
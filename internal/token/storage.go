@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"sync"
 
+	"code88reset/internal/i18n"
 	"code88reset/internal/models"
 	"code88reset/pkg/logger"
 )
@@ -107,7 +108,7 @@ func (s *Storage) Add(token *models.Token) error {
 	defer s.mu.Unlock()
 
 	if _, exists := s.tokens[token.ID]; exists {
-		return fmt.Errorf("Token ID 已存在: %s", token.ID)
+		return i18n.Errorf("token.id_exists", token.ID)
 	}
 
 	s.tokens[token.ID] = token
@@ -120,20 +121,42 @@ func (s *Storage) Update(token *models.Token) error {
 	defer s.mu.Unlock()
 
 	if _, exists := s.tokens[token.ID]; !exists {
-		return fmt.Errorf("Token 不存在: %s", token.ID)
+		return i18n.Errorf("token.not_found", token.ID)
 	}
 
 	s.tokens[token.ID] = token
 	return s.saveUnlocked()
 }
 
+// Mutate 在存储锁内修改 Token 并保存，返回修改后的副本
+//
+// 与 Get + Update 不同，fn 看到的是最新数据，只修改它关心的字段，不会覆盖期间其他操作（启用/禁用、重置等）的修改。
+// fn 在持有锁时执行，不能进行网络请求等耗时操作，也不能再访问 Storage。
+func (s *Storage) Mutate(tokenID string, fn func(token *models.Token)) (*models.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, exists := s.tokens[tokenID]
+	if !exists {
+		return nil, i18n.Errorf("token.not_found", tokenID)
+	}
+
+	fn(token)
+	if err := s.saveUnlocked(); err != nil {
+		return nil, err
+	}
+
+	tokenCopy := *token
+	return &tokenCopy, nil
+}
+
 // Delete 删除 Token
 func (s *Storage) Delete(tokenID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if _, exists := s.tokens[tokenID]; !exists {
-		return fmt.Errorf("Token 不存在: %s", tokenID)
+		return i18n.Errorf("token.not_found", tokenID)
 	}
 
 	delete(s.tokens, tokenID)
@@ -147,7 +170,7 @@ func (s *Storage) Get(tokenID string) (*models.Token, error) {
 
 	token, exists := s.tokens[tokenID]
 	if !exists {
-		return nil, fmt.Errorf("Token 不存在: %s", tokenID)
+		return nil, i18n.Errorf("token.not_found", tokenID)
 	}
 
 	// 返回副本，避免外部修改
@@ -237,3 +260,20 @@ func (s *Storage) saveUnlocked() error {
 
 	return nil
 }
+
+// Reload 从文件重新加载 Token 数据（用于恢复备份后同步内存状态）
+func (s *Storage) Reload() error {
+	return s.load()
+}
+
+// Snapshot 返回当前内存中 Token 数据的一致性快照
+func (s *Storage) Snapshot() models.TokenStorage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tokens := make([]models.Token, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, *token)
+	}
+	return models.TokenStorage{Tokens: tokens}
+}
@@ -0,0 +1,36 @@
+package token
+
+import (
+	"net/url"
+	"strings"
+
+	"code88reset/internal/api"
+	"code88reset/internal/i18n"
+)
+
+// AddRequest 添加 Token 的参数
+type AddRequest struct {
+	APIKey   string
+	Name     string
+	BaseURL  string // 单独的上游地址，为空时使用全局地址
+	ProxyURL string // 单独的代理地址，为空时使用全局代理设置
+}
+
+// normalizeUpstream 校验并规范化 Token 的上游地址和代理地址
+func normalizeUpstream(baseURL, proxyURL string) (string, string, error) {
+	baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	proxyURL = strings.TrimSpace(proxyURL)
+
+	if baseURL != "" {
+		u, err := url.Parse(baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", "", i18n.Errorf("token.invalid_base_url", baseURL)
+		}
+	}
+	if proxyURL != "" {
+		if _, err := api.ParseProxyURL(proxyURL); err != nil {
+			return "", "", err
+		}
+	}
+	return baseURL, proxyURL, nil
+}
@@ -0,0 +1,71 @@
+package token
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAddTokenWithRequest_UsesPerTokenBaseURL(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`{"code":0,"ok":true,"data":[{"id":1,"employeeId":7,"employeeEmail":"a@example.com","subscriptionPlanName":"PRO","currentCredits":10,"subscriptionPlan":{"planType":"MONTHLY","creditLimit":20}}]}`))
+	}))
+	defer srv.Close()
+
+	// 全局地址不可用，只有 Token 单独的上游地址可以访问
+	mgr := newTestManager(t)
+
+	tok, err := mgr.AddTokenWithRequest(AddRequest{APIKey: "self-hosted", Name: "A", BaseURL: srv.URL + "/"})
+	if err != nil {
+		t.Fatalf("AddTokenWithRequest() error = %v", err)
+	}
+	if tok.BaseURL != srv.URL {
+		t.Fatalf("BaseURL = %q, want normalized %q", tok.BaseURL, srv.URL)
+	}
+
+	if _, err := mgr.RefreshSubscription(tok.ID); err != nil {
+		t.Fatalf("RefreshSubscription() error = %v", err)
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("expected both requests to hit the per-token base URL, got %d", hits.Load())
+	}
+
+	// 恢复使用全局地址后刷新失败
+	empty := ""
+	if _, err := mgr.UpdateTokenSettings(tok.ID, TokenSettings{BaseURL: &empty}); err != nil {
+		t.Fatalf("UpdateTokenSettings() error = %v", err)
+	}
+	if _, err := mgr.RefreshSubscription(tok.ID); err == nil {
+		t.Fatal("expected refresh against the global base URL to fail")
+	}
+}
+
+func TestUpdateTokenSettings_ValidatesUpstream(t *testing.T) {
+	mgr := newTestManager(t, tokenWithEmail("t1", "key-1", "a@example.com"))
+
+	threshold := 30.0
+	if _, err := mgr.UpdateTokenSettings("t1", TokenSettings{LowCreditThreshold: &threshold}); err != nil {
+		t.Fatalf("UpdateTokenSettings() error = %v", err)
+	}
+
+	bad := "ftp://proxy:21"
+	if _, err := mgr.UpdateTokenSettings("t1", TokenSettings{ProxyURL: &bad}); err == nil {
+		t.Fatal("expected invalid proxy to be rejected")
+	}
+	badURL := "not a url"
+	if _, err := mgr.UpdateTokenSettings("t1", TokenSettings{BaseURL: &badURL}); err == nil {
+		t.Fatal("expected invalid base URL to be rejected")
+	}
+
+	proxy := "socks5://127.0.0.1:1080"
+	tok, err := mgr.UpdateTokenSettings("t1", TokenSettings{ProxyURL: &proxy})
+	if err != nil {
+		t.Fatalf("UpdateTokenSettings() error = %v", err)
+	}
+	if tok.ProxyURL != proxy || tok.LowCreditThreshold == nil || *tok.LowCreditThreshold != 30 {
+		t.Fatalf("expected proxy set and threshold kept, got %+v", tok)
+	}
+}
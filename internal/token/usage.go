@@ -0,0 +1,368 @@
+package token
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/internal/reset"
+	"code88reset/pkg/logger"
+)
+
+// UsageEventReset 重置成功的事件点
+const UsageEventReset = "reset"
+
+// UsageStore 按 Token 保存用量采样时间序列（usage/<token_id>.jsonl，每行一个采样点）
+type UsageStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewUsageStore 创建用量存储
+func NewUsageStore(dataDir string) (*UsageStore, error) {
+	dir := filepath.Join(dataDir, "usage")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("创建用量数据目录失败: %w", err)
+	}
+	return &UsageStore{dir: dir}, nil
+}
+
+func (s *UsageStore) path(tokenID string) string {
+	return filepath.Join(s.dir, filepath.Base(tokenID)+".jsonl")
+}
+
+// Append 追加一个采样点
+func (s *UsageStore) Append(tokenID string, point models.UsagePoint) error {
+	line, err := json.Marshal(point)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path(tokenID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("写入用量数据失败: %w", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// Query 返回 [from, to] 时间范围内的采样点（按时间排序），from/to 为零值时不限制
+func (s *UsageStore) Query(tokenID string, from, to time.Time) ([]models.UsagePoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	points, err := s.readUnlocked(tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.UsagePoint, 0, len(points))
+	for _, p := range points {
+		if (!from.IsZero() && p.Time.Before(from)) || (!to.IsZero() && p.Time.After(to)) {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// Delete 删除 Token 的全部采样数据
+func (s *UsageStore) Delete(tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path(tokenID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Prune 删除早于 before 的采样点
+func (s *UsageStore) Prune(before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".jsonl" {
+			continue
+		}
+		tokenID := name[:len(name)-len(".jsonl")]
+
+		points, err := s.readUnlocked(tokenID)
+		if err != nil {
+			return err
+		}
+		kept := points[:0]
+		for _, p := range points {
+			if !p.Time.Before(before) {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == len(points) {
+			continue
+		}
+		if len(kept) == 0 {
+			if err := os.Remove(s.path(tokenID)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		var buf bytes.Buffer
+		for _, p := range kept {
+			line, err := json.Marshal(p)
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		tmp := s.path(tokenID) + ".tmp"
+		if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, s.path(tokenID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *UsageStore) readUnlocked(tokenID string) ([]models.UsagePoint, error) {
+	f, err := os.Open(s.path(tokenID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var points []models.UsagePoint
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p models.UsagePoint
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			continue // 跳过写入中断产生的不完整行
+		}
+		points = append(points, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points, nil
+}
+
+// DownsamplePoints 将采样点按时间均分为至多 maxPoints 个区间，每个区间保留最后一个采样点；事件点（如重置）总是保留
+func DownsamplePoints(points []models.UsagePoint, maxPoints int) []models.UsagePoint {
+	if maxPoints <= 0 || len(points) <= maxPoints {
+		return points
+	}
+
+	start, end := points[0].Time, points[len(points)-1].Time
+	span := end.Sub(start)
+	if span <= 0 {
+		return points[len(points)-maxPoints:]
+	}
+	bucketSize := span / time.Duration(maxPoints)
+	if bucketSize <= 0 {
+		bucketSize = 1
+	}
+
+	result := make([]models.UsagePoint, 0, maxPoints)
+	var last *models.UsagePoint
+	lastBucket := -1
+	flush := func() {
+		if last != nil {
+			result = append(result, *last)
+			last = nil
+		}
+	}
+
+	for i := range points {
+		p := points[i]
+		bucket := int(p.Time.Sub(start) / bucketSize)
+		if bucket >= maxPoints {
+			bucket = maxPoints - 1
+		}
+		if bucket != lastBucket {
+			flush()
+			lastBucket = bucket
+		}
+		if p.Event != "" {
+			flush()
+			result = append(result, p)
+			continue
+		}
+		last = &points[i]
+	}
+	flush()
+	return result
+}
+
+// AggregateDaily 按天（loc 时区）汇总消耗积分和重置次数
+func AggregateDaily(points []models.UsagePoint, loc *time.Location) []models.DailyUsage {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	days := make([]models.DailyUsage, 0)
+	index := make(map[string]int)
+	day := func(t time.Time) *models.DailyUsage {
+		key := t.In(loc).Format("2006-01-02")
+		i, ok := index[key]
+		if !ok {
+			i = len(days)
+			index[key] = i
+			days = append(days, models.DailyUsage{Date: key})
+		}
+		return &days[i]
+	}
+
+	for i, p := range points {
+		d := day(p.Time)
+		d.Samples++
+		if p.Event == UsageEventReset {
+			d.Resets++
+		}
+		if i > 0 {
+			if drop := points[i-1].Credits - p.Credits; drop > 0 {
+				d.Consumed += drop
+			}
+		}
+	}
+	return days
+}
+
+// SetUsageStore 设置用量采样数据的存储，为空时不记录用量
+func (m *Manager) SetUsageStore(store *UsageStore) {
+	m.usage = store
+}
+
+// QueryUsage 查询 Token 在时间范围内的用量采样
+func (m *Manager) QueryUsage(tokenID string, from, to time.Time) ([]models.UsagePoint, error) {
+	if m.usage == nil {
+		return nil, nil
+	}
+	return m.usage.Query(tokenID, from, to)
+}
+
+// SampleUsage 通过用量接口采样所有启用 Token 的当前积分，用量接口不可用时使用订阅列表
+func (m *Manager) SampleUsage() (sampled int, failed int) {
+	if m.usage == nil {
+		return 0, 0
+	}
+
+	for _, t := range m.ListEnabledTokens() {
+		point, err := m.sampleToken(t)
+		if err != nil {
+			logger.Debug("用量采样失败: %s - %v", t.Name, err)
+			failed++
+			continue
+		}
+		if err := m.usage.Append(t.ID, point); err != nil {
+			logger.Warn("保存用量采样失败: %v", err)
+			failed++
+			continue
+		}
+		sampled++
+	}
+	return sampled, failed
+}
+
+func (m *Manager) sampleToken(t *models.Token) (models.UsagePoint, error) {
+	client, err := m.newClient(t, "")
+	if err != nil {
+		return models.UsagePoint{}, err
+	}
+	// 采样频繁，不写入系统日志
+	client.Storage = nil
+
+	now := time.Now()
+	if usage, err := client.GetUsage(); err == nil {
+		point := models.UsagePoint{Time: now, Credits: usage.CurrentCredits, CreditLimit: usage.CreditLimit}
+		for _, sub := range usage.SubscriptionEntityList {
+			if t.Subscription != nil && sub.ID == t.Subscription.ID {
+				point.Credits, point.CreditLimit, point.ResetTimes = sub.CurrentCredits, sub.SubscriptionPlan.CreditLimit, sub.ResetTimes
+				break
+			}
+		}
+		if point.CreditLimit > 0 {
+			return point, nil
+		}
+	}
+
+	subs, err := client.GetSubscriptions()
+	if err != nil {
+		return models.UsagePoint{}, err
+	}
+	sub := findTargetSubscription(subs)
+	if sub == nil {
+		return models.UsagePoint{}, ErrNoEligibleSubscription
+	}
+	return models.UsagePoint{
+		Time:        now,
+		Credits:     sub.CurrentCredits,
+		CreditLimit: sub.SubscriptionPlan.CreditLimit,
+		ResetTimes:  sub.ResetTimes,
+	}, nil
+}
+
+// PruneUsage 删除超过保留天数的采样数据
+func (m *Manager) PruneUsage(retentionDays int) {
+	if m.usage == nil || retentionDays <= 0 {
+		return
+	}
+	if err := m.usage.Prune(time.Now().AddDate(0, 0, -retentionDays)); err != nil {
+		logger.Warn("清理用量采样数据失败: %v", err)
+	}
+}
+
+// recordResetUsage 记录重置前后的积分，重置后的点标记为重置事件
+func (m *Manager) recordResetUsage(tokenID string, before *models.Subscription, result reset.Result) {
+	if m.usage == nil {
+		return
+	}
+	now := time.Now()
+	points := []models.UsagePoint{
+		{
+			Time:        now.Add(-time.Second),
+			Credits:     before.CurrentCredits,
+			CreditLimit: before.SubscriptionPlan.CreditLimit,
+			ResetTimes:  before.ResetTimes,
+		},
+		{
+			Time:        now,
+			Credits:     result.AfterCredits,
+			CreditLimit: before.SubscriptionPlan.CreditLimit,
+			ResetTimes:  result.AfterResets,
+			Event:       UsageEventReset,
+		},
+	}
+	for _, p := range points {
+		if err := m.usage.Append(tokenID, p); err != nil {
+			logger.Warn("保存用量采样失败: %v", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+func TestUsageStore_AppendQueryPrune(t *testing.T) {
+	store, err := NewUsageStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewUsageStore() error = %v", err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		if err := store.Append("t1", models.UsagePoint{Time: base.Add(time.Duration(i) * time.Hour), Credits: float64(100 - i)}); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	points, err := store.Query("t1", base.Add(time.Hour), base.Add(3*time.Hour))
+	if err != nil || len(points) != 3 || points[0].Credits != 99 {
+		t.Fatalf("Query() = %+v, %v", points, err)
+	}
+
+	if err := store.Prune(base.Add(2 * time.Hour)); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	points, _ = store.Query("t1", time.Time{}, time.Time{})
+	if len(points) != 3 || !points[0].Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("after Prune() = %+v", points)
+	}
+
+	if err := store.Delete("t1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if points, _ := store.Query("t1", time.Time{}, time.Time{}); len(points) != 0 {
+		t.Fatalf("expected no points after Delete(), got %d", len(points))
+	}
+}
+
+func TestDownsamplePoints_KeepsResetEvents(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var points []models.UsagePoint
+	for i := 0; i < 100; i++ {
+		p := models.UsagePoint{Time: base.Add(time.Duration(i) * time.Minute), Credits: float64(i)}
+		if i == 37 {
+			p.Event = UsageEventReset
+		}
+		points = append(points, p)
+	}
+
+	got := DownsamplePoints(points, 10)
+	if len(got) > 12 {
+		t.Fatalf("expected about 10 points, got %d", len(got))
+	}
+	found := false
+	for i, p := range got {
+		if p.Event == UsageEventReset {
+			found = true
+		}
+		if i > 0 && p.Time.Before(got[i-1].Time) {
+			t.Fatalf("points out of order: %+v", got)
+		}
+	}
+	if !found {
+		t.Fatal("reset event was dropped by downsampling")
+	}
+	if got[len(got)-1].Credits != 99 {
+		t.Fatalf("last point should be kept, got %+v", got[len(got)-1])
+	}
+}
+
+func TestAggregateDaily_IgnoresResetIncrease(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	points := []models.UsagePoint{
+		{Time: day1, Credits: 100},
+		{Time: day1.Add(time.Hour), Credits: 60},
+		{Time: day1.Add(2 * time.Hour), Credits: 100, Event: UsageEventReset},
+		{Time: day1.Add(3 * time.Hour), Credits: 90},
+		{Time: day2, Credits: 50},
+	}
+
+	daily := AggregateDaily(points, time.UTC)
+	if len(daily) != 2 {
+		t.Fatalf("expected 2 days, got %+v", daily)
+	}
+	if daily[0].Consumed != 50 || daily[0].Resets != 1 || daily[0].Samples != 4 {
+		t.Fatalf("day1 = %+v, want consumed=50 resets=1 samples=4", daily[0])
+	}
+	if daily[1].Consumed != 40 {
+		t.Fatalf("day2 = %+v, want consumed=40", daily[1])
+	}
+}
@@ -0,0 +1,152 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"code88reset/internal/backup"
+	"code88reset/internal/i18n"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// handleExportTokens 导出全部 Token 归档
+func (s *Server) handleExportTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	var req webapi.ExportTokensRequest
+	if r.ContentLength != 0 {
+		if err := readJSON(r, &req); err != nil {
+			writeInvalidJSON(w, err)
+			return
+		}
+	}
+
+	includeHistory := req.IncludeHistory == nil || *req.IncludeHistory
+	data, err := s.backupSvc.ExportTokens(req.Passphrase, includeHistory)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.export_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 导出 Token (加密=%v)", req.Passphrase != "")
+	writeAttachment(w, fmt.Sprintf("tokens_%s.json", time.Now().Format("20060102_150405")), data)
+}
+
+// handleImportTokens 从归档导入 Token
+func (s *Server) handleImportTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	var req webapi.ImportTokensRequest
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+	if len(req.Archive) == 0 {
+		writeError(w, http.StatusBadRequest, "web.archive_required")
+		return
+	}
+
+	mode, err := token.ParseConflictMode(req.Mode)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+		return
+	}
+
+	result, err := s.backupSvc.ImportTokens(req.Archive, req.Passphrase, mode)
+	if err != nil {
+		writeArchiveError(w, "web.import_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 导入 Token: 新增 %d, 覆盖 %d, 合并 %d, 跳过 %d",
+		result.Added, result.Overwritten, result.Merged, result.Skipped)
+	writeJSON(w, http.StatusOK, webapi.ImportTokensResponse{
+		Success: true,
+		Message: tr(w, "web.import_completed",
+			result.Added, result.Overwritten, result.Merged, result.Skipped, result.Failed),
+		Report: result,
+	})
+}
+
+// handleBackup 生成 data 目录完整备份
+func (s *Server) handleBackup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	var req webapi.BackupRequest
+	if r.ContentLength != 0 {
+		if err := readJSON(r, &req); err != nil {
+			writeInvalidJSON(w, err)
+			return
+		}
+	}
+
+	data, err := s.backupSvc.Backup(req.Passphrase)
+	if err != nil {
+		writeError(w, http.StatusConflict, "web.backup_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 生成数据备份 (加密=%v)", req.Passphrase != "")
+	writeAttachment(w, fmt.Sprintf("backup_%s.json", time.Now().Format("20060102_150405")), data)
+}
+
+// handleRestore 从备份恢复 data 目录
+func (s *Server) handleRestore(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	var req webapi.RestoreRequest
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+	if len(req.Archive) == 0 {
+		writeError(w, http.StatusBadRequest, "web.archive_required")
+		return
+	}
+
+	result, err := s.backupSvc.Restore(req.Archive, req.Passphrase)
+	if err != nil {
+		writeArchiveError(w, "web.restore_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 恢复数据备份: %d 个文件", len(result.Files))
+	writeJSON(w, http.StatusOK, webapi.RestoreResponse{
+		Success: true,
+		Message: tr(w, "web.restored", len(result.Files)),
+		Result:  result,
+	})
+}
+
+// writeAttachment 以附件形式返回归档文件
+func writeAttachment(w http.ResponseWriter, filename string, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// writeArchiveError 根据归档错误类型返回合适的状态码，messageCode 的模板以错误为参数
+func writeArchiveError(w http.ResponseWriter, messageCode string, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, backup.ErrPassphraseRequired) {
+		status = http.StatusUnauthorized
+	}
+	writeError(w, status, messageCode, err)
+}
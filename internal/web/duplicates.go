@@ -0,0 +1,66 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// handleTokenDuplicates 列出属于同一账号的重复 Token
+func (s *Server) handleTokenDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	groups := s.tokenManager.FindDuplicates()
+	writeJSON(w, http.StatusOK, webapi.DuplicatesResponse{
+		Groups: maskDuplicateGroups(w, groups),
+		Count:  len(groups),
+	})
+}
+
+// handleMergeDuplicates 合并重复 Token
+//
+// 请求体: {"keep_id": "...", "remove_ids": ["..."]}，remove_ids 为空时合并同组全部重复项
+func (s *Server) handleMergeDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	var req webapi.MergeDuplicatesRequest
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+	if req.KeepID == "" {
+		writeError(w, http.StatusBadRequest, "web.keep_id_required")
+		return
+	}
+
+	merged, err := s.tokenManager.MergeDuplicates(req.KeepID, req.RemoveIDs)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "web.merge_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 合并重复 Token: %s", merged.Name)
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.duplicates_merged"),
+		Token:   maskToken(w, merged),
+	})
+}
+
+// asDuplicateError 判断添加 Token 失败是否因为重复
+func asDuplicateError(err error) (*token.DuplicateError, bool) {
+	var dup *token.DuplicateError
+	if errors.As(err, &dup) {
+		return dup, true
+	}
+	return nil, false
+}
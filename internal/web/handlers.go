@@ -1,12 +1,20 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
-	"code88reset/internal/models"
+	"code88reset/internal/config"
+	"code88reset/internal/i18n"
+	"code88reset/internal/reset"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
 	"code88reset/pkg/logger"
+
+	"github.com/google/uuid"
 )
 
 // handleTokens Token 列表管理
@@ -17,81 +25,81 @@ func (s *Server) handleTokens(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPost:
 		s.handleAddToken(w, r)
 	default:
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 	}
 }
 
 // handleListTokens 获取 Token 列表
 func (s *Server) handleListTokens(w http.ResponseWriter, r *http.Request) {
 	tokens := s.tokenManager.ListTokens()
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"tokens": tokens,
-		"count":  len(tokens),
+	writeJSON(w, http.StatusOK, webapi.TokenListResponse{
+		Tokens: maskTokens(w, tokens),
+		Count:  len(tokens),
 	})
 }
 
 // handleAddToken 添加新 Token
 func (s *Server) handleAddToken(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		APIKey string `json:"api_key"`
-		Name   string `json:"name"`
-	}
+	var req webapi.AddTokenRequest
 
 	if err := readJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		writeInvalidJSON(w, err)
 		return
 	}
 
 	if req.APIKey == "" {
-		writeError(w, http.StatusBadRequest, "API Key is required")
+		writeError(w, http.StatusBadRequest, "web.api_key_required")
 		return
 	}
 
 	if req.Name == "" {
-		req.Name = "Token " + req.APIKey[:8]
+		req.Name = defaultTokenName(req.APIKey)
 	}
 
-	token, err := s.tokenManager.AddToken(req.APIKey, req.Name)
+	token, err := s.addToken(req.APIKey, req.Name, req.UpstreamFields)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Failed to add token: "+err.Error())
+		if dup, ok := asDuplicateError(err); ok {
+			writeDuplicateError(w, err, dup)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "web.add_token_failed", err)
 		return
 	}
 
 	logger.Info("通过 Web API 添加 Token: %s", token.Name)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "Token added successfully",
-		"token":   token,
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.token_added"),
+		Token:   maskToken(w, token),
 	})
 }
 
 // handleBatchAddTokens 批量添加 Token
 func (s *Server) handleBatchAddTokens(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 		return
 	}
 
-	var req struct {
-		APIKeys string `json:"api_keys"` // 多个 API Key，每行一个
-		Prefix  string `json:"prefix"`   // Token 名称前缀，可选
-	}
+	var req webapi.BatchAddTokensRequest
 
 	if err := readJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		writeInvalidJSON(w, err)
 		return
 	}
 
 	if req.APIKeys == "" {
-		writeError(w, http.StatusBadRequest, "API Keys are required")
+		writeError(w, http.StatusBadRequest, "web.api_keys_required")
 		return
 	}
 
 	// 按行分割
 	lines := strings.Split(req.APIKeys, "\n")
-	var results []map[string]interface{}
+	results := make([]webapi.BatchAddResult, 0)
 	successCount := 0
 	failCount := 0
+	duplicateCount := 0
+	seen := make(map[string]string) // API Key -> 本批次中首次出现的名称
 
 	for i, line := range lines {
 		apiKey := strings.TrimSpace(line)
@@ -105,35 +113,58 @@ func (s *Server) handleBatchAddTokens(w http.ResponseWriter, r *http.Request) {
 			name = fmt.Sprintf("Token-%d", i+1)
 		}
 
+		// 同一批次内重复的 API Key
+		if first, ok := seen[apiKey]; ok {
+			results = append(results, webapi.BatchAddResult{
+				APIKey:    config.MaskAPIKey(apiKey),
+				Name:      name,
+				Duplicate: true,
+				Error:     tr(w, "web.batch_duplicate", first),
+			})
+			duplicateCount++
+			continue
+		}
+		seen[apiKey] = name
+
 		// 添加 Token
-		token, err := s.tokenManager.AddToken(apiKey, name)
-		if err != nil {
-			results = append(results, map[string]interface{}{
-				"api_key": apiKey[:10] + "...",
-				"name":    name,
-				"success": false,
-				"error":   err.Error(),
+		token, err := s.addToken(apiKey, name, req.UpstreamFields)
+		if dup, ok := asDuplicateError(err); ok {
+			results = append(results, webapi.BatchAddResult{
+				APIKey:            config.MaskAPIKey(apiKey),
+				Name:              name,
+				Duplicate:         true,
+				ExistingTokenID:   dup.Existing.ID,
+				ExistingTokenName: dup.Existing.Name,
+				Error:             i18n.Localize(err, localeOf(w)),
+			})
+			duplicateCount++
+		} else if err != nil {
+			results = append(results, webapi.BatchAddResult{
+				APIKey: config.MaskAPIKey(apiKey),
+				Name:   name,
+				Error:  i18n.Localize(err, localeOf(w)),
 			})
 			failCount++
 			logger.Warn("批量添加 Token 失败: %s - %v", name, err)
 		} else {
-			results = append(results, map[string]interface{}{
-				"api_key": apiKey[:10] + "...",
-				"name":    name,
-				"success": true,
-				"token":   token,
+			results = append(results, webapi.BatchAddResult{
+				APIKey:  config.MaskAPIKey(apiKey),
+				Name:    name,
+				Success: true,
+				Token:   maskToken(w, token),
 			})
 			successCount++
 			logger.Info("通过批量添加 Token: %s", token.Name)
 		}
 	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success":       true,
-		"message":       fmt.Sprintf("批量添加完成: 成功 %d, 失败 %d", successCount, failCount),
-		"success_count": successCount,
-		"fail_count":    failCount,
-		"results":       results,
+	writeJSON(w, http.StatusOK, webapi.BatchAddTokensResponse{
+		Success:        true,
+		Message:        tr(w, "web.batch_added", successCount, duplicateCount, failCount),
+		SuccessCount:   successCount,
+		DuplicateCount: duplicateCount,
+		FailCount:      failCount,
+		Results:        results,
 	})
 }
 
@@ -143,7 +174,7 @@ func (s *Server) handleTokenDetail(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/tokens/")
 	parts := strings.Split(path, "/")
 	if len(parts) == 0 || parts[0] == "" {
-		writeError(w, http.StatusBadRequest, "Token ID is required")
+		writeError(w, http.StatusBadRequest, "web.token_id_required")
 		return
 	}
 
@@ -151,9 +182,19 @@ func (s *Server) handleTokenDetail(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
+		if len(parts) > 1 && parts[1] == "usage" {
+			s.handleTokenUsage(w, r, tokenID)
+			return
+		}
 		s.handleGetToken(w, r, tokenID)
 	case http.MethodDelete:
 		s.handleDeleteToken(w, r, tokenID)
+	case http.MethodPost:
+		if len(parts) > 1 && parts[1] == "reveal" {
+			s.handleRevealAPIKey(w, r, tokenID)
+			return
+		}
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 	case http.MethodPut:
 		// 根据路径判断操作类型
 		if len(parts) > 1 {
@@ -164,14 +205,16 @@ func (s *Server) handleTokenDetail(w http.ResponseWriter, r *http.Request) {
 				s.handleRefreshToken(w, r, tokenID)
 			case "reset":
 				s.handleResetToken(w, r, tokenID)
+			case "settings":
+				s.handleUpdateTokenSettings(w, r, tokenID)
 			default:
-				writeError(w, http.StatusNotFound, "Unknown operation")
+				writeError(w, http.StatusNotFound, "web.unknown_operation")
 			}
 		} else {
-			writeError(w, http.StatusBadRequest, "Operation is required")
+			writeError(w, http.StatusBadRequest, "web.operation_required")
 		}
 	default:
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 	}
 }
 
@@ -179,24 +222,24 @@ func (s *Server) handleTokenDetail(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleGetToken(w http.ResponseWriter, r *http.Request, tokenID string) {
 	token, err := s.tokenManager.GetToken(tokenID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "Token not found")
+		writeTokenNotFound(w)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, token)
+	writeJSON(w, http.StatusOK, maskToken(w, token))
 }
 
 // handleDeleteToken 删除 Token
 func (s *Server) handleDeleteToken(w http.ResponseWriter, r *http.Request, tokenID string) {
 	if err := s.tokenManager.DeleteToken(tokenID); err != nil {
-		writeError(w, http.StatusNotFound, "Token not found")
+		writeTokenNotFound(w)
 		return
 	}
 
 	logger.Info("通过 Web API 删除 Token: %s", tokenID)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "Token deleted successfully",
+	writeJSON(w, http.StatusOK, webapi.MessageResponse{
+		Success: true,
+		Message: tr(w, "web.token_deleted"),
 	})
 }
 
@@ -204,15 +247,15 @@ func (s *Server) handleDeleteToken(w http.ResponseWriter, r *http.Request, token
 func (s *Server) handleToggleToken(w http.ResponseWriter, r *http.Request, tokenID string) {
 	token, err := s.tokenManager.ToggleToken(tokenID)
 	if err != nil {
-		writeError(w, http.StatusNotFound, "Token not found")
+		writeTokenNotFound(w)
 		return
 	}
 
 	logger.Info("通过 Web API 切换 Token 状态: %s (enabled=%v)", tokenID, token.Enabled)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "Token status toggled",
-		"token":   token,
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.token_toggled"),
+		Token:   maskToken(w, token),
 	})
 }
 
@@ -220,123 +263,174 @@ func (s *Server) handleToggleToken(w http.ResponseWriter, r *http.Request, token
 func (s *Server) handleRefreshToken(w http.ResponseWriter, r *http.Request, tokenID string) {
 	token, err := s.tokenManager.RefreshSubscription(tokenID)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Failed to refresh subscription: "+err.Error())
+		writeError(w, http.StatusBadRequest, "web.refresh_failed", err)
 		return
 	}
 
 	logger.Info("通过 Web API 刷新 Token 订阅: %s", tokenID)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "Subscription refreshed",
-		"token":   token,
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.subscription_refreshed"),
+		Token:   maskToken(w, token),
+	})
+}
+
+// handleRefreshAllTokens 立即刷新所有启用 Token 的订阅信息
+func (s *Server) handleRefreshAllTokens(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+	if !s.beginAction(w, actionRefreshAll) {
+		return
+	}
+
+	summary, err := s.tokenManager.RefreshAll(s.configMgr.GetConfig().SubscriptionRefresh.Concurrency)
+	if err != nil {
+		// 后台刷新正在进行时本次请求没有执行，不占用冷却
+		if errors.Is(err, token.ErrRefreshInProgress) {
+			s.cooldown.cancel(actionRefreshAll)
+		}
+		writeError(w, http.StatusConflict, "web.refresh_all_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 批量刷新订阅: 成功 %d, 失败 %d", summary.Succeeded, summary.Failed)
+	writeJSON(w, http.StatusOK, webapi.RefreshAllResponse{
+		Success: true,
+		Message: tr(w, "web.refresh_all_completed", summary.Succeeded, summary.Failed),
+		Summary: summary,
 	})
 }
 
 // handleResetToken 手动重置单个 Token
 func (s *Server) handleResetToken(w http.ResponseWriter, r *http.Request, tokenID string) {
-	var req struct {
-		ResetType string `json:"reset_type"` // "first" or "second"
-	}
+	var req webapi.ResetTokenRequest
 
-	if err := readJSON(r, &req); err != nil {
-		// 如果没有请求体，使用默认值
-		req.ResetType = "second"
+	// 没有请求体时使用默认值，其他解析错误（包括请求体过大）直接返回
+	if err := readJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeInvalidJSON(w, err)
+		return
 	}
 
 	if req.ResetType != "first" && req.ResetType != "second" {
 		req.ResetType = "second"
 	}
 
-	// 获取配置以确定阈值
-	cfg := s.configMgr.GetConfig()
-	var threshold float64
-	if req.ResetType == "first" {
-		threshold = cfg.FirstReset.ThresholdPercent
-	} else {
-		threshold = cfg.SecondReset.ThresholdPercent
+	s.extendResetDeadline(w, 1)
+	token, err := s.resetWithSchedule(tokenID, req.ResetType, "")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "web.reset_failed", err)
+		return
 	}
 
-	token, err := s.tokenManager.ResetToken(tokenID, req.ResetType, threshold)
+	logger.Info("通过 Web API 手动重置 Token: %s (type=%s)", tokenID, req.ResetType)
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.reset_completed", reset.ResetTypeLabel(req.ResetType)),
+		Token:   maskToken(w, token),
+	})
+}
+
+// handleUpdateTokenSettings 更新 Token 的单独设置（低额度提醒阈值、上游地址、代理）
+func (s *Server) handleUpdateTokenSettings(w http.ResponseWriter, r *http.Request, tokenID string) {
+	var req webapi.TokenSettingsRequest
+
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+
+	current, err := s.tokenManager.GetToken(tokenID)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "Reset failed: "+err.Error())
+		writeTokenNotFound(w)
 		return
 	}
+	// 原样提交响应中遮蔽后的代理地址时视为未修改，避免用遮蔽值覆盖密码
+	if req.ProxyURL != nil && *req.ProxyURL != current.ProxyURL && *req.ProxyURL == config.MaskProxyURL(current.ProxyURL) {
+		req.ProxyURL = nil
+	}
 
-	logger.Info("通过 Web API 手动重置 Token: %s (type=%s)", tokenID, req.ResetType)
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": fmt.Sprintf("Reset %s completed", req.ResetType),
-		"token":   token,
+	updated, err := s.tokenManager.UpdateTokenSettings(tokenID, token.TokenSettings{
+		LowCreditThreshold: req.LowCreditThreshold,
+		BaseURL:            req.BaseURL,
+		ProxyURL:           req.ProxyURL,
+	})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+		return
+	}
+
+	logger.Info("通过 Web API 更新 Token 设置: %s", tokenID)
+	writeJSON(w, http.StatusOK, webapi.TokenResponse{
+		Success: true,
+		Message: tr(w, "web.token_settings_updated"),
+		Token:   maskToken(w, updated),
 	})
 }
 
 // handleManualReset 手动触发所有 Token 重置
 func (s *Server) handleManualReset(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 		return
 	}
 
-	var req struct {
-		ResetType string `json:"reset_type"` // "first" or "second"
-	}
+	var req webapi.ResetTokenRequest
 
 	if err := readJSON(r, &req); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		writeInvalidJSON(w, err)
 		return
 	}
 
 	if req.ResetType != "first" && req.ResetType != "second" {
-		writeError(w, http.StatusBadRequest, "Invalid reset_type, must be 'first' or 'second'")
+		writeError(w, http.StatusBadRequest, "web.invalid_reset_type")
 		return
 	}
 
-	// 获取配置以确定阈值
-	cfg := s.configMgr.GetConfig()
-	var threshold float64
-	if req.ResetType == "first" {
-		threshold = cfg.FirstReset.ThresholdPercent
-	} else {
-		threshold = cfg.SecondReset.ThresholdPercent
+	// 获取所有启用的 Token，订阅已到期的不再重置
+	tokens, expired := s.tokenManager.ListResettableTokens()
+	if len(tokens)+len(expired) == 0 {
+		writeError(w, http.StatusBadRequest, "web.no_enabled_tokens")
+		return
 	}
-
-	// 获取所有启用的 Token
-	tokens := s.tokenManager.ListEnabledTokens()
-	if len(tokens) == 0 {
-		writeError(w, http.StatusBadRequest, "No enabled tokens")
+	if !s.beginAction(w, actionManualReset) {
 		return
 	}
 
-	// 执行重置
-	type resetResult struct {
-		TokenID string                 `json:"token_id"`
-		Name    string                 `json:"name"`
-		Success bool                   `json:"success"`
-		Message string                 `json:"message"`
-		Before  float64                `json:"before_credits,omitempty"`
-		After   float64                `json:"after_credits,omitempty"`
-		Last    *models.TokenResetRecord `json:"last_reset,omitempty"`
-	}
+	// 执行重置，逐个核实结果
+	s.extendResetDeadline(w, len(tokens))
+	results := make([]webapi.ResetResult, 0, len(tokens)+len(expired))
+	runID := uuid.New().String()
 
-	results := make([]resetResult, 0, len(tokens))
+	for _, token := range expired {
+		results = append(results, webapi.ResetResult{
+			TokenID: token.ID,
+			Name:    token.Name,
+			Code:    "token.subscription_expired",
+			Message: tr(w, "web.subscription_expired_skipped", token.Subscription.EndDate),
+		})
+	}
 
 	for _, token := range tokens {
-		updatedToken, err := s.tokenManager.ResetToken(token.ID, req.ResetType, threshold)
+		updatedToken, err := s.resetWithSchedule(token.ID, req.ResetType, runID)
 
-		result := resetResult{
+		result := webapi.ResetResult{
 			TokenID: token.ID,
 			Name:    token.Name,
 		}
 
 		if err != nil {
 			result.Success = false
-			result.Message = err.Error()
+			result.Code = i18n.CodeOf(err)
+			result.Message = i18n.Localize(err, localeOf(w))
 		} else if updatedToken.LastReset != nil {
 			result.Success = updatedToken.LastReset.Success
-			result.Message = updatedToken.LastReset.Message
+			result.Code = updatedToken.LastReset.Code
+			result.Message = updatedToken.LastReset.Localize(localeOf(w))
 			result.Before = updatedToken.LastReset.BeforeCredits
 			result.After = updatedToken.LastReset.AfterCredits
-			result.Last = updatedToken.LastReset
+			result.Last = localizeResetRecord(updatedToken.LastReset, localeOf(w))
 		}
 
 		results = append(results, result)
@@ -349,53 +443,14 @@ func (s *Server) handleManualReset(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	logger.Info("通过 Web API 手动触发批量重置: type=%s, success=%d/%d", req.ResetType, successCount, len(results))
-
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success":       true,
-		"reset_type":    req.ResetType,
-		"total":         len(results),
-		"success_count": successCount,
-		"results":       results,
-	})
-}
-
-// handleSystemLogs 系统日志管理
-func (s *Server) handleSystemLogs(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		s.handleGetSystemLogs(w, r)
-	case http.MethodDelete:
-		s.handleClearSystemLogs(w, r)
-	default:
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
-	}
-}
-
-// handleGetSystemLogs 获取系统日志
-func (s *Server) handleGetSystemLogs(w http.ResponseWriter, r *http.Request) {
-	logs, err := s.storage.LoadSystemLogs()
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to load system logs: "+err.Error())
-		return
-	}
-
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"logs":  logs.Logs,
-		"count": len(logs.Logs),
-	})
-}
-
-// handleClearSystemLogs 清空系统日志
-func (s *Server) handleClearSystemLogs(w http.ResponseWriter, r *http.Request) {
-	if err := s.storage.ClearSystemLogs(); err != nil {
-		writeError(w, http.StatusInternalServerError, "Failed to clear system logs: "+err.Error())
-		return
-	}
+	logger.Info("通过 Web API 手动触发批量重置: type=%s, run=%s, success=%d/%d", req.ResetType, runID, successCount, len(results))
 
-	logger.Info("通过 Web API 清空系统日志")
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "System logs cleared successfully",
+	writeJSON(w, http.StatusOK, webapi.ManualResetResponse{
+		Success:      true,
+		RunID:        runID,
+		ResetType:    req.ResetType,
+		Total:        len(results),
+		SuccessCount: successCount,
+		Results:      results,
 	})
 }
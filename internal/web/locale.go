@@ -0,0 +1,58 @@
+package web
+
+import (
+	"net/http"
+
+	"code88reset/internal/i18n"
+)
+
+// localeResponseWriter 记录请求使用的语言，错误和提示消息据此渲染
+type localeResponseWriter struct {
+	http.ResponseWriter
+	locale i18n.Locale
+}
+
+// Unwrap 返回原始 ResponseWriter，供 http.ResponseController 使用
+func (w *localeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// withLocale 选择响应语言：?lang= 参数优先，其次 Accept-Language，最后使用配置的默认语言
+func withLocale(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := requestLocale(r)
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", string(locale))
+		handler.ServeHTTP(&localeResponseWriter{ResponseWriter: w, locale: locale}, r)
+	})
+}
+
+// requestLocale 解析请求指定的语言
+func requestLocale(r *http.Request) i18n.Locale {
+	if locale, ok := i18n.Parse(r.URL.Query().Get("lang")); ok {
+		return locale
+	}
+	if locale, ok := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language")); ok {
+		return locale
+	}
+	return i18n.Default()
+}
+
+// localeOf 响应使用的语言（支持被其他中间件再次包装），未经 withLocale 时使用默认语言
+func localeOf(w http.ResponseWriter) i18n.Locale {
+	for {
+		if lw, ok := w.(*localeResponseWriter); ok {
+			return lw.locale
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return i18n.Default()
+		}
+		w = u.Unwrap()
+	}
+}
+
+// tr 按响应语言渲染消息
+func tr(w http.ResponseWriter, code string, args ...interface{}) string {
+	return i18n.T(localeOf(w), code, args...)
+}
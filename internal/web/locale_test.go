@@ -0,0 +1,64 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/webapi"
+)
+
+func TestWithLocale(t *testing.T) {
+	handler := withLocale(withV1(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "web.not_found")
+	})))
+
+	serve := func(target, acceptLanguage string) (*httptest.ResponseRecorder, webapi.APIError) {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var envelope webapi.ErrorEnvelope
+		if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		return rec, envelope.Error
+	}
+
+	rec, apiErr := serve("/api/v1/missing", "en-US,en;q=0.9")
+	if apiErr.Message != "Not found" || apiErr.MessageCode != "web.not_found" || rec.Header().Get("Content-Language") != "en" {
+		t.Errorf("Accept-Language en: %+v, Content-Language %q", apiErr, rec.Header().Get("Content-Language"))
+	}
+	if _, apiErr = serve("/api/v1/missing?lang=zh-CN", "en"); apiErr.Message != "接口不存在" {
+		t.Errorf("lang parameter should take precedence, got %q", apiErr.Message)
+	}
+
+	i18n.SetDefault(i18n.EN)
+	defer i18n.SetDefault(i18n.ZhCN)
+	if _, apiErr = serve("/api/v1/missing", "fr"); apiErr.Message != "Not found" {
+		t.Errorf("unsupported language should use the default locale, got %q", apiErr.Message)
+	}
+}
+
+func TestErrorMessageCodeFromError(t *testing.T) {
+	handler := withLocale(withV1(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, i18n.Errorf("token.not_found", "t1"))
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tokens/t1", nil)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var envelope webapi.ErrorEnvelope
+	if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Error.MessageCode != "token.not_found" || envelope.Error.Message != "Token not found: t1" {
+		t.Errorf("envelope = %+v", envelope.Error)
+	}
+}
@@ -0,0 +1,240 @@
+package web
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+const (
+	defaultLogPageSize = 100
+	maxLogPageSize     = 1000
+)
+
+// handleSystemLogs 系统日志管理
+func (s *Server) handleSystemLogs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.handleGetSystemLogs(w, r)
+	case http.MethodDelete:
+		s.handleClearSystemLogs(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+	}
+}
+
+// handleGetSystemLogs 分页查询系统日志
+//
+// 支持的查询参数: type（可逗号分隔多个）、token_id、run_id、since、until、q、page、page_size
+func (s *Server) handleGetSystemLogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSystemLogQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+		return
+	}
+
+	pageSize := defaultLogPageSize
+	if v := r.URL.Query().Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "web.invalid_page_size")
+			return
+		}
+		pageSize = n
+	}
+	if pageSize > maxLogPageSize {
+		pageSize = maxLogPageSize
+	}
+
+	page := 1
+	if v := r.URL.Query().Get("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "web.invalid_page")
+			return
+		}
+		page = n
+	}
+
+	query.Offset = (page - 1) * pageSize
+	query.Limit = pageSize
+
+	result, err := s.storage.QuerySystemLogs(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.load_logs_failed", err)
+		return
+	}
+
+	totalPages := (result.Total + pageSize - 1) / pageSize
+	writeJSON(w, http.StatusOK, webapi.SystemLogsResponse{
+		Logs:       localizeSystemLogs(result.Logs, localeOf(w)),
+		Count:      len(result.Logs),
+		Total:      result.Total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	})
+}
+
+// handleClearSystemLogs 清空系统日志，带过滤参数时只删除匹配的日志
+func (s *Server) handleClearSystemLogs(w http.ResponseWriter, r *http.Request) {
+	query, err := parseSystemLogQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+		return
+	}
+
+	if query.IsEmpty() {
+		if err := s.storage.ClearSystemLogs(); err != nil {
+			writeError(w, http.StatusInternalServerError, "web.clear_logs_failed", err)
+			return
+		}
+
+		logger.Info("通过 Web API 清空系统日志")
+		writeJSON(w, http.StatusOK, webapi.MessageResponse{
+			Success: true,
+			Message: tr(w, "web.logs_cleared"),
+		})
+		return
+	}
+
+	removed, err := s.storage.DeleteSystemLogs(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.delete_logs_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 删除系统日志: %d 条", removed)
+	writeJSON(w, http.StatusOK, webapi.DeleteSystemLogsResponse{
+		Success: true,
+		Message: tr(w, "web.logs_deleted", removed),
+		Removed: removed,
+	})
+}
+
+// handleExportSystemLogs 导出系统日志（format=csv|ndjson），支持与查询相同的过滤参数
+func (s *Server) handleExportSystemLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	query, err := parseSystemLogQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "ndjson" {
+		writeError(w, http.StatusBadRequest, "web.invalid_log_format")
+		return
+	}
+
+	result, err := s.storage.QuerySystemLogs(query)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.load_logs_failed", err)
+		return
+	}
+
+	logs := localizeSystemLogs(result.Logs, localeOf(w))
+	filename := fmt.Sprintf("system_logs_%s.%s", time.Now().Format("20060102_150405"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch format {
+	case "ndjson":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+		enc := json.NewEncoder(w)
+		for _, log := range logs {
+			if err := enc.Encode(log); err != nil {
+				logger.Error("导出系统日志失败: %v", err)
+				return
+			}
+		}
+	default:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"timestamp", "type", "token_id", "run_id", "message"})
+		for _, log := range logs {
+			cw.Write([]string{
+				log.Timestamp.Format(time.RFC3339),
+				log.Type,
+				log.TokenID,
+				log.RunID,
+				log.Message,
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			logger.Error("导出系统日志失败: %v", err)
+		}
+	}
+}
+
+// localizeSystemLogs 按语言渲染带消息码的系统日志
+func localizeSystemLogs(logs []models.SystemLog, locale i18n.Locale) []models.SystemLog {
+	localized := make([]models.SystemLog, len(logs))
+	for i, log := range logs {
+		log.Message = log.Localize(locale)
+		localized[i] = log
+	}
+	return localized
+}
+
+// parseSystemLogQuery 从请求参数解析日志过滤条件（不含分页）
+func parseSystemLogQuery(r *http.Request) (storage.SystemLogQuery, error) {
+	values := r.URL.Query()
+	query := storage.SystemLogQuery{
+		TokenID: strings.TrimSpace(values.Get("token_id")),
+		RunID:   strings.TrimSpace(values.Get("run_id")),
+		Search:  strings.TrimSpace(values.Get("q")),
+	}
+
+	for _, raw := range values["type"] {
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" && t != "all" {
+				query.Types = append(query.Types, t)
+			}
+		}
+	}
+
+	if v := values.Get("since"); v != "" {
+		t, err := parseLogTime(v)
+		if err != nil {
+			return query, i18n.Errorf("web.invalid_since", err)
+		}
+		query.Since = &t
+	}
+	if v := values.Get("until"); v != "" {
+		t, err := parseLogTime(v)
+		if err != nil {
+			return query, i18n.Errorf("web.invalid_until", err)
+		}
+		query.Until = &t
+	}
+
+	return query, nil
+}
+
+// parseLogTime 解析 RFC3339 时间或 YYYY-MM-DD 日期（按本地时区）
+func parseLogTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02", value, time.Local)
+}
@@ -0,0 +1,118 @@
+package web
+
+import (
+	"net/http"
+
+	"code88reset/internal/config"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// maskToken 返回 API Key 和代理密码已遮蔽的副本，Web 响应中的 Token 都经过这里；重置结果按响应语言渲染
+func maskToken(w http.ResponseWriter, t *models.Token) *models.Token {
+	if t == nil {
+		return nil
+	}
+	masked := *t
+	masked.APIKey = config.MaskAPIKey(t.APIKey)
+	masked.ProxyURL = config.MaskProxyURL(t.ProxyURL)
+	masked.LastReset = localizeResetRecord(t.LastReset, localeOf(w))
+	return &masked
+}
+
+// maskTokens 遮蔽 Token 列表的 API Key
+func maskTokens(w http.ResponseWriter, tokens []*models.Token) []*models.Token {
+	masked := make([]*models.Token, len(tokens))
+	for i, t := range tokens {
+		masked[i] = maskToken(w, t)
+	}
+	return masked
+}
+
+// maskDuplicateGroups 遮蔽重复 Token 分组中的 API Key
+func maskDuplicateGroups(w http.ResponseWriter, groups []token.DuplicateGroup) []token.DuplicateGroup {
+	masked := make([]token.DuplicateGroup, len(groups))
+	for i, g := range groups {
+		masked[i] = token.DuplicateGroup{Reasons: g.Reasons, Tokens: maskTokens(w, g.Tokens)}
+	}
+	return masked
+}
+
+// maskConfig 返回代理密码、自定义请求头和 Webhook 地址已遮蔽的配置副本，配置响应都经过这里
+func maskConfig(cfg models.DynamicConfig) models.DynamicConfig {
+	cfg.HTTPClient.ProxyURL = config.MaskProxyURL(cfg.HTTPClient.ProxyURL)
+	cfg.HTTPClient.Headers = config.MaskHeaders(cfg.HTTPClient.Headers)
+	cfg.Notifications.WebhookURL = config.MaskWebhookURL(cfg.Notifications.WebhookURL)
+	return cfg
+}
+
+// unmaskConfig 原样提交的遮蔽值视为未修改，换回当前配置中的原值，避免用遮蔽值覆盖密钥
+func unmaskConfig(cfg *models.DynamicConfig, current models.DynamicConfig) {
+	if proxy := current.HTTPClient.ProxyURL; cfg.HTTPClient.ProxyURL != proxy && cfg.HTTPClient.ProxyURL == config.MaskProxyURL(proxy) {
+		cfg.HTTPClient.ProxyURL = proxy
+	}
+	if webhook := current.Notifications.WebhookURL; cfg.Notifications.WebhookURL != webhook && cfg.Notifications.WebhookURL == config.MaskWebhookURL(webhook) {
+		cfg.Notifications.WebhookURL = webhook
+	}
+	maskedHeaders := config.MaskHeaders(current.HTTPClient.Headers)
+	for name, value := range cfg.HTTPClient.Headers {
+		if original, ok := current.HTTPClient.Headers[name]; ok && value != original && value == maskedHeaders[name] {
+			cfg.HTTPClient.Headers[name] = original
+		}
+	}
+}
+
+// localizeResetRecord 返回按语言渲染结果说明和规则判定说明的重置记录副本
+func localizeResetRecord(record *models.TokenResetRecord, locale i18n.Locale) *models.TokenResetRecord {
+	if record == nil {
+		return nil
+	}
+	localized := *record
+	localized.Message = record.Localize(locale)
+	if len(record.Decisions) > 0 {
+		localized.Decisions = make([]models.RuleDecision, len(record.Decisions))
+		for i, d := range record.Decisions {
+			d.Message = d.Localize(locale)
+			localized.Decisions[i] = d
+		}
+	}
+	return &localized
+}
+
+// defaultTokenName 未指定名称时按 API Key 可显示的前缀命名，Key 过短时使用随机后缀
+func defaultTokenName(apiKey string) string {
+	if prefix := config.VisibleAPIKeyPrefix(apiKey); prefix != "" {
+		return "Token " + prefix
+	}
+	return "Token " + uuid.New().String()[:8]
+}
+
+// handleRevealAPIKey 返回 Token 的完整 API Key 和代理地址，每次调用都记录审计日志
+func (s *Server) handleRevealAPIKey(w http.ResponseWriter, r *http.Request, tokenID string) {
+	t, err := s.tokenManager.GetToken(tokenID)
+	if err != nil {
+		writeTokenNotFound(w)
+		return
+	}
+
+	entry := models.SystemLog{Type: "audit", TokenID: t.ID}
+	entry.SetMessage(i18n.M("web.audit.reveal_api_key", t.Name, s.clientIP(r)))
+	logger.Warn("%s", entry.Message)
+	if err := s.storage.AddSystemLogEntry(entry); err != nil {
+		// 无法留下审计记录时不返回 Key
+		writeError(w, http.StatusInternalServerError, "web.audit_log_failed", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, webapi.RevealAPIKeyResponse{
+		TokenID:  t.ID,
+		APIKey:   t.APIKey,
+		ProxyURL: t.ProxyURL,
+	})
+}
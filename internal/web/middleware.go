@@ -2,32 +2,51 @@ package web
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"code88reset/internal/i18n"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
 	"code88reset/pkg/logger"
 )
 
-// withAuth 认证中间件
+// defaultContentSecurityPolicy 内置界面默认的内容安全策略：脚本和样式只允许本站及界面使用的 CDN
+const defaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'unsafe-inline' https://cdn.tailwindcss.com; " +
+	"style-src 'self' 'unsafe-inline' https://cdnjs.cloudflare.com https://fonts.googleapis.com; " +
+	"font-src 'self' data: https://cdnjs.cloudflare.com https://fonts.gstatic.com; " +
+	"img-src 'self' data:; connect-src 'self'; " +
+	"frame-ancestors 'none'; base-uri 'self'; form-action 'self'"
+
+// withAuth 认证中间件：管理员令牌可访问全部接口，服务令牌只能访问其权限覆盖的接口
 func (s *Server) withAuth(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 检查 Authorization 头
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			writeError(w, http.StatusUnauthorized, "Missing authorization header")
+			writeError(w, http.StatusUnauthorized, "web.missing_authorization")
 			return
 		}
 
 		// 检查 Bearer token
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			writeError(w, http.StatusUnauthorized, "Invalid authorization header format")
+			writeError(w, http.StatusUnauthorized, "web.invalid_authorization")
 			return
 		}
 
 		token := parts[1]
+		credential := "admin"
 		if token != s.adminToken {
-			writeError(w, http.StatusUnauthorized, "Invalid token")
+			st := s.authorizeServiceToken(w, r, token)
+			if st == nil {
+				return
+			}
+			credential = "service:" + st.ID
+		}
+		if !s.allowCredential(w, credential) {
 			return
 		}
 
@@ -36,12 +55,17 @@ func (s *Server) withAuth(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// withCORS CORS 中间件
+// withCORS CORS 中间件，只对配置中允许的来源返回跨域响应头
 func (s *Server) withCORS(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if origin := r.Header.Get("Origin"); origin != "" {
+			w.Header().Add("Vary", "Origin")
+			if allowed, ok := s.allowedOrigin(origin); ok {
+				w.Header().Set("Access-Control-Allow-Origin", allowed)
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			}
+		}
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusOK)
@@ -52,10 +76,42 @@ func (s *Server) withCORS(handler http.Handler) http.Handler {
 	})
 }
 
+// allowedOrigin 判断跨域来源是否允许，返回应写入 Access-Control-Allow-Origin 的值
+func (s *Server) allowedOrigin(origin string) (string, bool) {
+	for _, allowed := range s.webCfg.AllowedOrigins {
+		if allowed == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
+
+// withSecurityHeaders 为所有响应添加安全相关的响应头，HTTPS 请求（含可信代理转发的）额外发送 HSTS
+func (s *Server) withSecurityHeaders(handler http.Handler) http.Handler {
+	csp := s.webCfg.ContentSecurityPolicy
+	if csp == "" {
+		csp = defaultContentSecurityPolicy
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Content-Security-Policy", csp)
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("Referrer-Policy", "no-referrer")
+		if s.webCfg.HSTSMaxAge > 0 && s.isHTTPS(r) {
+			h.Set("Strict-Transport-Security", fmt.Sprintf("max-age=%d; includeSubDomains", s.webCfg.HSTSMaxAge))
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
 // withLogging 日志中间件
 func (s *Server) withLogging(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		logger.Debug("HTTP %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
+		logger.Debug("HTTP %s %s from %s", r.Method, r.URL.Path, s.clientIP(r))
 		handler.ServeHTTP(w, r)
 	})
 }
@@ -70,15 +126,108 @@ func writeJSON(w http.ResponseWriter, status int, data interface{}) {
 	}
 }
 
-// writeError 写入错误响应
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]interface{}{
-		"error":   true,
-		"message": message,
+// writeError 写入错误响应，错误码按状态码推断，消息按响应语言渲染
+func writeError(w http.ResponseWriter, status int, messageCode string, args ...interface{}) {
+	writeErrorCode(w, status, defaultErrorCode(status), nil, messageCode, args...)
+}
+
+// writeErrorCode 写入带错误码的错误响应：/api/v1 使用统一的错误信封，旧版路由保持原有格式
+//
+// messageCode 为消息目录中的消息码；直接返回底层错误时使用 i18n.TextCode，信封中的消息码取自该错误。
+func writeErrorCode(w http.ResponseWriter, status int, code string, details map[string]interface{}, messageCode string, args ...interface{}) {
+	message := tr(w, messageCode, args...)
+	if isV1(w) {
+		writeJSON(w, status, webapi.ErrorEnvelope{
+			Error: webapi.APIError{
+				Code:        code,
+				MessageCode: errorMessageCode(messageCode, args),
+				Message:     message,
+				Details:     details,
+			},
+		})
+		return
+	}
+
+	writeJSON(w, status, webapi.ErrorResponse{
+		Error:   true,
+		Message: message,
+		Code:    code,
+	})
+}
+
+// errorMessageCode 错误响应的消息码，原样返回的错误使用其自身的消息码
+func errorMessageCode(messageCode string, args []interface{}) string {
+	if messageCode != i18n.TextCode {
+		return messageCode
+	}
+	if len(args) == 1 {
+		if err, ok := args[0].(error); ok {
+			return i18n.CodeOf(err)
+		}
+	}
+	return ""
+}
+
+// defaultErrorCode 状态码对应的默认错误码
+func defaultErrorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return webapi.CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return webapi.CodeUnauthorized
+	case http.StatusForbidden:
+		return webapi.CodeForbidden
+	case http.StatusNotFound:
+		return webapi.CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return webapi.CodeMethodNotAllowed
+	case http.StatusConflict:
+		return webapi.CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return webapi.CodeRequestTooLarge
+	case http.StatusTooManyRequests:
+		return webapi.CodeRateLimited
+	default:
+		return webapi.CodeInternal
+	}
+}
+
+// writeInvalidJSON 请求体不是合法 JSON，超过大小上限时返回 413
+func writeInvalidJSON(w http.ResponseWriter, err error) {
+	if limit, ok := isBodyTooLarge(err); ok {
+		writeRequestTooLarge(w, limit)
+		return
+	}
+	writeErrorCode(w, http.StatusBadRequest, webapi.CodeInvalidJSON, nil, "web.invalid_json", err)
+}
+
+// writeTokenNotFound Token 不存在
+func writeTokenNotFound(w http.ResponseWriter) {
+	writeErrorCode(w, http.StatusNotFound, webapi.CodeTokenNotFound, nil, "web.token_not_found")
+}
+
+// writeDuplicateError 添加的 Token 与已有 Token 重复
+func writeDuplicateError(w http.ResponseWriter, err error, dup *token.DuplicateError) {
+	reason := tr(w, "token.duplicate_reason."+dup.ReasonCode)
+	if isV1(w) {
+		writeErrorCode(w, http.StatusConflict, webapi.CodeDuplicateToken, map[string]interface{}{
+			"existing_token_id":   dup.Existing.ID,
+			"existing_token_name": dup.Existing.Name,
+			"reason":              reason,
+			"reason_code":         dup.ReasonCode,
+		}, "web.duplicate_token", err)
+		return
+	}
+
+	writeJSON(w, http.StatusConflict, webapi.DuplicateTokenResponse{
+		Error:             tr(w, "web.duplicate_token", err),
+		ExistingTokenID:   dup.Existing.ID,
+		ExistingTokenName: dup.Existing.Name,
+		Reason:            reason,
 	})
 }
 
-// readJSON 读取 JSON 请求
+// readJSON 读取 JSON 请求，请求体大小由 withLimits 限制
 func readJSON(r *http.Request, v interface{}) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"code88reset/pkg/logger"
+)
+
+// withBasePath 在 URL 前缀下提供全部路由，访问前缀本身时重定向到带斜杠的地址
+//
+// 内置界面使用相对路径访问 API，页面地址必须以斜杠结尾。
+func withBasePath(basePath string, handler http.Handler) http.Handler {
+	strip := http.StripPrefix(basePath, handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == basePath:
+			target := basePath + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+		case strings.HasPrefix(r.URL.Path, basePath+"/"):
+			strip.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// parseTrustedProxies 解析可信代理列表，支持单个 IP 和 CIDR，无效项记录警告后忽略
+func parseTrustedProxies(entries []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil {
+				bits := 8 * net.IPv6len
+				if ip4 := ip.To4(); ip4 != nil {
+					ip, bits = ip4, 8*net.IPv4len
+				}
+				nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+				continue
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			logger.Warn("忽略无效的可信代理地址: %s", entry)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+// isTrustedProxy 判断地址是否属于可信代理
+func (s *Server) isTrustedProxy(ip net.IP) bool {
+	for _, n := range s.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP 直接连接方的 IP
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientIP 客户端真实 IP：直接连接方是可信代理时，从 X-Forwarded-For 末尾向前取第一个非可信代理的地址
+func (s *Server) clientIP(r *http.Request) string {
+	addr := remoteIP(r)
+	ip := net.ParseIP(addr)
+	if ip == nil || !s.isTrustedProxy(ip) {
+		return addr
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		hopIP := net.ParseIP(hop)
+		if hopIP == nil {
+			// 无法解析的值不可信，停在最后一个可信代理
+			return addr
+		}
+		if !s.isTrustedProxy(hopIP) {
+			return hop
+		}
+		addr = hop
+	}
+	return addr
+}
+
+// isHTTPS 请求是否经由 HTTPS 到达：直接 TLS 连接，或可信代理通过 X-Forwarded-Proto 声明
+func (s *Server) isHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	ip := net.ParseIP(remoteIP(r))
+	if ip == nil || !s.isTrustedProxy(ip) {
+		return false
+	}
+	proto := strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-Proto"), ",")[0])
+	return strings.EqualFold(proto, "https")
+}
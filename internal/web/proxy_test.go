@@ -0,0 +1,91 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code88reset/internal/models"
+)
+
+func TestWithBasePath(t *testing.T) {
+	handler := withBasePath("/reset", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.Path))
+	}))
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	if rec := serve("/reset?tab=logs"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/reset/?tab=logs" {
+		t.Errorf("base path redirect = %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := serve("/reset/api/v1/status"); rec.Code != http.StatusOK || rec.Body.String() != "/api/v1/status" {
+		t.Errorf("prefixed request = %d %q", rec.Code, rec.Body.String())
+	}
+	for _, target := range []string{"/api/v1/status", "/resetx/api/v1/status"} {
+		if rec := serve(target); rec.Code != http.StatusNotFound {
+			t.Errorf("%s = %d, want 404", target, rec.Code)
+		}
+	}
+}
+
+func TestClientIPAndHTTPS(t *testing.T) {
+	s := &Server{trustedProxies: parseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "invalid"})}
+	if len(s.trustedProxies) != 2 {
+		t.Fatalf("trustedProxies = %v", s.trustedProxies)
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		proto      string
+		wantIP     string
+		wantHTTPS  bool
+	}{
+		{"direct", "203.0.113.5:1234", "", "", "203.0.113.5", false},
+		{"untrusted forwarder", "203.0.113.5:1234", "198.51.100.7", "https", "203.0.113.5", false},
+		{"trusted proxy", "10.1.2.3:1234", "198.51.100.7", "https", "198.51.100.7", true},
+		{"proxy chain", "10.1.2.3:1234", "1.1.1.1, 198.51.100.7, 192.168.1.1", "https", "198.51.100.7", true},
+		{"spoofed header", "10.1.2.3:1234", "garbage, 10.0.0.9", "http", "10.0.0.9", false},
+		{"no header", "192.168.1.1:1234", "", "", "192.168.1.1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			if got := s.clientIP(req); got != tt.wantIP {
+				t.Errorf("clientIP = %q, want %q", got, tt.wantIP)
+			}
+			if got := s.isHTTPS(req); got != tt.wantHTTPS {
+				t.Errorf("isHTTPS = %v, want %v", got, tt.wantHTTPS)
+			}
+		})
+	}
+}
+
+func TestHSTSBehindTrustedProxy(t *testing.T) {
+	s := &Server{
+		webCfg:         models.WebServerConfig{HSTSMaxAge: 60},
+		trustedProxies: parseTrustedProxies([]string{"127.0.0.1"}),
+	}
+	handler := s.withSecurityHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Strict-Transport-Security") == "" {
+		t.Error("HSTS should be sent when a trusted proxy terminates TLS")
+	}
+}
@@ -0,0 +1,208 @@
+package web
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// uploadPaths 允许较大请求体的接口（映射后的 /api 路径）
+var uploadPaths = map[string]bool{
+	"/api/tokens/import":  true,
+	"/api/backup/restore": true,
+}
+
+// 耗时操作的冷却键
+const (
+	actionManualReset = "reset:trigger"
+	actionRefreshAll  = "tokens:refresh-all"
+)
+
+// rateLimiter 令牌桶限流：每个键每分钟最多 limit 次，允许短时间内用完整桶
+type rateLimiter struct {
+	limit int
+
+	mu        sync.Mutex
+	buckets   map[string]*rateBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type rateBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// newRateLimiter 创建限流器，limit 为 0 时返回 nil 表示不限制
+func newRateLimiter(limit int) *rateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	return &rateLimiter{limit: limit, buckets: make(map[string]*rateBucket), now: time.Now}
+}
+
+// allow 消耗一次额度，超限时返回需要等待的时间
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	rate := float64(l.limit) / time.Minute.Seconds() // 每秒恢复的次数
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rateBucket{tokens: float64(l.limit), updated: now}
+		l.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(l.limit), b.tokens+now.Sub(b.updated).Seconds()*rate)
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep 定期清理已恢复满额的桶，避免大量来源 IP 占用内存，调用方需持有锁
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// actionCooldown 耗时操作两次执行之间的最小间隔，所有调用方共享
+type actionCooldown struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]time.Time
+	now  func() time.Time
+}
+
+// newActionCooldown 创建冷却控制，interval 为 0 时返回 nil 表示不限制
+func newActionCooldown(interval time.Duration) *actionCooldown {
+	if interval <= 0 {
+		return nil
+	}
+	return &actionCooldown{interval: interval, last: make(map[string]time.Time), now: time.Now}
+}
+
+// begin 开始执行操作，仍在冷却中时返回剩余时间
+func (c *actionCooldown) begin(action string) (bool, time.Duration) {
+	if c == nil {
+		return true, 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if last, ok := c.last[action]; ok {
+		if remaining := c.interval - now.Sub(last); remaining > 0 {
+			return false, remaining
+		}
+	}
+	c.last[action] = now
+	return true, 0
+}
+
+// cancel 操作未真正执行时归还冷却，begin 成功时上一次执行已超过冷却时间，直接删除即可
+func (c *actionCooldown) cancel(action string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.last, action)
+}
+
+// withLimits 管理 API 的按 IP 限流和请求体大小限制，需放在 withV1 之内以使用对应的错误格式
+func (s *Server) withLimits(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := s.clientIP(r)
+		if ok, wait := s.ipLimiter.allow(ip); !ok {
+			logger.Warn("客户端 %s 请求过于频繁: %s %s", ip, r.Method, r.URL.Path)
+			writeRateLimited(w, wait, "web.rate_limited")
+			return
+		}
+
+		limit := s.webCfg.MaxBodyBytes
+		if uploadPaths[r.URL.Path] {
+			limit = s.webCfg.MaxUploadBytes
+		}
+		if limit > 0 {
+			if r.ContentLength > int64(limit) {
+				writeRequestTooLarge(w, int64(limit))
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, int64(limit))
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// allowCredential 按令牌限流，key 区分管理员令牌和各个服务令牌
+func (s *Server) allowCredential(w http.ResponseWriter, key string) bool {
+	if ok, wait := s.tokenLimiter.allow(key); !ok {
+		writeRateLimited(w, wait, "web.rate_limited_token")
+		return false
+	}
+	return true
+}
+
+// beginAction 检查耗时操作的冷却时间，仍在冷却中时写入 429 并返回 false
+func (s *Server) beginAction(w http.ResponseWriter, action string) bool {
+	if ok, wait := s.cooldown.begin(action); !ok {
+		writeRateLimited(w, wait, "web.action_cooling_down", action)
+		return false
+	}
+	return true
+}
+
+// writeRateLimited 返回 429，Retry-After 向上取整到秒
+func writeRateLimited(w http.ResponseWriter, wait time.Duration, messageCode string, args ...interface{}) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeErrorCode(w, http.StatusTooManyRequests, webapi.CodeRateLimited,
+		map[string]interface{}{"retry_after_seconds": seconds}, messageCode, args...)
+}
+
+// writeRequestTooLarge 请求体超过大小上限
+func writeRequestTooLarge(w http.ResponseWriter, limit int64) {
+	writeErrorCode(w, http.StatusRequestEntityTooLarge, webapi.CodeRequestTooLarge,
+		map[string]interface{}{"limit_bytes": limit}, "web.request_too_large", limit)
+}
+
+// isBodyTooLarge 判断读取请求体的错误是否因超过大小上限
+func isBodyTooLarge(err error) (int64, bool) {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return maxErr.Limit, true
+	}
+	return 0, false
+}
@@ -0,0 +1,132 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+	"code88reset/internal/webapi"
+)
+
+func TestRateLimiter_RefillsOverTime(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("a"); !ok {
+			t.Fatalf("request %d should be allowed", i+1)
+		}
+	}
+	ok, wait := l.allow("a")
+	if ok || wait <= 0 || wait > 30*time.Second {
+		t.Fatalf("third request: ok=%v wait=%v, want rejected with wait <= 30s", ok, wait)
+	}
+	if ok, _ := l.allow("b"); !ok {
+		t.Fatal("other keys must have their own bucket")
+	}
+
+	now = now.Add(wait)
+	if ok, _ := l.allow("a"); !ok {
+		t.Fatal("request after Retry-After should be allowed")
+	}
+
+	if ok, _ := (*rateLimiter)(nil).allow("a"); !ok {
+		t.Fatal("nil limiter must not limit")
+	}
+}
+
+func TestActionCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newActionCooldown(time.Minute)
+	c.now = func() time.Time { return now }
+
+	if ok, _ := c.begin(actionManualReset); !ok {
+		t.Fatal("first run should be allowed")
+	}
+	now = now.Add(20 * time.Second)
+	if ok, remaining := c.begin(actionManualReset); ok || remaining != 40*time.Second {
+		t.Fatalf("second run: ok=%v remaining=%v", ok, remaining)
+	}
+	if ok, _ := c.begin(actionRefreshAll); !ok {
+		t.Fatal("cooldown is per action")
+	}
+	now = now.Add(40 * time.Second)
+	if ok, _ := c.begin(actionManualReset); !ok {
+		t.Fatal("run after cooldown should be allowed")
+	}
+	c.cancel(actionManualReset)
+	if ok, _ := c.begin(actionManualReset); !ok {
+		t.Fatal("cancelled run should not start a cooldown")
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	s := &Server{
+		webCfg:    models.WebServerConfig{MaxBodyBytes: 16, MaxUploadBytes: 64},
+		ipLimiter: newRateLimiter(3),
+	}
+	handler := withV1(s.withLimits(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var v map[string]interface{}
+		if err := readJSON(r, &v); err != nil {
+			writeInvalidJSON(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	serve := func(path, body string, chunked bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	large := `{"name":"` + strings.Repeat("x", 32) + `"}`
+	if rec := serve("/api/v1/tokens", large, false); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("declared large body = %d, want 413", rec.Code)
+	}
+	rec := serve("/api/v1/tokens", large, true)
+	var envelope webapi.ErrorEnvelope
+	if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil || rec.Code != http.StatusRequestEntityTooLarge || envelope.Error.Code != webapi.CodeRequestTooLarge {
+		t.Errorf("streamed large body = %d %+v", rec.Code, envelope)
+	}
+	if rec := serve("/api/v1/backup/restore", large, false); rec.Code != http.StatusNoContent {
+		t.Errorf("upload endpoint = %d, want the larger limit to apply", rec.Code)
+	}
+
+	rec = serve("/api/v1/tokens", "{}", false)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("fourth request = %d Retry-After=%q, want 429", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if err := json.NewDecoder(bytes.NewReader(rec.Body.Bytes())).Decode(&envelope); err != nil || envelope.Error.Code != webapi.CodeRateLimited {
+		t.Errorf("rate limited envelope = %+v", envelope)
+	}
+}
+
+func TestWithAuth_LimitsPerCredential(t *testing.T) {
+	s := &Server{adminToken: "secret", tokenLimiter: newRateLimiter(1)}
+	handler := s.withAuth(func(w http.ResponseWriter, r *http.Request) {})
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/status", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("first request = %d", code)
+	}
+	if code := serve(); code != http.StatusTooManyRequests {
+		t.Fatalf("second request = %d, want 429", code)
+	}
+}
@@ -0,0 +1,130 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/report"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// handleReports 列出或立即生成重置报告
+//
+// GET /api/reports?period= 列出已生成的报告；POST /api/reports {"period": "daily"} 立即生成截至当前的报告。
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	if s.reports == nil {
+		writeError(w, http.StatusNotFound, "web.reports_disabled")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		period := r.URL.Query().Get("period")
+		if period != "" && !validReportPeriod(period) {
+			writeError(w, http.StatusBadRequest, "web.invalid_report_period")
+			return
+		}
+		infos, err := s.reports.List(period)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "web.load_reports_failed", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, webapi.ReportListResponse{Reports: infos, Count: len(infos)})
+	case http.MethodPost:
+		s.handleGenerateReport(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+	}
+}
+
+// handleGenerateReport 立即生成重置报告，同一天的同类报告会被覆盖
+func (s *Server) handleGenerateReport(w http.ResponseWriter, r *http.Request) {
+	var req webapi.GenerateReportRequest
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+	if !validReportPeriod(req.Period) {
+		writeError(w, http.StatusBadRequest, "web.invalid_report_period")
+		return
+	}
+
+	rep, err := s.reports.Generate(req.Period, time.Now(), s.reportLocation())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.generate_report_failed", err)
+		return
+	}
+
+	logger.Info("通过 Web API 生成报告: %s", rep.Name)
+	writeJSON(w, http.StatusOK, webapi.GenerateReportResponse{
+		Success: true,
+		Message: tr(w, "web.report_generated", rep.Name),
+		Report:  rep,
+	})
+}
+
+// handleReportDetail 获取单份报告
+//
+// GET /api/reports/{name}?format=json|markdown|html，Markdown 和 HTML 按请求语言重新渲染。
+func (s *Server) handleReportDetail(w http.ResponseWriter, r *http.Request) {
+	if s.reports == nil {
+		writeError(w, http.StatusNotFound, "web.reports_disabled")
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = report.FormatJSON
+	}
+	if _, ok := report.ContentTypes[format]; !ok {
+		writeError(w, http.StatusBadRequest, "web.invalid_report_format")
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/reports/")
+	rep, err := s.reports.Get(name)
+	if errors.Is(err, report.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "web.report_not_found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.load_reports_failed", err)
+		return
+	}
+
+	if format == report.FormatJSON {
+		writeJSON(w, http.StatusOK, rep)
+		return
+	}
+
+	data, err := report.Render(rep, format, localeOf(w), s.reportLocation())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, i18n.TextCode, err)
+		return
+	}
+	w.Header().Set("Content-Type", report.ContentTypes[format])
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// reportLocation 报告中时间显示使用的时区（动态配置的时区）
+func (s *Server) reportLocation() *time.Location {
+	loc, err := time.LoadLocation(s.configMgr.GetConfig().Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+func validReportPeriod(period string) bool {
+	return period == models.ReportDaily || period == models.ReportWeekly
+}
@@ -0,0 +1,139 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+
+	"code88reset/internal/auth"
+	"code88reset/internal/backup"
+	"code88reset/internal/config"
+	"code88reset/internal/models"
+	"code88reset/internal/report"
+	"code88reset/internal/storage"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	dir := t.TempDir()
+
+	store, err := storage.NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configMgr, err := config.NewDynamicConfigManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenStorage, err := token.NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenMgr := token.NewManager(tokenStorage, "http://127.0.0.1:0", store)
+	reports, err := report.NewService(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceTokens, err := auth.NewStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backupSvc := backup.NewService(tokenStorage, tokenMgr, store, configMgr, "test")
+
+	return NewServer(models.WebServerConfig{}, tokenMgr, configMgr, store, backupSvc, reports, serviceTokens, "admin", "test")
+}
+
+// TestOperationsAreServed 文档中的每个操作都有对应的路由，不会落到 404 兜底或方法不允许
+func TestOperationsAreServed(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	for _, op := range webapi.Operations {
+		target := apiV1Prefix + strings.TrimPrefix(samplePath(op.Path), "/")
+		req := httptest.NewRequest(op.Method, target, nil)
+		req.Header.Set("Authorization", "Bearer admin")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code == http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: method not allowed", op.Method, op.Path)
+			continue
+		}
+		if rec.Code != http.StatusNotFound {
+			continue
+		}
+		var envelope webapi.ErrorEnvelope
+		if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+			t.Errorf("%s %s: decode 404 response: %v", op.Method, op.Path, err)
+			continue
+		}
+		if code := envelope.Error.MessageCode; code == "web.not_found" || code == "web.unknown_operation" {
+			t.Errorf("%s %s: not routed (%s)", op.Method, op.Path, code)
+		}
+	}
+}
+
+// TestRouteScopesMatchOperations 服务令牌权限表中的每条路由都对应文档中的操作
+func TestRouteScopesMatchOperations(t *testing.T) {
+	for _, route := range routeScopes {
+		matched := false
+		for _, op := range webapi.Operations {
+			if op.Method != route.method {
+				continue
+			}
+			if ok, _ := path.Match(route.pattern, "/api"+samplePath(op.Path)); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			t.Errorf("routeScopes entry %s %s has no matching operation", route.method, route.pattern)
+		}
+	}
+
+	for _, op := range webapi.Operations {
+		if op.Public {
+			continue
+		}
+		req := httptest.NewRequest(op.Method, "/api"+samplePath(op.Path), nil)
+		if scope := requiredScope(req); scope != "" && auth.ValidateScopes([]string{scope}) != nil {
+			t.Errorf("%s %s: unknown scope %q", op.Method, op.Path, scope)
+		}
+	}
+}
+
+func TestResetTokenRejectsInvalidBody(t *testing.T) {
+	handler := newTestServer(t).Handler()
+
+	serve := func(body string) webapi.APIError {
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tokens/missing/reset", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var envelope webapi.ErrorEnvelope
+		if err := json.NewDecoder(rec.Body).Decode(&envelope); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return envelope.Error
+	}
+
+	if apiErr := serve(`{"reset_type":`); apiErr.Code != webapi.CodeInvalidJSON {
+		t.Errorf("malformed body: %+v", apiErr)
+	}
+	// 空请求体使用默认重置类型，继续执行到查找 Token
+	if apiErr := serve(""); apiErr.Code == webapi.CodeInvalidJSON {
+		t.Errorf("empty body should use defaults: %+v", apiErr)
+	}
+}
+
+// samplePath 将路径参数替换为不存在的 ID
+func samplePath(p string) string {
+	for _, param := range []string{"{id}", "{name}"} {
+		p = strings.ReplaceAll(p, param, "missing")
+	}
+	return p
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"code88reset/internal/config"
+	"code88reset/internal/models"
+	"code88reset/internal/reset"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// handleResetRules 列出内置重置规则及各调度项停用的规则
+func (s *Server) handleResetRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	cfg := s.configMgr.GetConfig()
+	writeJSON(w, http.StatusOK, webapi.ResetRulesResponse{
+		Rules: reset.LocalizedRules(localeOf(w)),
+		Disabled: map[string][]string{
+			"first":  cfg.FirstReset.DisabledRules,
+			"second": cfg.SecondReset.DisabledRules,
+		},
+	})
+}
+
+// resetWithSchedule 按对应调度项的阈值和规则配置重置单个 Token
+func (s *Server) resetWithSchedule(tokenID, resetType, runID string) (*models.Token, error) {
+	req := token.ScheduleResetRequest(s.configMgr.GetConfig(), resetType, runID)
+	return s.tokenManager.ResetTokenWithRequest(tokenID, req)
+}
+
+// extendResetDeadline 按待重置的 Token 数量延长响应写入截止时间
+//
+// 重置后会同步轮询核实结果，核实截止时间可能远超服务器的 WriteTimeout。每个 Token 最多重置两次，
+// 每次包含核实轮询以及查询、重置两次 API 请求。
+func (s *Server) extendResetDeadline(w http.ResponseWriter, tokens int) {
+	cfg := s.configMgr.GetConfig()
+	verify := time.Duration(cfg.ResetVerification.TimeoutSeconds) * time.Second
+	if verify <= 0 {
+		verify = reset.DefaultVerifyTimeout
+	}
+	request := time.Duration(cfg.HTTPClient.TimeoutSeconds) * time.Second
+	if request <= 0 {
+		request = config.DefaultHTTPTimeoutSeconds * time.Second
+	}
+
+	perToken := 2 * (verify + 2*request)
+	deadline := time.Now().Add(serverWriteTimeout + time.Duration(tokens)*perToken)
+	if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+		logger.Warn("延长响应写入截止时间失败: %v", err)
+	}
+}
@@ -2,40 +2,68 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"embed"
 	"fmt"
 	"io/fs"
+	"net"
 	"net/http"
+	"strconv"
 	"time"
 
+	"code88reset/internal/api"
+	"code88reset/internal/auth"
+	"code88reset/internal/backup"
 	"code88reset/internal/config"
 	"code88reset/internal/models"
+	"code88reset/internal/report"
 	"code88reset/internal/storage"
 	"code88reset/internal/token"
+	"code88reset/internal/webapi"
 	"code88reset/pkg/logger"
 )
 
 //go:embed static/*
 var staticFiles embed.FS
 
+// serverWriteTimeout 响应写入超时，同步执行重置的接口会按需延长
+const serverWriteTimeout = 30 * time.Second
+
 // Server Web 服务器
 type Server struct {
-	httpServer   *http.Server
-	tokenManager *token.Manager
-	configMgr    *config.DynamicConfigManager
-	storage      *storage.Storage
-	adminToken   string
-	version      string
+	httpServer     *http.Server
+	redirectServer *http.Server // 启用 HTTPS 时将 HTTP 请求重定向到 HTTPS
+	webCfg         models.WebServerConfig
+	trustedProxies []*net.IPNet
+	ipLimiter      *rateLimiter    // 按客户端 IP 限流
+	tokenLimiter   *rateLimiter    // 按令牌限流
+	cooldown       *actionCooldown // 手动重置全部、批量刷新的冷却时间
+	tokenManager   *token.Manager
+	configMgr      *config.DynamicConfigManager
+	storage        *storage.Storage
+	backupSvc      *backup.Service
+	reports        *report.Service
+	serviceTokens  *auth.Store
+	adminToken     string
+	version        string
 }
 
 // NewServer 创建 Web 服务器
-func NewServer(port int, tokenManager *token.Manager, configMgr *config.DynamicConfigManager, storage *storage.Storage, adminToken string, version string) *Server {
+func NewServer(webCfg models.WebServerConfig, tokenManager *token.Manager, configMgr *config.DynamicConfigManager, storage *storage.Storage, backupSvc *backup.Service, reports *report.Service, serviceTokens *auth.Store, adminToken string, version string) *Server {
 	s := &Server{
-		tokenManager: tokenManager,
-		configMgr:    configMgr,
-		storage:      storage,
-		adminToken:   adminToken,
-		version:      version,
+		webCfg:         webCfg,
+		trustedProxies: parseTrustedProxies(webCfg.TrustedProxies),
+		ipLimiter:      newRateLimiter(webCfg.RateLimitPerIP),
+		tokenLimiter:   newRateLimiter(webCfg.RateLimitPerToken),
+		cooldown:       newActionCooldown(time.Duration(webCfg.ActionCooldown) * time.Second),
+		tokenManager:   tokenManager,
+		configMgr:      configMgr,
+		storage:        storage,
+		backupSvc:      backupSvc,
+		reports:        reports,
+		serviceTokens:  serviceTokens,
+		adminToken:     adminToken,
+		version:        version,
 	}
 
 	// 创建路由
@@ -48,37 +76,113 @@ func NewServer(port int, tokenManager *token.Manager, configMgr *config.DynamicC
 	}
 	mux.Handle("/", http.FileServer(http.FS(staticFS)))
 
-	// API 路由
+	// API 路由：处理函数按旧版 /api 路径注册，/api/v1 映射到同一组处理函数
+	apiMux := http.NewServeMux()
+	apiMux.HandleFunc(apiPrefix, handleAPINotFound)
+	apiMux.HandleFunc("/api/version", s.handleVersion)
+	apiMux.HandleFunc("/api/status", s.withAuth(s.handleGetStatus))
+	apiMux.HandleFunc("/api/config", s.withAuth(s.handleConfig))
+	apiMux.HandleFunc("/api/tokens", s.withAuth(s.handleTokens))
+	apiMux.HandleFunc("/api/tokens/batch", s.withAuth(s.handleBatchAddTokens))
+	apiMux.HandleFunc("/api/tokens/export", s.withAuth(s.handleExportTokens))
+	apiMux.HandleFunc("/api/tokens/import", s.withAuth(s.handleImportTokens))
+	apiMux.HandleFunc("/api/tokens/refresh-all", s.withAuth(s.handleRefreshAllTokens))
+	apiMux.HandleFunc("/api/tokens/duplicates", s.withAuth(s.handleTokenDuplicates))
+	apiMux.HandleFunc("/api/tokens/duplicates/merge", s.withAuth(s.handleMergeDuplicates))
+	apiMux.HandleFunc("/api/tokens/", s.withAuth(s.handleTokenDetail))
+	apiMux.HandleFunc("/api/reset/trigger", s.withAuth(s.handleManualReset))
+	apiMux.HandleFunc("/api/reset/rules", s.withAuth(s.handleResetRules))
+	apiMux.HandleFunc("/api/system-logs", s.withAuth(s.handleSystemLogs))
+	apiMux.HandleFunc("/api/system-logs/export", s.withAuth(s.handleExportSystemLogs))
+	apiMux.HandleFunc("/api/reports", s.withAuth(s.handleReports))
+	apiMux.HandleFunc("/api/reports/", s.withAuth(s.handleReportDetail))
+	apiMux.HandleFunc("/api/service-tokens", s.withAuth(s.handleServiceTokens))
+	apiMux.HandleFunc("/api/service-tokens/", s.withAuth(s.handleServiceTokenDetail))
+	apiMux.HandleFunc("/api/backup", s.withAuth(s.handleBackup))
+	apiMux.HandleFunc("/api/backup/restore", s.withAuth(s.handleRestore))
+
 	mux.HandleFunc("/health", s.handleHealth)
-	mux.HandleFunc("/api/version", s.handleVersion)
-	mux.HandleFunc("/api/status", s.withAuth(s.handleGetStatus))
-	mux.HandleFunc("/api/config", s.withAuth(s.handleConfig))
-	mux.HandleFunc("/api/tokens", s.withAuth(s.handleTokens))
-	mux.HandleFunc("/api/tokens/batch", s.withAuth(s.handleBatchAddTokens))
-	mux.HandleFunc("/api/tokens/", s.withAuth(s.handleTokenDetail))
-	mux.HandleFunc("/api/reset/trigger", s.withAuth(s.handleManualReset))
-	mux.HandleFunc("/api/system-logs", s.withAuth(s.handleSystemLogs))
+	mux.Handle(apiV1Prefix+"openapi.json", withV1(http.HandlerFunc(s.handleOpenAPI)))
+	limited := s.withLimits(apiMux)
+	mux.Handle(apiV1Prefix, withV1(limited))
+	mux.Handle(apiPrefix, withDeprecation(webCfg.BasePath, limited)) // 旧版路由，已弃用
+
+	var handler http.Handler = mux
+	if webCfg.BasePath != "" {
+		handler = withBasePath(webCfg.BasePath, mux)
+	}
 
 	// 创建 HTTP 服务器
 	s.httpServer = &http.Server{
-		Addr:         fmt.Sprintf(":%d", port),
-		Handler:      s.withCORS(s.withLogging(mux)),
+		Addr:         net.JoinHostPort(webCfg.BindAddress, strconv.Itoa(webCfg.Port)),
+		Handler:      s.withSecurityHeaders(s.withCORS(s.withLogging(withLocale(handler)))),
 		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: serverWriteTimeout,
 		IdleTimeout:  120 * time.Second,
 	}
 
 	return s
 }
 
-// Start 启动 Web 服务器
+// Handler 返回包含全部路由和中间件的 HTTP 处理器
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// URL 返回本机访问管理界面的地址
+func (s *Server) URL() string {
+	scheme := "http"
+	if s.webCfg.TLSEnabled() {
+		scheme = "https"
+	}
+	host := s.webCfg.BindAddress
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s://%s%s/", scheme, net.JoinHostPort(host, strconv.Itoa(s.webCfg.Port)), s.webCfg.BasePath)
+}
+
+// Start 启动 Web 服务器，配置了证书时使用 HTTPS
 func (s *Server) Start() error {
+	var certs *certReloader
+	if s.webCfg.TLSEnabled() {
+		var err error
+		if certs, err = newCertReloader(s.webCfg.TLSCertFile, s.webCfg.TLSKeyFile); err != nil {
+			return err
+		}
+		s.httpServer.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+	}
+
 	logger.Info("========================================")
 	logger.Info("Web 管理服务器启动")
-	logger.Info("监听地址: %s", s.httpServer.Addr)
+	logger.Info("监听地址: %s (HTTPS=%v)", s.httpServer.Addr, certs != nil)
 	logger.Info("========================================")
 
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if certs != nil && s.webCfg.HTTPRedirectPort > 0 {
+		s.redirectServer = &http.Server{
+			Addr:         net.JoinHostPort(s.webCfg.BindAddress, strconv.Itoa(s.webCfg.HTTPRedirectPort)),
+			Handler:      httpsRedirectHandler(s.webCfg.Port),
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+		}
+		go func() {
+			logger.Info("HTTP 重定向监听地址: %s", s.redirectServer.Addr)
+			if err := s.redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("HTTP 重定向服务启动失败: %v", err)
+			}
+		}()
+	}
+
+	var err error
+	if certs != nil {
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("Web 服务器启动失败: %w", err)
 	}
 
@@ -88,28 +192,33 @@ func (s *Server) Start() error {
 // Stop 停止 Web 服务器
 func (s *Server) Stop(ctx context.Context) error {
 	logger.Info("正在停止 Web 服务器...")
+	if s.redirectServer != nil {
+		if err := s.redirectServer.Shutdown(ctx); err != nil {
+			logger.Warn("HTTP 重定向服务关闭失败: %v", err)
+		}
+	}
 	return s.httpServer.Shutdown(ctx)
 }
 
 // handleHealth 健康检查
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"status": "ok",
-		"time":   time.Now().Format(time.RFC3339),
+	writeJSON(w, http.StatusOK, webapi.HealthResponse{
+		Status: "ok",
+		Time:   time.Now().Format(time.RFC3339),
 	})
 }
 
 // handleVersion 获取版本号
 func (s *Server) handleVersion(w http.ResponseWriter, r *http.Request) {
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"version": s.version,
+	writeJSON(w, http.StatusOK, webapi.VersionResponse{
+		Version: s.version,
 	})
 }
 
 // handleGetStatus 获取系统状态
 func (s *Server) handleGetStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 		return
 	}
 
@@ -125,57 +234,24 @@ func (s *Server) handleGetStatus(w http.ResponseWriter, r *http.Request) {
 
 	// 计算下次重置时间
 	now := time.Now()
-	loc, _ := time.LoadLocation(cfg.Timezone)
-	nowInTZ := now.In(loc)
-
-	var nextResetTime time.Time
-	var nextResetType string
-
-	// 检查第一次重置
-	if cfg.FirstReset.Enabled {
-		firstToday := time.Date(nowInTZ.Year(), nowInTZ.Month(), nowInTZ.Day(),
-			cfg.FirstReset.Hour, cfg.FirstReset.Minute, 0, 0, loc)
-		if nowInTZ.Before(firstToday) {
-			nextResetTime = firstToday
-			nextResetType = "first"
-		}
-	}
+	nextResetTime, nextResetType := config.NextReset(cfg, now)
 
-	// 检查第二次重置
-	if cfg.SecondReset.Enabled {
-		secondToday := time.Date(nowInTZ.Year(), nowInTZ.Month(), nowInTZ.Day(),
-			cfg.SecondReset.Hour, cfg.SecondReset.Minute, 0, 0, loc)
-		if nowInTZ.Before(secondToday) {
-			if nextResetTime.IsZero() || secondToday.Before(nextResetTime) {
-				nextResetTime = secondToday
-				nextResetType = "second"
-			}
-		}
-	}
-
-	// 如果今天没有下次重置，计算明天的
-	if nextResetTime.IsZero() {
-		tomorrow := nowInTZ.AddDate(0, 0, 1)
-		if cfg.FirstReset.Enabled {
-			nextResetTime = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(),
-				cfg.FirstReset.Hour, cfg.FirstReset.Minute, 0, 0, loc)
-			nextResetType = "first"
-		} else if cfg.SecondReset.Enabled {
-			nextResetTime = time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(),
-				cfg.SecondReset.Hour, cfg.SecondReset.Minute, 0, 0, loc)
-			nextResetType = "second"
-		}
-	}
+	// 即将到期的订阅
+	expiring := s.tokenManager.ExpiringTokens(token.ExpiringWindowDays(cfg.ExpiryAlert))
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"current_time":     now.Format(time.RFC3339),
-		"timezone":         cfg.Timezone,
-		"next_reset_time":  nextResetTime.Format(time.RFC3339),
-		"next_reset_type":  nextResetType,
-		"total_tokens":     len(tokens),
-		"enabled_tokens":   enabledCount,
-		"first_reset":      cfg.FirstReset,
-		"second_reset":     cfg.SecondReset,
+	cacheStats := api.DefaultCache.Stats()
+	writeJSON(w, http.StatusOK, webapi.StatusResponse{
+		CurrentTime:    now,
+		Timezone:       cfg.Timezone,
+		NextResetTime:  nextResetTime,
+		NextResetType:  nextResetType,
+		TotalTokens:    len(tokens),
+		EnabledTokens:  enabledCount,
+		FirstReset:     cfg.FirstReset,
+		SecondReset:    cfg.SecondReset,
+		ExpiringTokens: expiring,
+		APICache:       &cacheStats,
+		APIEndpoint:    s.tokenManager.EndpointStatus(),
 	})
 }
 
@@ -187,33 +263,34 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPut:
 		s.handleUpdateConfig(w, r)
 	default:
-		writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
 	}
 }
 
 // handleGetConfig 获取配置
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
 	cfg := s.configMgr.GetConfig()
-	writeJSON(w, http.StatusOK, cfg)
+	writeJSON(w, http.StatusOK, maskConfig(cfg))
 }
 
 // handleUpdateConfig 更新配置
 func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
 	var newConfig models.DynamicConfig
 	if err := readJSON(r, &newConfig); err != nil {
-		writeError(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		writeInvalidJSON(w, err)
 		return
 	}
+	unmaskConfig(&newConfig, s.configMgr.GetConfig())
 
 	if err := s.configMgr.UpdateConfig(newConfig); err != nil {
-		writeError(w, http.StatusBadRequest, "Configuration update failed: "+err.Error())
+		writeError(w, http.StatusBadRequest, "web.config_update_failed", err)
 		return
 	}
 
 	logger.Info("配置已通过 Web API 更新")
-	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"success": true,
-		"message": "Configuration updated successfully",
-		"config":  newConfig,
+	writeJSON(w, http.StatusOK, webapi.ConfigUpdateResponse{
+		Success: true,
+		Message: tr(w, "web.config_updated"),
+		Config:  maskConfig(newConfig),
 	})
 }
@@ -0,0 +1,194 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"code88reset/internal/auth"
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/webapi"
+	"code88reset/pkg/logger"
+)
+
+// routeScopes 服务令牌可以访问的接口及所需权限（路径为映射后的 /api 路径），未列出的接口只允许管理员令牌
+var routeScopes = []struct {
+	method  string
+	pattern string
+	scope   string
+}{
+	{http.MethodGet, "/api/status", auth.ScopeStatusRead},
+	{http.MethodGet, "/api/reset/rules", auth.ScopeStatusRead},
+	{http.MethodGet, "/api/config", auth.ScopeConfigRead},
+	{http.MethodGet, "/api/tokens", auth.ScopeTokensRead},
+	{http.MethodGet, "/api/tokens/*", auth.ScopeTokensRead},
+	{http.MethodGet, "/api/tokens/*/usage", auth.ScopeTokensRead},
+	{http.MethodPut, "/api/tokens/*/refresh", auth.ScopeTokensRefresh},
+	{http.MethodPost, "/api/tokens/refresh-all", auth.ScopeTokensRefresh},
+	{http.MethodPut, "/api/tokens/*/reset", auth.ScopeResetTrigger},
+	{http.MethodPost, "/api/reset/trigger", auth.ScopeResetTrigger},
+	{http.MethodGet, "/api/system-logs", auth.ScopeLogsRead},
+	{http.MethodGet, "/api/system-logs/export", auth.ScopeLogsRead},
+	{http.MethodGet, "/api/reports", auth.ScopeReportsRead},
+	{http.MethodGet, "/api/reports/*", auth.ScopeReportsRead},
+}
+
+// requiredScope 请求所需的服务令牌权限，返回空字符串表示只允许管理员令牌
+func requiredScope(r *http.Request) string {
+	for _, route := range routeScopes {
+		if route.method != r.Method {
+			continue
+		}
+		if ok, _ := path.Match(route.pattern, r.URL.Path); ok {
+			return route.scope
+		}
+	}
+	return ""
+}
+
+// authorizeServiceToken 校验服务令牌及其权限，失败时写入错误响应并返回 nil
+func (s *Server) authorizeServiceToken(w http.ResponseWriter, r *http.Request, secret string) *models.ServiceToken {
+	if s.serviceTokens == nil {
+		writeError(w, http.StatusUnauthorized, "web.invalid_token")
+		return nil
+	}
+
+	st, err := s.serviceTokens.Authenticate(secret)
+	switch {
+	case errors.Is(err, auth.ErrTokenExpired):
+		writeErrorCode(w, http.StatusUnauthorized, webapi.CodeTokenExpired, nil, "web.token_expired")
+		return nil
+	case errors.Is(err, auth.ErrTokenRevoked):
+		writeErrorCode(w, http.StatusUnauthorized, webapi.CodeTokenRevoked, nil, "web.token_revoked")
+		return nil
+	case errors.Is(err, auth.ErrInvalidToken):
+		writeError(w, http.StatusUnauthorized, "web.invalid_token")
+		return nil
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "web.verify_token_failed", err)
+		return nil
+	}
+
+	scope := requiredScope(r)
+	if scope == "" {
+		writeError(w, http.StatusForbidden, "web.admin_token_required")
+		return nil
+	}
+	if !auth.HasScope(st, scope) {
+		writeErrorCode(w, http.StatusForbidden, webapi.CodeMissingScope,
+			map[string]interface{}{"required_scope": scope}, "web.missing_scope", scope)
+		return nil
+	}
+
+	logger.Debug("服务令牌 %s (%s) 访问 %s %s", st.Name, st.Prefix, r.Method, r.URL.Path)
+	return st
+}
+
+// publicServiceToken 去掉哈希后用于响应
+func publicServiceToken(t models.ServiceToken) *models.ServiceToken {
+	t.Hash = ""
+	return &t
+}
+
+// handleServiceTokens 服务令牌列表与创建
+func (s *Server) handleServiceTokens(w http.ResponseWriter, r *http.Request) {
+	if s.serviceTokens == nil {
+		writeError(w, http.StatusNotFound, "web.service_tokens_disabled")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		list := s.serviceTokens.List()
+		tokens := make([]models.ServiceToken, len(list))
+		for i, t := range list {
+			tokens[i] = *publicServiceToken(t)
+		}
+		writeJSON(w, http.StatusOK, webapi.ServiceTokenListResponse{
+			Tokens: tokens,
+			Count:  len(tokens),
+			Scopes: auth.Scopes,
+		})
+	case http.MethodPost:
+		s.handleCreateServiceToken(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+	}
+}
+
+// handleCreateServiceToken 创建服务令牌
+func (s *Server) handleCreateServiceToken(w http.ResponseWriter, r *http.Request) {
+	var req webapi.CreateServiceTokenRequest
+	if err := readJSON(r, &req); err != nil {
+		writeInvalidJSON(w, err)
+		return
+	}
+
+	expiresAt := req.ExpiresAt
+	if expiresAt == nil && req.ExpiresInDays != 0 {
+		if req.ExpiresInDays < 0 {
+			writeError(w, http.StatusBadRequest, "web.invalid_expires_in_days")
+			return
+		}
+		t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	created, secret, err := s.serviceTokens.Create(req.Name, req.Scopes, expiresAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "web.create_service_token_failed", err)
+		return
+	}
+
+	s.auditServiceToken(i18n.M("web.audit.service_token_created", created.Name, created.Prefix, strings.Join(created.Scopes, ","), s.clientIP(r)))
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, webapi.CreateServiceTokenResponse{
+		Success: true,
+		Message: tr(w, "web.service_token_created"),
+		Token:   publicServiceToken(*created),
+		Secret:  secret,
+	})
+}
+
+// handleServiceTokenDetail 吊销服务令牌
+func (s *Server) handleServiceTokenDetail(w http.ResponseWriter, r *http.Request) {
+	if s.serviceTokens == nil {
+		writeError(w, http.StatusNotFound, "web.service_tokens_disabled")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/service-tokens/")
+	revoked, err := s.serviceTokens.Revoke(id)
+	if errors.Is(err, auth.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "web.service_token_not_found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.revoke_service_token_failed", err)
+		return
+	}
+
+	s.auditServiceToken(i18n.M("web.audit.service_token_revoked", revoked.Name, revoked.Prefix, s.clientIP(r)))
+	writeJSON(w, http.StatusOK, webapi.ServiceTokenResponse{
+		Success: true,
+		Message: tr(w, "web.service_token_revoked"),
+		Token:   publicServiceToken(*revoked),
+	})
+}
+
+// auditServiceToken 记录服务令牌管理操作
+func (s *Server) auditServiceToken(msg i18n.Message) {
+	entry := models.SystemLog{Type: "audit"}
+	entry.SetMessage(msg)
+	logger.Info("%s", entry.Message)
+	if err := s.storage.AddSystemLogEntry(entry); err != nil {
+		logger.Error("写入审计日志失败: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package web
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"code88reset/pkg/logger"
+)
+
+// certCheckInterval 检查证书文件是否更新的最小间隔
+const certCheckInterval = 10 * time.Second
+
+// certReloader 在 TLS 握手时按需重新加载证书，证书续期后无需重启服务
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time // 已加载证书对应的文件修改时间（取证书和私钥中较晚者）
+	checkedAt time.Time
+	now       func() time.Time
+}
+
+// newCertReloader 加载证书和私钥，文件无效时返回错误
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, fmt.Errorf("启用 HTTPS 需要同时设置证书和私钥文件")
+	}
+	c := &certReloader{certFile: certFile, keyFile: keyFile, now: time.Now}
+	modTime, err := c.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.load(modTime); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetCertificate 供 tls.Config 使用
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if now.Sub(c.checkedAt) >= certCheckInterval {
+		c.checkedAt = now
+		if modTime, err := c.latestModTime(); err != nil {
+			logger.Warn("检查 TLS 证书文件失败，继续使用当前证书: %v", err)
+		} else if modTime.After(c.modTime) {
+			// 证书和私钥可能尚未全部写完，加载失败时保留旧证书，下次检查再试
+			if err := c.load(modTime); err != nil {
+				logger.Warn("重新加载 TLS 证书失败，继续使用当前证书: %v", err)
+			} else {
+				logger.Info("已重新加载 TLS 证书: %s", c.certFile)
+			}
+		}
+	}
+	return c.cert, nil
+}
+
+// load 加载证书，调用方需持有锁（初始化时除外）
+func (c *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("加载 TLS 证书失败: %w", err)
+	}
+	c.cert = &cert
+	c.modTime = modTime
+	return nil
+}
+
+func (c *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// httpsRedirectHandler 将 HTTP 请求重定向到同一主机的 HTTPS 端口
+func httpsRedirectHandler(httpsPort int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if httpsPort != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(httpsPort))
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
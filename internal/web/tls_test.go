@@ -0,0 +1,169 @@
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// writeTestCert 生成自签名证书写入 dir，返回证书和私钥路径
+func writeTestCert(t *testing.T, dir, commonName string, modTime time.Time) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("chtimes %s: %v", file, err)
+		}
+	}
+	return certFile, keyFile
+}
+
+func leafCommonName(t *testing.T, c *certReloader) string {
+	t.Helper()
+	cert, err := c.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return leaf.Subject.CommonName
+}
+
+func TestCertReloader_ReloadsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	certFile, keyFile := writeTestCert(t, dir, "old", base)
+
+	c, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader error = %v", err)
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	if got := leafCommonName(t, c); got != "old" {
+		t.Fatalf("initial certificate = %q", got)
+	}
+
+	writeTestCert(t, dir, "new", base.Add(time.Minute))
+	// 检查间隔内不重新读取文件
+	if got := leafCommonName(t, c); got != "old" {
+		t.Fatalf("certificate reloaded before check interval: %q", got)
+	}
+
+	now = now.Add(certCheckInterval)
+	if got := leafCommonName(t, c); got != "new" {
+		t.Fatalf("certificate not reloaded: %q", got)
+	}
+
+	// 写入一半的文件加载失败时继续使用当前证书
+	if err := os.WriteFile(keyFile, []byte("broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(keyFile, base.Add(2*time.Minute), base.Add(2*time.Minute))
+	now = now.Add(certCheckInterval)
+	if got := leafCommonName(t, c); got != "new" {
+		t.Fatalf("broken files should keep current certificate, got %q", got)
+	}
+
+	if _, err := newCertReloader(certFile, ""); err == nil {
+		t.Fatalf("expected error when key file is missing")
+	}
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		host string
+		port int
+		want string
+	}{
+		{"example.com", 8443, "https://example.com:8443/api/v1/status?x=1"},
+		{"example.com:8080", 443, "https://example.com/api/v1/status?x=1"},
+		{"[::1]:8080", 443, "https://[::1]/api/v1/status?x=1"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://"+tt.host+"/api/v1/status?x=1", nil)
+		rec := httptest.NewRecorder()
+		httpsRedirectHandler(tt.port).ServeHTTP(rec, req)
+		if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != tt.want {
+			t.Errorf("%s: got %d %q, want %q", tt.host, rec.Code, rec.Header().Get("Location"), tt.want)
+		}
+	}
+}
+
+func TestCORSAndSecurityHeaders(t *testing.T) {
+	s := &Server{webCfg: models.WebServerConfig{
+		AllowedOrigins: []string{"https://ci.example.com"},
+		HSTSMaxAge:     3600,
+	}}
+	handler := s.withSecurityHeaders(s.withCORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	serve := func(origin string) http.Header {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header()
+	}
+
+	if h := serve("https://ci.example.com"); h.Get("Access-Control-Allow-Origin") != "https://ci.example.com" {
+		t.Errorf("allowed origin not echoed: %v", h)
+	}
+	h := serve("https://evil.example.com")
+	if h.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unexpected CORS header for disallowed origin: %v", h)
+	}
+	if h.Get("X-Frame-Options") != "DENY" || h.Get("Content-Security-Policy") == "" || h.Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("missing security headers: %v", h)
+	}
+	if h.Get("Strict-Transport-Security") != "" {
+		t.Errorf("HSTS must not be sent over plain HTTP")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://localhost/", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Header().Get("Strict-Transport-Security") != "max-age=3600; includeSubDomains" {
+		t.Errorf("unexpected HSTS header %q", rec.Header().Get("Strict-Transport-Security"))
+	}
+}
@@ -0,0 +1,17 @@
+package web
+
+import (
+	"code88reset/internal/models"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+// addToken 添加 Token，并应用单独的上游设置
+func (s *Server) addToken(apiKey, name string, upstream webapi.UpstreamFields) (*models.Token, error) {
+	return s.tokenManager.AddTokenWithRequest(token.AddRequest{
+		APIKey:   apiKey,
+		Name:     name,
+		BaseURL:  upstream.BaseURL,
+		ProxyURL: upstream.ProxyURL,
+	})
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/token"
+	"code88reset/internal/webapi"
+)
+
+// 用量查询参数
+const (
+	defaultUsageRange  = 7 * 24 * time.Hour
+	defaultUsagePoints = 300
+	maxUsagePoints     = 2000
+)
+
+// handleTokenUsage 查询 Token 的用量时间序列（降采样）和按天汇总
+//
+// GET /api/tokens/{id}/usage?from=&to=&points=，from/to 支持 RFC3339 或 YYYY-MM-DD，默认最近 7 天。
+func (s *Server) handleTokenUsage(w http.ResponseWriter, r *http.Request, tokenID string) {
+	if _, err := s.tokenManager.GetToken(tokenID); err != nil {
+		writeTokenNotFound(w)
+		return
+	}
+
+	loc, err := time.LoadLocation(s.configMgr.GetConfig().Timezone)
+	if err != nil {
+		loc = time.Local
+	}
+
+	query := r.URL.Query()
+	to := time.Now()
+	if v := query.Get("to"); v != "" {
+		if to, err = parseUsageTime(v, loc, true); err != nil {
+			writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+			return
+		}
+	}
+	from := to.Add(-defaultUsageRange)
+	if v := query.Get("from"); v != "" {
+		if from, err = parseUsageTime(v, loc, false); err != nil {
+			writeError(w, http.StatusBadRequest, i18n.TextCode, err)
+			return
+		}
+	}
+	if from.After(to) {
+		writeError(w, http.StatusBadRequest, "web.invalid_usage_range")
+		return
+	}
+
+	maxPoints := defaultUsagePoints
+	if v := query.Get("points"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 2 || n > maxUsagePoints {
+			writeError(w, http.StatusBadRequest, "web.invalid_usage_points", maxUsagePoints)
+			return
+		}
+		maxPoints = n
+	}
+
+	points, err := s.tokenManager.QueryUsage(tokenID, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "web.load_usage_failed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webapi.UsageResponse{
+		TokenID:      tokenID,
+		From:         from.Format(time.RFC3339),
+		To:           to.Format(time.RFC3339),
+		TotalSamples: len(points),
+		Points:       token.DownsamplePoints(points, maxPoints),
+		Daily:        token.AggregateDaily(points, loc),
+	})
+}
+
+// parseUsageTime 解析 RFC3339 或 YYYY-MM-DD；日期作为结束时间时取当天结束
+func parseUsageTime(value string, loc *time.Location, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return time.Time{}, i18n.Errorf("web.invalid_time", strconv.Quote(value))
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
@@ -0,0 +1,72 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"code88reset/internal/webapi"
+)
+
+// 接口路径前缀
+const (
+	apiPrefix   = "/api/"
+	apiV1Prefix = "/api/v1/"
+)
+
+// v1ResponseWriter 标记 /api/v1 请求的响应，错误响应据此使用统一的错误信封
+type v1ResponseWriter struct {
+	http.ResponseWriter
+}
+
+// Unwrap 返回原始 ResponseWriter，供 http.ResponseController 使用
+func (w *v1ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// isV1 判断响应是否属于 /api/v1 请求（支持被其他中间件再次包装）
+func isV1(w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(*v1ResponseWriter); ok {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
+
+// withV1 将 /api/v1/xxx 映射到对应的 /api/xxx 处理函数，并启用统一的错误信封
+func withV1(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r2 := r.Clone(r.Context())
+		r2.URL.Path = apiPrefix + strings.TrimPrefix(r.URL.Path, apiV1Prefix)
+		r2.URL.RawPath = ""
+		handler.ServeHTTP(&v1ResponseWriter{ResponseWriter: w}, r2)
+	})
+}
+
+// withDeprecation 旧版 /api/xxx 路由保留为 /api/v1/xxx 的别名，响应头提示迁移（新地址包含 URL 前缀）
+func withDeprecation(basePath string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		successor := basePath + apiV1Prefix + strings.TrimPrefix(r.URL.Path, apiPrefix)
+		w.Header().Set("Deprecation", "true")
+		w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// handleAPINotFound 未知的接口路径
+func handleAPINotFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, http.StatusNotFound, "web.not_found")
+}
+
+// handleOpenAPI 返回 /api/v1 的 OpenAPI 文档（无需认证）
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "web.method_not_allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, webapi.OpenAPI(s.version, s.webCfg.BasePath+webapi.BasePath))
+}
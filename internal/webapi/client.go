@@ -0,0 +1,299 @@
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code88reset/internal/i18n"
+	"code88reset/internal/models"
+	"code88reset/internal/storage"
+)
+
+// DefaultTimeout 调用 Web 管理 API 的默认超时（批量刷新、重置会同步访问上游，留足时间）
+const DefaultTimeout = 5 * time.Minute
+
+// BasePath 客户端调用的版本化 API 路径前缀
+const BasePath = "/api/v1"
+
+// Error Web 管理 API 返回的错误
+type Error struct {
+	StatusCode  int
+	Code        string        // 错误码，旧版服务端可能为空
+	MessageCode string        // 消息码，旧版服务端和旧版路由为空
+	Message     string        // 按请求语言渲染的错误消息
+	RetryAfter  time.Duration // 429 响应的 Retry-After，其他情况为 0
+}
+
+// Error 实现 error 接口
+func (e *Error) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("HTTP %d: %s (retry after %s)", e.StatusCode, e.Message, e.RetryAfter)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Client Web 管理 API 客户端
+type Client struct {
+	BaseURL    string // 实例地址，如 http://127.0.0.1:8966
+	AdminToken string // WEB_ADMIN_TOKEN
+	HTTPClient *http.Client
+}
+
+// NewClient 创建 Web 管理 API 客户端
+func NewClient(baseURL, adminToken string) *Client {
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		AdminToken: adminToken,
+		HTTPClient: &http.Client{Timeout: DefaultTimeout},
+	}
+}
+
+// do 发送请求并解析 JSON 响应，非 2xx 状态码转换为 *Error
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("序列化请求失败: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.AdminToken)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", string(i18n.Default()))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求 %s 失败: %w", c.BaseURL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := parseError(resp.StatusCode, data)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			apiErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("解析响应失败: %w", err)
+	}
+	return nil
+}
+
+// parseError 从错误响应中提取错误码和说明文字
+func parseError(statusCode int, data []byte) *Error {
+	var body apiErrorBody
+	if err := json.Unmarshal(data, &body); err == nil {
+		var envelope APIError
+		if json.Unmarshal(body.Error, &envelope) == nil && envelope.Message != "" {
+			return &Error{StatusCode: statusCode, Code: envelope.Code, MessageCode: envelope.MessageCode, Message: envelope.Message}
+		}
+		var text string
+		if json.Unmarshal(body.Error, &text) == nil && text != "" {
+			return &Error{StatusCode: statusCode, Code: body.Code, Message: text}
+		}
+		if body.Message != "" {
+			return &Error{StatusCode: statusCode, Code: body.Code, Message: body.Message}
+		}
+	}
+
+	message := strings.TrimSpace(string(data))
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+	return &Error{StatusCode: statusCode, Message: message}
+}
+
+// tokenPath Token 详情接口路径
+func tokenPath(tokenID, action string) string {
+	path := BasePath + "/tokens/" + url.PathEscape(tokenID)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// Status 获取系统状态
+func (c *Client) Status() (*StatusResponse, error) {
+	var resp StatusResponse
+	if err := c.do(http.MethodGet, BasePath+"/status", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListTokens 获取 Token 列表
+func (c *Client) ListTokens() ([]*models.Token, error) {
+	var resp TokenListResponse
+	if err := c.do(http.MethodGet, BasePath+"/tokens", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tokens, nil
+}
+
+// GetToken 获取单个 Token
+func (c *Client) GetToken(tokenID string) (*models.Token, error) {
+	var t models.Token
+	if err := c.do(http.MethodGet, tokenPath(tokenID, ""), nil, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// AddToken 添加 Token
+func (c *Client) AddToken(req AddTokenRequest) (*models.Token, error) {
+	var resp TokenResponse
+	if err := c.do(http.MethodPost, BasePath+"/tokens", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Token, nil
+}
+
+// RevealAPIKey 获取 Token 的完整 API Key（服务端会记录审计日志）
+func (c *Client) RevealAPIKey(tokenID string) (string, error) {
+	var resp RevealAPIKeyResponse
+	if err := c.do(http.MethodPost, tokenPath(tokenID, "reveal"), nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.APIKey, nil
+}
+
+// DeleteToken 删除 Token
+func (c *Client) DeleteToken(tokenID string) error {
+	return c.do(http.MethodDelete, tokenPath(tokenID, ""), nil, nil)
+}
+
+// ToggleToken 切换 Token 启用状态
+func (c *Client) ToggleToken(tokenID string) (*models.Token, error) {
+	return c.tokenAction(tokenID, "toggle", nil)
+}
+
+// RefreshToken 刷新 Token 订阅信息
+func (c *Client) RefreshToken(tokenID string) (*models.Token, error) {
+	return c.tokenAction(tokenID, "refresh", nil)
+}
+
+// ResetToken 按调度项配置手动重置 Token
+func (c *Client) ResetToken(tokenID, resetType string) (*models.Token, error) {
+	return c.tokenAction(tokenID, "reset", ResetTokenRequest{ResetType: resetType})
+}
+
+// UpdateTokenSettings 更新 Token 的单独设置
+func (c *Client) UpdateTokenSettings(tokenID string, req TokenSettingsRequest) (*models.Token, error) {
+	return c.tokenAction(tokenID, "settings", req)
+}
+
+// tokenAction 调用 PUT /api/v1/tokens/{id}/{action}
+func (c *Client) tokenAction(tokenID, action string, body interface{}) (*models.Token, error) {
+	var resp TokenResponse
+	if err := c.do(http.MethodPut, tokenPath(tokenID, action), body, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Token, nil
+}
+
+// RefreshAll 立即刷新所有启用 Token 的订阅信息
+func (c *Client) RefreshAll() (*RefreshAllResponse, error) {
+	var resp RefreshAllResponse
+	if err := c.do(http.MethodPost, BasePath+"/tokens/refresh-all", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetConfig 获取动态配置
+func (c *Client) GetConfig() (*models.DynamicConfig, error) {
+	var cfg models.DynamicConfig
+	if err := c.do(http.MethodGet, BasePath+"/config", nil, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// UpdateConfig 更新动态配置，运行中的实例会立即应用
+func (c *Client) UpdateConfig(cfg models.DynamicConfig) error {
+	return c.do(http.MethodPut, BasePath+"/config", cfg, nil)
+}
+
+// SystemLogs 分页查询系统日志（按时间倒序），page 从 1 开始
+func (c *Client) SystemLogs(q storage.SystemLogQuery, page, pageSize int) (*SystemLogsResponse, error) {
+	values := url.Values{}
+	if len(q.Types) > 0 {
+		values.Set("type", strings.Join(q.Types, ","))
+	}
+	if q.TokenID != "" {
+		values.Set("token_id", q.TokenID)
+	}
+	if q.RunID != "" {
+		values.Set("run_id", q.RunID)
+	}
+	if q.Search != "" {
+		values.Set("q", q.Search)
+	}
+	if q.Since != nil {
+		values.Set("since", q.Since.Format(time.RFC3339Nano))
+	}
+	if q.Until != nil {
+		values.Set("until", q.Until.Format(time.RFC3339Nano))
+	}
+	if page > 0 {
+		values.Set("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		values.Set("page_size", strconv.Itoa(pageSize))
+	}
+
+	var resp SystemLogsResponse
+	if err := c.do(http.MethodGet, BasePath+"/system-logs?"+values.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListServiceTokens 列出服务令牌
+func (c *Client) ListServiceTokens() (*ServiceTokenListResponse, error) {
+	var resp ServiceTokenListResponse
+	if err := c.do(http.MethodGet, BasePath+"/service-tokens", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateServiceToken 创建服务令牌，响应中的 Secret 只返回这一次
+func (c *Client) CreateServiceToken(req CreateServiceTokenRequest) (*CreateServiceTokenResponse, error) {
+	var resp CreateServiceTokenResponse
+	if err := c.do(http.MethodPost, BasePath+"/service-tokens", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RevokeServiceToken 吊销服务令牌
+func (c *Client) RevokeServiceToken(id string) error {
+	return c.do(http.MethodDelete, BasePath+"/service-tokens/"+url.PathEscape(id), nil, nil)
+}
@@ -0,0 +1,53 @@
+package webapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_ParsesErrorResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("unexpected Authorization header: %q", r.Header.Get("Authorization"))
+		}
+		switch r.URL.Path {
+		case "/api/v1/tokens":
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(ErrorEnvelope{Error: APIError{Code: CodeDuplicateToken, Message: "Duplicate token: same key"}})
+		case "/api/v1/config":
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(DuplicateTokenResponse{Error: "legacy conflict", ExistingTokenID: "t1"})
+		case "/api/v1/status":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("bad gateway"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(ErrorResponse{Error: true, Message: "Token not found", Code: CodeTokenNotFound})
+		}
+	}))
+	defer ts.Close()
+
+	c := NewClient(ts.URL+"/", "secret")
+
+	tests := []struct {
+		name    string
+		call    func() error
+		status  int
+		code    string
+		message string
+	}{
+		{"envelope", func() error { _, err := c.AddToken(AddTokenRequest{APIKey: "k"}); return err }, http.StatusConflict, CodeDuplicateToken, "Duplicate token: same key"},
+		{"legacy string error", func() error { _, err := c.GetConfig(); return err }, http.StatusConflict, "", "legacy conflict"},
+		{"legacy boolean error", func() error { _, err := c.GetToken("missing"); return err }, http.StatusNotFound, CodeTokenNotFound, "Token not found"},
+		{"non-JSON body", func() error { _, err := c.Status(); return err }, http.StatusBadGateway, "", "bad gateway"},
+	}
+	for _, tt := range tests {
+		var apiErr *Error
+		if err := tt.call(); !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message {
+			t.Errorf("%s: got %v (%+v), want HTTP %d %s %q", tt.name, err, apiErr, tt.status, tt.code, tt.message)
+		}
+	}
+}
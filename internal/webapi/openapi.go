@@ -0,0 +1,332 @@
+package webapi
+
+import (
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"code88reset/internal/models"
+)
+
+// Param 查询参数说明
+type Param struct {
+	Name        string
+	Type        string // string / integer
+	Description string
+}
+
+// Operation 管理 API 的一个操作，用于生成 OpenAPI 文档
+type Operation struct {
+	Method      string
+	Path        string // 相对 /api/v1 的路径，路径参数写作 {id}
+	Summary     string
+	Tag         string
+	Query       []Param
+	Request     interface{} // 请求体类型的零值，nil 表示没有请求体
+	Response    interface{} // 成功响应类型的零值
+	ContentType string      // 非 JSON 响应（导出文件）的内容类型
+	Public      bool        // 无需认证
+}
+
+// systemLogFilters 系统日志查询、删除、导出共用的过滤参数
+var systemLogFilters = []Param{
+	{"type", "string", "日志类型，多个用逗号分隔"},
+	{"token_id", "string", "Token ID"},
+	{"run_id", "string", "重置批次 ID"},
+	{"q", "string", "关键字"},
+	{"since", "string", "起始时间，RFC3339 或 YYYY-MM-DD"},
+	{"until", "string", "结束时间，RFC3339 或 YYYY-MM-DD"},
+}
+
+// Operations /api/v1 的全部操作
+var Operations = []Operation{
+	{Method: "GET", Path: "/version", Summary: "获取版本号", Tag: "system", Response: VersionResponse{}, Public: true},
+	{Method: "GET", Path: "/openapi.json", Summary: "获取 OpenAPI 文档", Tag: "system", Response: map[string]interface{}{}, Public: true},
+	{Method: "GET", Path: "/status", Summary: "获取系统状态", Tag: "system", Response: StatusResponse{}},
+	{Method: "GET", Path: "/config", Summary: "获取动态配置", Tag: "config", Response: models.DynamicConfig{}},
+	{Method: "PUT", Path: "/config", Summary: "更新动态配置", Tag: "config", Request: models.DynamicConfig{}, Response: ConfigUpdateResponse{}},
+
+	{Method: "GET", Path: "/tokens", Summary: "获取 Token 列表", Tag: "tokens", Response: TokenListResponse{}},
+	{Method: "POST", Path: "/tokens", Summary: "添加 Token", Tag: "tokens", Request: AddTokenRequest{}, Response: TokenResponse{}},
+	{Method: "POST", Path: "/tokens/batch", Summary: "批量添加 Token", Tag: "tokens", Request: BatchAddTokensRequest{}, Response: BatchAddTokensResponse{}},
+	{Method: "POST", Path: "/tokens/export", Summary: "导出 Token 归档", Tag: "backup", Request: ExportTokensRequest{}, ContentType: "application/json"},
+	{Method: "POST", Path: "/tokens/import", Summary: "导入 Token 归档", Tag: "backup", Request: ImportTokensRequest{}, Response: ImportTokensResponse{}},
+	{Method: "POST", Path: "/tokens/refresh-all", Summary: "刷新所有启用 Token 的订阅信息", Tag: "tokens", Response: RefreshAllResponse{}},
+	{Method: "GET", Path: "/tokens/duplicates", Summary: "查找重复 Token", Tag: "tokens", Response: DuplicatesResponse{}},
+	{Method: "POST", Path: "/tokens/duplicates/merge", Summary: "合并重复 Token", Tag: "tokens", Request: MergeDuplicatesRequest{}, Response: TokenResponse{}},
+	{Method: "GET", Path: "/tokens/{id}", Summary: "获取单个 Token", Tag: "tokens", Response: models.Token{}},
+	{Method: "DELETE", Path: "/tokens/{id}", Summary: "删除 Token", Tag: "tokens", Response: MessageResponse{}},
+	{Method: "POST", Path: "/tokens/{id}/reveal", Summary: "查看完整 API Key（记录审计日志）", Tag: "tokens", Response: RevealAPIKeyResponse{}},
+	{Method: "GET", Path: "/tokens/{id}/usage", Summary: "获取用量历史", Tag: "tokens", Response: UsageResponse{}, Query: []Param{
+		{"from", "string", "起始时间，RFC3339 或 YYYY-MM-DD"},
+		{"to", "string", "结束时间，RFC3339 或 YYYY-MM-DD，默认当前时间"},
+		{"points", "integer", "最多返回的采样点数"},
+	}},
+	{Method: "PUT", Path: "/tokens/{id}/toggle", Summary: "切换启用状态", Tag: "tokens", Response: TokenResponse{}},
+	{Method: "PUT", Path: "/tokens/{id}/refresh", Summary: "刷新订阅信息", Tag: "tokens", Response: TokenResponse{}},
+	{Method: "PUT", Path: "/tokens/{id}/reset", Summary: "手动重置 Token", Tag: "reset", Request: ResetTokenRequest{}, Response: TokenResponse{}},
+	{Method: "PUT", Path: "/tokens/{id}/settings", Summary: "更新 Token 单独设置", Tag: "tokens", Request: TokenSettingsRequest{}, Response: TokenResponse{}},
+
+	{Method: "POST", Path: "/reset/trigger", Summary: "手动重置所有启用的 Token", Tag: "reset", Request: ResetTokenRequest{}, Response: ManualResetResponse{}},
+	{Method: "GET", Path: "/reset/rules", Summary: "获取重置规则", Tag: "reset", Response: ResetRulesResponse{}},
+
+	{Method: "GET", Path: "/system-logs", Summary: "分页查询系统日志", Tag: "logs", Response: SystemLogsResponse{}, Query: append([]Param{
+		{"page", "integer", "页码，从 1 开始"},
+		{"page_size", "integer", "每页条数"},
+	}, systemLogFilters...)},
+	{Method: "DELETE", Path: "/system-logs", Summary: "删除系统日志，不带过滤参数时全部清空", Tag: "logs", Response: DeleteSystemLogsResponse{}, Query: systemLogFilters},
+	{Method: "GET", Path: "/system-logs/export", Summary: "导出系统日志", Tag: "logs", ContentType: "text/csv", Query: append([]Param{
+		{"format", "string", "csv（默认）或 ndjson"},
+	}, systemLogFilters...)},
+
+	{Method: "GET", Path: "/reports", Summary: "列出重置报告", Tag: "reports", Response: ReportListResponse{}, Query: []Param{
+		{"period", "string", "daily 或 weekly，不传时列出全部"},
+	}},
+	{Method: "POST", Path: "/reports", Summary: "立即生成重置报告", Tag: "reports", Request: GenerateReportRequest{}, Response: GenerateReportResponse{}},
+	{Method: "GET", Path: "/reports/{name}", Summary: "获取重置报告", Tag: "reports", Response: models.ResetReport{}, Query: []Param{
+		{"format", "string", "json（默认）、markdown 或 html，文字按请求语言渲染"},
+	}},
+
+	{Method: "GET", Path: "/service-tokens", Summary: "列出服务令牌", Tag: "auth", Response: ServiceTokenListResponse{}},
+	{Method: "POST", Path: "/service-tokens", Summary: "创建服务令牌", Tag: "auth", Request: CreateServiceTokenRequest{}, Response: CreateServiceTokenResponse{}},
+	{Method: "DELETE", Path: "/service-tokens/{id}", Summary: "吊销服务令牌", Tag: "auth", Response: ServiceTokenResponse{}},
+
+	{Method: "POST", Path: "/backup", Summary: "生成完整备份", Tag: "backup", Request: BackupRequest{}, ContentType: "application/json"},
+	{Method: "POST", Path: "/backup/restore", Summary: "从备份恢复", Tag: "backup", Request: RestoreRequest{}, Response: RestoreResponse{}},
+}
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+// OpenAPI 根据 Operations 生成 OpenAPI 3 文档，serverURL 为 API 的访问路径（含 URL 前缀）
+func OpenAPI(version, serverURL string) map[string]interface{} {
+	b := newSchemaBuilder()
+	errorResponse := map[string]interface{}{
+		"description": "错误",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": b.schemaOf(ErrorEnvelope{})},
+		},
+	}
+	rateLimitedResponse := map[string]interface{}{
+		"description": "请求过于频繁或操作冷却中（rate_limited），按 Retry-After 秒数后重试",
+		"headers": map[string]interface{}{
+			"Retry-After": map[string]interface{}{"schema": map[string]interface{}{"type": "integer"}},
+		},
+		"content": errorResponse["content"],
+	}
+
+	paths := map[string]interface{}{}
+	for _, op := range Operations {
+		item, ok := paths[op.Path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[op.Path] = item
+		}
+
+		var params []interface{}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(op.Path, -1) {
+			params = append(params, map[string]interface{}{
+				"name": m[1], "in": "path", "required": true,
+				"schema": map[string]interface{}{"type": "string"},
+			})
+		}
+		for _, p := range op.Query {
+			params = append(params, map[string]interface{}{
+				"name": p.Name, "in": "query", "description": p.Description,
+				"schema": map[string]interface{}{"type": p.Type},
+			})
+		}
+
+		success := map[string]interface{}{"description": "成功"}
+		switch {
+		case op.ContentType != "":
+			success["description"] = "以附件形式返回的文件"
+			success["content"] = map[string]interface{}{
+				op.ContentType: map[string]interface{}{"schema": map[string]interface{}{"type": "string", "format": "binary"}},
+			}
+		case op.Response != nil:
+			success["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": b.schemaOf(op.Response)},
+			}
+		}
+
+		operation := map[string]interface{}{
+			"summary": op.Summary,
+			"tags":    []string{op.Tag},
+			"responses": map[string]interface{}{
+				"200":     success,
+				"default": errorResponse,
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.schemaOf(op.Request)},
+				},
+			}
+		}
+		if op.Public {
+			operation["security"] = []interface{}{}
+		} else {
+			operation["responses"].(map[string]interface{})["429"] = rateLimitedResponse
+		}
+		item[strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "88code Reset 管理 API",
+			"version": version,
+		},
+		"servers":  []interface{}{map[string]interface{}{"url": serverURL}},
+		"security": []interface{}{map[string]interface{}{"bearerAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]interface{}{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "WEB_ADMIN_TOKEN 或服务令牌（只能访问其权限覆盖的接口）",
+				},
+			},
+		},
+	}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaBuilder 通过反射把 Go 类型转换为 JSON Schema，具名结构体放入 components/schemas 并以 $ref 引用
+type schemaBuilder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		schemas: map[string]interface{}{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+func (b *schemaBuilder) schemaOf(v interface{}) map[string]interface{} {
+	return b.schemaFor(reflect.TypeOf(v))
+}
+
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schemaFor(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + b.register(t)}
+	}
+	return map[string]interface{}{}
+}
+
+// register 登记具名结构体，不同包中的同名类型加包名前缀区分
+func (b *schemaBuilder) register(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if _, taken := b.schemas[name]; taken {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	b.names[t] = name
+	b.schemas[name] = nil // 先占位，支持自引用类型
+	b.schemas[name] = b.structSchema(t)
+	return name
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	b.collectFields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectFields 按 encoding/json 的规则收集字段：展开匿名嵌入结构体，没有 omitempty 的字段视为必有
+func (b *schemaBuilder) collectFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			b.collectFields(ft, properties, required)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := b.schemaFor(f.Type)
+		if hasOption(opts, "string") {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		properties[name] = schema
+		if !hasOption(opts, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package webapi
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPI_RefsResolve(t *testing.T) {
+	data, err := json.Marshal(OpenAPI("test", BasePath))
+	if err != nil {
+		t.Fatalf("marshal spec: %v", err)
+	}
+
+	var spec struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]json.RawMessage `json:"properties"`
+				Required   []string                   `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("unmarshal spec: %v", err)
+	}
+
+	for _, op := range Operations {
+		if _, ok := spec.Paths[op.Path][strings.ToLower(op.Method)]; !ok {
+			t.Errorf("missing operation %s %s", op.Method, op.Path)
+		}
+	}
+
+	const prefix = "#/components/schemas/"
+	for _, ref := range strings.Split(string(data), `"$ref":"`)[1:] {
+		name := strings.TrimPrefix(ref[:strings.IndexByte(ref, '"')], prefix)
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("unresolved $ref %q", name)
+		}
+	}
+
+	// 嵌入的 UpstreamFields 展开到 AddTokenRequest，omitempty 字段不是必有字段
+	add := spec.Components.Schemas["AddTokenRequest"]
+	for _, field := range []string{"api_key", "name", "base_url", "proxy_url"} {
+		if _, ok := add.Properties[field]; !ok {
+			t.Errorf("AddTokenRequest missing property %q", field)
+		}
+	}
+	for _, field := range spec.Components.Schemas["APIError"].Required {
+		if field == "details" {
+			t.Errorf("details should be optional")
+		}
+	}
+	if !strings.Contains(string(spec.Components.Schemas["StatusResponse"].Properties["current_time"]), `"date-time"`) {
+		t.Errorf("time.Time should be a date-time string")
+	}
+}
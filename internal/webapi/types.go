@@ -0,0 +1,342 @@
+// Package webapi 定义 Web 管理 API（/api/*）的请求和响应类型，并提供调用这些接口的 Go 客户端
+//
+// Web 服务端的处理函数和命令行远程模式共用这里的类型，保证两端字段一致。
+package webapi
+
+import (
+	"encoding/json"
+	"time"
+
+	"code88reset/internal/api"
+	"code88reset/internal/backup"
+	"code88reset/internal/models"
+	"code88reset/internal/reset"
+	"code88reset/internal/token"
+)
+
+// 错误码，/api/v1 错误响应中的 code 字段，供程序判断错误类型
+const (
+	CodeInvalidRequest   = "invalid_request"
+	CodeInvalidJSON      = "invalid_json"
+	CodeUnauthorized     = "unauthorized"
+	CodeTokenExpired     = "token_expired"
+	CodeTokenRevoked     = "token_revoked"
+	CodeForbidden        = "forbidden"
+	CodeMissingScope     = "missing_scope"
+	CodeNotFound         = "not_found"
+	CodeTokenNotFound    = "token_not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeDuplicateToken   = "duplicate_token"
+	CodeRequestTooLarge  = "request_too_large"
+	CodeRateLimited      = "rate_limited"
+	CodeInternal         = "internal_error"
+)
+
+// APIError /api/v1 错误详情
+type APIError struct {
+	Code        string                 `json:"code"`
+	MessageCode string                 `json:"message_code,omitempty"` // 消息目录中的消息码，按语言渲染为 Message
+	Message     string                 `json:"message"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// ErrorEnvelope /api/v1 统一的错误响应
+type ErrorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+// ErrorResponse 旧版 /api 路由的错误响应
+type ErrorResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// HealthResponse GET /health 响应
+type HealthResponse struct {
+	Status string `json:"status"`
+	Time   string `json:"time"`
+}
+
+// VersionResponse GET /api/version 响应
+type VersionResponse struct {
+	Version string `json:"version"`
+}
+
+// MessageResponse 只包含结果说明的响应
+type MessageResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+// UpstreamFields 添加 Token 时可选的单独上游设置
+type UpstreamFields struct {
+	BaseURL  string `json:"base_url"`  // 兼容 88code 的自建后台地址，为空时使用全局地址
+	ProxyURL string `json:"proxy_url"` // 为空时使用全局代理设置
+}
+
+// AddTokenRequest POST /api/tokens 请求
+type AddTokenRequest struct {
+	APIKey string `json:"api_key"`
+	Name   string `json:"name"`
+	UpstreamFields
+}
+
+// BatchAddTokensRequest POST /api/tokens/batch 请求
+type BatchAddTokensRequest struct {
+	APIKeys        string `json:"api_keys"` // 多个 API Key，每行一个
+	Prefix         string `json:"prefix"`   // Token 名称前缀，可选
+	UpstreamFields        // 对本批次全部 Token 生效的上游设置，可选
+}
+
+// BatchAddResult 批量添加中单个 API Key 的结果
+type BatchAddResult struct {
+	APIKey            string        `json:"api_key"` // 遮蔽后的 API Key
+	Name              string        `json:"name"`
+	Success           bool          `json:"success"`
+	Duplicate         bool          `json:"duplicate,omitempty"`
+	ExistingTokenID   string        `json:"existing_token_id,omitempty"`
+	ExistingTokenName string        `json:"existing_token_name,omitempty"`
+	Error             string        `json:"error,omitempty"`
+	Token             *models.Token `json:"token,omitempty"`
+}
+
+// BatchAddTokensResponse POST /api/tokens/batch 响应
+type BatchAddTokensResponse struct {
+	Success        bool             `json:"success"`
+	Message        string           `json:"message"`
+	SuccessCount   int              `json:"success_count"`
+	DuplicateCount int              `json:"duplicate_count"`
+	FailCount      int              `json:"fail_count"`
+	Results        []BatchAddResult `json:"results"`
+}
+
+// DuplicateTokenResponse 旧版路由中添加的 Token 与已有 Token 重复时的 409 响应
+type DuplicateTokenResponse struct {
+	Error             string `json:"error"`
+	ExistingTokenID   string `json:"existing_token_id"`
+	ExistingTokenName string `json:"existing_token_name"`
+	Reason            string `json:"reason"`
+}
+
+// TokenListResponse GET /api/tokens 响应，API Key 均已遮蔽
+type TokenListResponse struct {
+	Tokens []*models.Token `json:"tokens"`
+	Count  int             `json:"count"`
+}
+
+// TokenResponse 单个 Token 操作（添加、切换、刷新、重置、更新设置）的响应，API Key 已遮蔽
+type TokenResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Token   *models.Token `json:"token"`
+}
+
+// RevealAPIKeyResponse POST /api/tokens/{id}/reveal 响应
+type RevealAPIKeyResponse struct {
+	TokenID  string `json:"token_id"`
+	APIKey   string `json:"api_key"`             // 完整 API Key
+	ProxyURL string `json:"proxy_url,omitempty"` // 含认证信息的完整代理地址，未单独设置时为空
+}
+
+// ResetTokenRequest PUT /api/tokens/{id}/reset 和 POST /api/reset/trigger 请求
+type ResetTokenRequest struct {
+	ResetType string `json:"reset_type"` // "first" or "second"
+}
+
+// TokenSettingsRequest PUT /api/tokens/{id}/settings 请求
+type TokenSettingsRequest struct {
+	LowCreditThreshold *float64 `json:"low_credit_threshold"` // 不传时不修改，<= 0 恢复使用全局阈值
+	BaseURL            *string  `json:"base_url"`             // 不传时不修改，空字符串恢复使用全局地址
+	ProxyURL           *string  `json:"proxy_url"`            // 不传时不修改，空字符串恢复使用全局代理
+}
+
+// RefreshAllResponse POST /api/tokens/refresh-all 响应
+type RefreshAllResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Summary *token.RefreshSummary `json:"summary"`
+}
+
+// ResetResult 批量重置中单个 Token 的结果
+type ResetResult struct {
+	TokenID string                   `json:"token_id"`
+	Name    string                   `json:"name"`
+	Success bool                     `json:"success"`
+	Code    string                   `json:"code,omitempty"` // 结果说明的消息码
+	Message string                   `json:"message"`
+	Before  float64                  `json:"before_credits,omitempty"`
+	After   float64                  `json:"after_credits,omitempty"`
+	Last    *models.TokenResetRecord `json:"last_reset,omitempty"`
+}
+
+// ManualResetResponse POST /api/reset/trigger 响应
+type ManualResetResponse struct {
+	Success      bool          `json:"success"`
+	RunID        string        `json:"run_id"`
+	ResetType    string        `json:"reset_type"`
+	Total        int           `json:"total"`
+	SuccessCount int           `json:"success_count"`
+	Results      []ResetResult `json:"results"`
+}
+
+// StatusResponse GET /api/status 响应
+type StatusResponse struct {
+	CurrentTime    time.Time             `json:"current_time"`
+	Timezone       string                `json:"timezone"`
+	NextResetTime  time.Time             `json:"next_reset_time"`
+	NextResetType  string                `json:"next_reset_type"`
+	TotalTokens    int                   `json:"total_tokens"`
+	EnabledTokens  int                   `json:"enabled_tokens"`
+	FirstReset     models.ResetConfig    `json:"first_reset"`
+	SecondReset    models.ResetConfig    `json:"second_reset"`
+	ExpiringTokens []token.ExpiringToken `json:"expiring_tokens"`
+	APICache       *api.CacheStats       `json:"api_cache,omitempty"` // 仅 Web 模式进程内有意义
+	APIEndpoint    api.EndpointStatus    `json:"api_endpoint"`
+}
+
+// ResetRulesResponse GET /api/reset/rules 响应
+type ResetRulesResponse struct {
+	Rules    []reset.RuleInfo    `json:"rules"`
+	Disabled map[string][]string `json:"disabled"` // 各调度项（first/second）停用的规则
+}
+
+// UsageResponse GET /api/tokens/{id}/usage 响应
+type UsageResponse struct {
+	TokenID      string              `json:"token_id"`
+	From         string              `json:"from"`
+	To           string              `json:"to"`
+	TotalSamples int                 `json:"total_samples"`
+	Points       []models.UsagePoint `json:"points"`
+	Daily        []models.DailyUsage `json:"daily"`
+}
+
+// DuplicatesResponse GET /api/tokens/duplicates 响应
+type DuplicatesResponse struct {
+	Groups []token.DuplicateGroup `json:"groups"`
+	Count  int                    `json:"count"`
+}
+
+// MergeDuplicatesRequest POST /api/tokens/duplicates/merge 请求
+type MergeDuplicatesRequest struct {
+	KeepID    string   `json:"keep_id"`
+	RemoveIDs []string `json:"remove_ids"` // 为空时合并同组全部重复项
+}
+
+// ExportTokensRequest POST /api/tokens/export 请求
+type ExportTokensRequest struct {
+	Passphrase     string `json:"passphrase"`
+	IncludeHistory *bool  `json:"include_history"` // 不传时包含重置历史
+}
+
+// ImportTokensRequest POST /api/tokens/import 请求
+type ImportTokensRequest struct {
+	Archive    json.RawMessage `json:"archive"`
+	Passphrase string          `json:"passphrase"`
+	Mode       string          `json:"mode"` // skip, overwrite, merge
+}
+
+// ImportTokensResponse POST /api/tokens/import 响应
+type ImportTokensResponse struct {
+	Success bool                      `json:"success"`
+	Message string                    `json:"message"`
+	Report  *backup.TokenImportResult `json:"report"`
+}
+
+// BackupRequest POST /api/backup 请求
+type BackupRequest struct {
+	Passphrase string `json:"passphrase"`
+}
+
+// RestoreRequest POST /api/backup/restore 请求
+type RestoreRequest struct {
+	Archive    json.RawMessage `json:"archive"`
+	Passphrase string          `json:"passphrase"`
+}
+
+// RestoreResponse POST /api/backup/restore 响应
+type RestoreResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	Result  *backup.RestoreResult `json:"result"`
+}
+
+// ConfigUpdateResponse PUT /api/config 响应
+type ConfigUpdateResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Config  models.DynamicConfig `json:"config"`
+}
+
+// SystemLogsResponse GET /api/system-logs 响应
+type SystemLogsResponse struct {
+	Logs       []models.SystemLog `json:"logs"`
+	Count      int                `json:"count"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	PageSize   int                `json:"page_size"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// DeleteSystemLogsResponse DELETE /api/system-logs 响应
+type DeleteSystemLogsResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Removed int    `json:"removed,omitempty"` // 带过滤条件时删除的条数
+}
+
+// ServiceTokenListResponse GET /api/service-tokens 响应
+type ServiceTokenListResponse struct {
+	Tokens []models.ServiceToken `json:"tokens"`
+	Count  int                   `json:"count"`
+	Scopes []string              `json:"scopes"` // 可授予的全部权限
+}
+
+// CreateServiceTokenRequest POST /api/service-tokens 请求
+type CreateServiceTokenRequest struct {
+	Name          string     `json:"name"`
+	Scopes        []string   `json:"scopes"`
+	ExpiresInDays int        `json:"expires_in_days"` // 有效天数，与 expires_at 都不传时永不过期
+	ExpiresAt     *time.Time `json:"expires_at"`
+}
+
+// CreateServiceTokenResponse POST /api/service-tokens 响应，明文令牌只返回这一次
+type CreateServiceTokenResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Token   *models.ServiceToken `json:"token"`
+	Secret  string               `json:"secret"`
+}
+
+// ServiceTokenResponse DELETE /api/service-tokens/{id} 响应
+type ServiceTokenResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Token   *models.ServiceToken `json:"token"`
+}
+
+// ReportListResponse GET /api/reports 响应
+type ReportListResponse struct {
+	Reports []models.ReportInfo `json:"reports"`
+	Count   int                 `json:"count"`
+}
+
+// GenerateReportRequest POST /api/reports 请求
+type GenerateReportRequest struct {
+	Period string `json:"period"` // daily 或 weekly
+}
+
+// GenerateReportResponse POST /api/reports 响应
+type GenerateReportResponse struct {
+	Success bool                `json:"success"`
+	Message string              `json:"message"`
+	Report  *models.ResetReport `json:"report"`
+}
+
+// apiErrorBody 解析错误响应：/api/v1 的 error 为对象，旧版路由为布尔值或说明文字
+type apiErrorBody struct {
+	Error   json.RawMessage `json:"error"`
+	Message string          `json:"message"`
+	Code    string          `json:"code"`
+}
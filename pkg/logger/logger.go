@@ -16,8 +16,13 @@ var (
 	DebugLog *log.Logger
 )
 
-// Init 初始化日志系统
+// Init 初始化日志系统，同时输出到文件和控制台
 func Init(logDir string) error {
+	return InitWithConsole(logDir, os.Stdout)
+}
+
+// InitWithConsole 初始化日志系统，console 为 nil 时只写入日志文件（命令行子命令使用，避免干扰输出）
+func InitWithConsole(logDir string, console io.Writer) error {
 	// 确保日志目录存在
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return fmt.Errorf("创建日志目录失败: %w", err)
@@ -34,7 +39,10 @@ func Init(logDir string) error {
 	}
 
 	// 同时输出到文件和控制台
-	multiWriter := io.MultiWriter(os.Stdout, file)
+	var multiWriter io.Writer = file
+	if console != nil {
+		multiWriter = io.MultiWriter(console, file)
+	}
 
 	// 初始化不同级别的日志记录器
 	InfoLog = log.New(multiWriter, "[INFO]  ", log.Ldate|log.Ltime|log.Lshortfile)